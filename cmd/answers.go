@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// answerRecorder keeps the answers typed during a wizard session, in the
+// order their questions were first asked
+type answerRecorder struct {
+	keys    []string
+	answers map[string]string
+}
+
+// record remembers a non-empty answer; an empty answer kept the value an
+// earlier answer to the same question set, so there is nothing new to keep
+func (r *answerRecorder) record(key, answer string) {
+	if answer == "" {
+		return
+	}
+	if r.answers == nil {
+		r.answers = make(map[string]string)
+	}
+	if _, ok := r.answers[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	r.answers[key] = answer
+}
+
+// save writes the recorded answers as a YAML answer file
+func (r *answerRecorder) save(path string) error {
+	document := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range r.keys {
+		document.Content = append(document.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: r.answers[key], Style: yaml.DoubleQuotedStyle},
+		)
+	}
+	data, err := yaml.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("encoding answers: %w", err)
+	}
+
+	header := "# Answers recorded by calculator-generator interactive.\n" +
+		"# Replay with: calculator-generator interactive --answers " + path + "\n"
+	if err := os.WriteFile(path, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("writing answer file: %w", err)
+	}
+	return nil
+}
+
+// loadAnswers reads a YAML answer file mapping question keys to answers
+func loadAnswers(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading answer file: %w", err)
+	}
+
+	var answers map[string]string
+	if err := yaml.Unmarshal(data, &answers); err != nil {
+		return nil, fmt.Errorf("parsing answer file %s: %w", path, err)
+	}
+	if answers == nil {
+		answers = make(map[string]string)
+	}
+	return answers, nil
+}
+
+// isTerminal reports whether a file is an interactive terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
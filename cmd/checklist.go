@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checklistItem is one toggle of a full-screen checklist
+type checklistItem struct {
+	key         string // answer key recorded for the item, such as "features.memory"
+	label       string
+	description string
+	note        func() string // dependency note shown after the description, may be nil
+	field       *bool
+	enable      func() // called whenever the item is switched on, may be nil
+}
+
+// checklist is a full-screen multi-select list moved through with the arrow
+// keys, with a preview pane under it that is refreshed after every change
+type checklist struct {
+	title   string
+	items   []checklistItem
+	preview func() []string
+	cursor  int
+	top     int // first item shown when the list is taller than the screen
+}
+
+// checklistHelp lists the keys a checklist understands
+const checklistHelp = "↑/↓ move · space toggle · a all · n none · enter accept · b back · q quit"
+
+// set switches an item on or off
+func (c *checklist) set(i int, value bool) {
+	*c.items[i].field = value
+	if value && c.items[i].enable != nil {
+		c.items[i].enable()
+	}
+}
+
+// handle applies a key press. It reports whether the selection is accepted,
+// and returns errWizardBack or a cancellation error for those keys.
+func (c *checklist) handle(k key) (bool, error) {
+	switch k {
+	case keyUp:
+		if c.cursor > 0 {
+			c.cursor--
+		}
+	case keyDown:
+		if c.cursor < len(c.items)-1 {
+			c.cursor++
+		}
+	case keyHome:
+		c.cursor = 0
+	case keyEnd:
+		c.cursor = len(c.items) - 1
+	case keyToggle:
+		c.set(c.cursor, !*c.items[c.cursor].field)
+	case keyAll, keyNone:
+		for i := range c.items {
+			c.set(i, k == keyAll)
+		}
+	case keyAccept:
+		return true, nil
+	case keyBack:
+		return false, errWizardBack
+	case keyCancel:
+		return false, fmt.Errorf("generation cancelled by user")
+	}
+	return false, nil
+}
+
+// render draws the checklist for a screen of the given size, scrolling the
+// list so the cursor stays visible
+func (c *checklist) render(width, height int) string {
+	preview := c.preview()
+	header := []string{c.title, checklistHelp, ""}
+	rows := height - len(header) - len(preview) - 1
+	if rows < 3 {
+		// Keep the list usable on short screens, at the preview's expense
+		rows = 3
+		preview = nil
+	}
+	if rows > len(c.items) {
+		rows = len(c.items)
+	}
+	if c.cursor < c.top {
+		c.top = c.cursor
+	}
+	if c.cursor >= c.top+rows {
+		c.top = c.cursor - rows + 1
+	}
+
+	labelWidth := 0
+	for _, item := range c.items {
+		if n := len([]rune(item.label)); n > labelWidth {
+			labelWidth = n
+		}
+	}
+
+	lines := header
+	for i := c.top; i < c.top+rows; i++ {
+		item := c.items[i]
+		pointer, box := "  ", "[ ]"
+		if i == c.cursor {
+			pointer = "▸ "
+		}
+		if *item.field {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s%s %-*s  %s", pointer, box, labelWidth, item.label, item.description)
+		if item.note != nil {
+			if note := item.note(); note != "" {
+				line += " · " + note
+			}
+		}
+		lines = append(lines, line)
+	}
+	if preview != nil {
+		lines = append(lines, "")
+		lines = append(lines, preview...)
+	}
+
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncate shortens a line to fit a screen width
+func truncate(line string, width int) string {
+	runes := []rune(line)
+	if len(runes) <= width || width < 1 {
+		return line
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// askChecklist shows a checklist full screen until the selection is accepted
+// and records every item's final answer
+func (w *wizard) askChecklist(c *checklist) error {
+	restore, err := w.terminal.raw()
+	if err != nil {
+		return err
+	}
+	w.terminal.enterScreen()
+	err = w.runChecklist(c)
+	w.terminal.leaveScreen()
+	restore()
+	if err != nil {
+		return err
+	}
+
+	var selected []string
+	for _, item := range c.items {
+		if *item.field {
+			selected = append(selected, item.label)
+		}
+		if w.recorder != nil {
+			w.recorder.record(item.key, yesNo(*item.field))
+		}
+	}
+	fmt.Fprintf(w.out, "✅ Selected: %s\n", listOrNone(selected))
+	return nil
+}
+
+// runChecklist redraws the checklist after every key press until it is done
+func (w *wizard) runChecklist(c *checklist) error {
+	for {
+		width, height := w.terminal.size()
+		w.terminal.draw(c.render(width, height))
+		k, err := w.terminal.readKey()
+		if err != nil {
+			return err
+		}
+		if done, err := c.handle(k); done || err != nil {
+			return err
+		}
+	}
+}
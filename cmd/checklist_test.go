@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"calculator-generator/internal"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestChecklistKeys(t *testing.T) {
+	config := internal.GetDefaultConfig()
+	numpy := &config.Libraries.UseNumpy
+	c := &checklist{
+		title: "Features",
+		items: []checklistItem{
+			{key: "features.memory", label: "Memory", field: &config.Features.Memory},
+			{key: "features.statistical", label: "Statistical", field: &config.Features.Statistical, enable: func() { *numpy = true }},
+		},
+		preview: requirementsPreview(&config),
+	}
+
+	for _, input := range []string{"\x1b[B", " "} {
+		if done, err := c.handle(parseKey([]byte(input))); done || err != nil {
+			t.Fatalf("handle(%q) = %v, %v", input, done, err)
+		}
+	}
+	if !config.Features.Statistical || !config.Libraries.UseNumpy || config.Features.Memory {
+		t.Errorf("toggling Statistical gave features %+v, libraries %+v", config.Features, config.Libraries)
+	}
+	if screen := c.render(80, 24); !strings.Contains(screen, "▸ [x] Statistical") || !strings.Contains(screen, "numpy>=1.21.0") {
+		t.Errorf("screen does not show the selection and its requirements:\n%s", screen)
+	}
+
+	if _, err := c.handle(parseKey([]byte("b"))); !errors.Is(err, errWizardBack) {
+		t.Errorf("b = %v, want errWizardBack", err)
+	}
+	if done, err := c.handle(parseKey([]byte("\r"))); !done || err != nil {
+		t.Errorf("enter = %v, %v; want the selection accepted", done, err)
+	}
+}
+
+func TestChecklistScrolls(t *testing.T) {
+	config := internal.GetDefaultConfig()
+	c := &checklist{title: "Features", preview: func() []string { return nil }}
+	for _, feature := range wizardFeatures(&config) {
+		c.items = append(c.items, checklistItem{label: feature.label, field: feature.field})
+	}
+
+	c.handle(keyEnd)
+	lines := strings.Split(c.render(80, 10), "\n")
+	if len(lines) > 10 {
+		t.Errorf("rendered %d lines on a 10 line screen", len(lines))
+	}
+	if last := lines[len(lines)-1]; !strings.Contains(last, "▸") || !strings.Contains(last, "Programming") {
+		t.Errorf("last line = %q, want the cursor on the last feature", last)
+	}
+}
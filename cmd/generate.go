@@ -3,6 +3,9 @@ package cmd
 import (
 	"calculator-generator/internal"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -22,8 +25,15 @@ Examples:
   calculator-generator generate --type basic
   calculator-generator generate --type scientific --output scientific_calc.py
   calculator-generator generate --type scientific --features "trigonometric,logarithmic,statistical"
-  calculator-generator generate --libraries "numpy,scipy,sympy" --features "plotting,linear-algebra"`,
+  calculator-generator generate --libraries "numpy,scipy,sympy" --features "plotting,linear-algebra"
+  calculator-generator generate --output-format json
+  calculator-generator generate --type scientific --python-version 3.9 --pin
+  calculator-generator generate --name mycalc --layout package --license BSD-3-Clause
+  calculator-generator generate --features data-analysis --libraries -scipy --explain`,
 	RunE: runGenerate,
+	// Validation issues are listed above the error, and main prints the error
+	SilenceUsage:  true,
+	SilenceErrors: true,
 }
 
 func init() {
@@ -37,22 +47,51 @@ func init() {
 	generateCmd.Flags().StringP("description", "d", "", "project description")
 
 	// Libraries
-	generateCmd.Flags().String("libraries", "", "comma-separated list of libraries (numpy,pandas,scipy,sympy,plotly)")
+	generateCmd.Flags().String("libraries", "", "comma-separated list of libraries (numpy,pandas,scipy,sympy,plotly); prefix one with - to turn it off")
 	generateCmd.Flags().Bool("math", true, "include math library")
 
 	// Features
 	generateCmd.Flags().String("features", "", "comma-separated list of features")
 	generateCmd.Flags().Bool("memory", false, "include memory functionality")
 	generateCmd.Flags().Bool("history", false, "include calculation history")
+	generateCmd.Flags().Bool("persist-memory", false, "save memory registers between sessions")
+	generateCmd.Flags().String("memory-file", "", "memory registers file (default ~/.<project>_memory.json)")
+	generateCmd.Flags().Bool("persist-history", true, "save and reload history between sessions")
+	generateCmd.Flags().String("history-file", "", "history file (default ~/.<project>_history.json)")
+	generateCmd.Flags().Int("history-size", 100, "maximum number of history entries kept")
 	generateCmd.Flags().Bool("interactive", true, "create interactive calculator")
 
 	// UI configuration
-	generateCmd.Flags().String("style", "cli", "UI style (cli, gui)")
-	generateCmd.Flags().String("theme", "light", "UI theme (light, dark, colorful)")
+	generateCmd.Flags().String("style", "cli", "UI style (cli, gui, web, tui, notebook, api)")
+	generateCmd.Flags().String("theme", "light", "UI theme (light, dark, colorful, high-contrast)")
+	generateCmd.Flags().String("theme-file", "", "YAML theme file (overrides --theme)")
+	generateCmd.Flags().String("keypad", "", "GUI keypad layout (basic, scientific, programmer, statistics); defaults to the calculator type")
+	generateCmd.Flags().String("keypad-file", "", "YAML GUI keypad layout file (overrides --keypad)")
+	generateCmd.Flags().String("window-size", "", "initial GUI window size as WIDTHxHEIGHT (default fits the keypad)")
+	generateCmd.Flags().String("min-window-size", "", "minimum GUI window size as WIDTHxHEIGHT")
+	generateCmd.Flags().Bool("resizable", true, "allow the GUI window to be resized")
+	generateCmd.Flags().Float64("font-scale", 1.0, "multiply GUI font sizes (0.5-4)")
+	generateCmd.Flags().String("display-font", "", "GUI display font family (default from the theme)")
+	generateCmd.Flags().StringArray("bind", nil, "GUI keyboard shortcut as KEYS=ACTION, e.g. Ctrl+M=memory_store (repeatable)")
+	generateCmd.Flags().String("announce", "", "GUI announce mode for screen readers: stdout or a log file path")
 	generateCmd.Flags().Int("precision", 10, "decimal precision (1-20)")
 	generateCmd.Flags().String("angle-unit", "degrees", "angle unit (degrees, radians)")
+	generateCmd.Flags().String("locale", internal.DefaultLocale, "locale for messages and number format (en-US, de-DE, fr-FR)")
 	generateCmd.Flags().Bool("show-help", true, "show help information")
 	generateCmd.Flags().Bool("show-banner", true, "show application banner")
+	generateCmd.Flags().String("output-format", "text", "result output format (text, json)")
+
+	// Project layout
+	generateCmd.Flags().String("layout", "script", "output layout: script (one file) or package (an installable project with pyproject.toml)")
+	generateCmd.Flags().String("license", "MIT", "license of a package layout (MIT, BSD-3-Clause, Apache-2.0, none)")
+
+	// Python dependencies
+	generateCmd.Flags().String("python-version", "", "target Python version such as 3.12; requirements get lower bounds that support it")
+	generateCmd.Flags().Bool("pin", false, "pin exact package versions for the target Python (default "+internal.DefaultPythonVersion+")")
+	generateCmd.Flags().Bool("constraints", false, "also write constraints.txt with exact package versions")
+
+	// Reporting
+	generateCmd.Flags().Bool("explain", false, "explain which features pulled in which libraries before generating")
 
 	// Bind flags to viper
 	viper.BindPFlag("type", generateCmd.Flags().Lookup("type"))
@@ -63,78 +102,253 @@ func init() {
 	viper.BindPFlag("features", generateCmd.Flags().Lookup("features"))
 	viper.BindPFlag("memory", generateCmd.Flags().Lookup("memory"))
 	viper.BindPFlag("history", generateCmd.Flags().Lookup("history"))
+	viper.BindPFlag("persist-memory", generateCmd.Flags().Lookup("persist-memory"))
+	viper.BindPFlag("memory-file", generateCmd.Flags().Lookup("memory-file"))
+	viper.BindPFlag("persist-history", generateCmd.Flags().Lookup("persist-history"))
+	viper.BindPFlag("history-file", generateCmd.Flags().Lookup("history-file"))
+	viper.BindPFlag("history-size", generateCmd.Flags().Lookup("history-size"))
 	viper.BindPFlag("interactive", generateCmd.Flags().Lookup("interactive"))
 	viper.BindPFlag("style", generateCmd.Flags().Lookup("style"))
 	viper.BindPFlag("theme", generateCmd.Flags().Lookup("theme"))
+	viper.BindPFlag("theme-file", generateCmd.Flags().Lookup("theme-file"))
+	viper.BindPFlag("keypad", generateCmd.Flags().Lookup("keypad"))
+	viper.BindPFlag("keypad-file", generateCmd.Flags().Lookup("keypad-file"))
+	viper.BindPFlag("window-size", generateCmd.Flags().Lookup("window-size"))
+	viper.BindPFlag("min-window-size", generateCmd.Flags().Lookup("min-window-size"))
+	viper.BindPFlag("resizable", generateCmd.Flags().Lookup("resizable"))
+	viper.BindPFlag("font-scale", generateCmd.Flags().Lookup("font-scale"))
+	viper.BindPFlag("display-font", generateCmd.Flags().Lookup("display-font"))
+	viper.BindPFlag("bind", generateCmd.Flags().Lookup("bind"))
+	viper.BindPFlag("announce", generateCmd.Flags().Lookup("announce"))
 	viper.BindPFlag("precision", generateCmd.Flags().Lookup("precision"))
 	viper.BindPFlag("angle-unit", generateCmd.Flags().Lookup("angle-unit"))
+	viper.BindPFlag("locale", generateCmd.Flags().Lookup("locale"))
 	viper.BindPFlag("show-help", generateCmd.Flags().Lookup("show-help"))
 	viper.BindPFlag("show-banner", generateCmd.Flags().Lookup("show-banner"))
+	viper.BindPFlag("output-format", generateCmd.Flags().Lookup("output-format"))
+	viper.BindPFlag("layout", generateCmd.Flags().Lookup("layout"))
+	viper.BindPFlag("license", generateCmd.Flags().Lookup("license"))
+	viper.BindPFlag("python-version", generateCmd.Flags().Lookup("python-version"))
+	viper.BindPFlag("pin", generateCmd.Flags().Lookup("pin"))
+	viper.BindPFlag("constraints", generateCmd.Flags().Lookup("constraints"))
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
+	config, decisions, err := configFromFlags()
+	if err != nil {
+		return err
+	}
+
+	if explain, _ := cmd.Flags().GetBool("explain"); explain {
+		explainLibraries(os.Stdout, decisions)
+	}
+
+	// Report every problem at once, and stop on errors
+	if err := reportIssues(os.Stderr, internal.ValidateConfig(config)); err != nil {
+		return err
+	}
+
+	// Generate calculator
+	generator := internal.NewGenerator(config)
+	if err := generator.Generate(); err != nil {
+		return fmt.Errorf("generation failed: %w", err)
+	}
+
+	printGenerated(config)
+	return nil
+}
+
+// configFromFlags builds the configuration from the generate flags and the
+// config file, with the libraries resolved from the features. The decisions
+// explain the resolved libraries.
+func configFromFlags() (internal.CalculatorConfig, []internal.LibraryDecision, error) {
 	// Get config from flags
 	getConfigFromFlags()
-	config := buildConfigFromFlags()
 
-	// Validate calculator type
+	// Start from the calculator type's features and libraries
+	var config internal.CalculatorConfig
 	calcType := viper.GetString("type")
 	switch calcType {
 	case "basic":
-		config.Type = internal.BasicCalculator
+		config = internal.GetDefaultConfig()
 	case "scientific":
 		config = internal.GetScientificConfig()
-		// Override with user flags
-		config = buildConfigFromFlags()
-		config.Type = internal.ScientificCalculator
 	default:
-		return fmt.Errorf("invalid calculator type: %s (must be 'basic' or 'scientific')", calcType)
+		return internal.CalculatorConfig{}, nil, fmt.Errorf("invalid calculator type: %s (must be 'basic' or 'scientific')", calcType)
 	}
 
-	// Apply libraries from flags
-	if err := applyLibrariesFromFlags(&config); err != nil {
-		return err
-	}
+	// Override with user flags
+	applyConfigFlags(&config)
 
 	// Apply features from flags
 	if err := applyFeaturesFromFlags(&config); err != nil {
-		return err
+		return internal.CalculatorConfig{}, nil, err
 	}
 
-	// Generate calculator
-	generator := internal.NewGenerator(config)
-	if err := generator.Generate(); err != nil {
-		return fmt.Errorf("generation failed: %w", err)
+	// Resolve the libraries the features need
+	choices, err := libraryChoicesFromFlags()
+	if err != nil {
+		return internal.CalculatorConfig{}, nil, err
+	}
+	config, decisions := internal.ResolveLibraries(config, choices)
+
+	// Load a custom theme
+	if themeFile := viper.GetString("theme-file"); themeFile != "" {
+		theme, err := internal.LoadThemeFile(themeFile)
+		if err != nil {
+			return internal.CalculatorConfig{}, nil, err
+		}
+		config.UI.CustomTheme = &theme
+	}
+
+	// Load a custom keypad layout
+	if keypadFile := viper.GetString("keypad-file"); keypadFile != "" {
+		keypad, err := internal.LoadKeypadFile(keypadFile)
+		if err != nil {
+			return internal.CalculatorConfig{}, nil, err
+		}
+		config.UI.CustomKeypad = &keypad
+	}
+
+	// GUI keyboard shortcuts
+	for _, value := range viper.GetStringSlice("bind") {
+		binding, err := internal.ParseKeyBinding(value)
+		if err != nil {
+			return internal.CalculatorConfig{}, nil, fmt.Errorf("invalid --bind: %w", err)
+		}
+		config.UI.Keymap = append(config.UI.Keymap, binding)
+	}
+
+	// GUI window sizes
+	if size := viper.GetString("window-size"); size != "" {
+		width, height, err := parseWindowSize(size)
+		if err != nil {
+			return internal.CalculatorConfig{}, nil, fmt.Errorf("invalid --window-size: %w", err)
+		}
+		config.UI.WindowWidth, config.UI.WindowHeight = width, height
+	}
+	if size := viper.GetString("min-window-size"); size != "" {
+		width, height, err := parseWindowSize(size)
+		if err != nil {
+			return internal.CalculatorConfig{}, nil, fmt.Errorf("invalid --min-window-size: %w", err)
+		}
+		config.UI.MinWindowWidth, config.UI.MinWindowHeight = width, height
+	}
+
+	return config, decisions, nil
+}
+
+// explainLibraries lists each library with the reason it is or is not part
+// of the calculator
+func explainLibraries(w io.Writer, decisions []internal.LibraryDecision) {
+	fmt.Fprintln(w, "📦 Libraries:")
+	for _, decision := range decisions {
+		needed := strings.Join(decision.RequiredBy, ", ")
+		var reason string
+		switch {
+		case decision.Enabled && needed != "":
+			reason = "needed by " + needed
+		case decision.Enabled && decision.Library == "math":
+			reason = "standard library, supplies sqrt, pi and e"
+		case decision.Enabled:
+			reason = "chosen, but no feature uses it"
+		case needed != "":
+			reason = "turned off, but needed by " + needed
+		case decision.Chosen:
+			reason = "turned off"
+		default:
+			reason = "not needed"
+		}
+
+		mark := "  "
+		if decision.Enabled {
+			mark = "✅"
+		}
+		fmt.Fprintf(w, "  %s %-7s %s\n", mark, decision.Library, reason)
+	}
+}
+
+// printGenerated describes the generated calculator and how to run it
+func printGenerated(config internal.CalculatorConfig) {
+	if config.Layout == "package" {
+		printGeneratedPackage(config)
+		return
 	}
 
 	// Success message
 	fmt.Printf("✅ Calculator generated successfully!\n")
 	fmt.Printf("📁 Output file: %s\n", config.OutputFile)
 
-	if config.UI.Style == "gui" {
+	switch config.UI.Style {
+	case "gui":
 		fmt.Printf("🖥️  Type: Desktop GUI Calculator\n")
-	} else {
+	case "web":
+		fmt.Printf("🌐 Type: Browser Calculator\n")
+	case "tui":
+		fmt.Printf("🖥️  Type: Terminal UI Calculator\n")
+	case "api":
+		fmt.Printf("🔌 Type: HTTP/JSON API Service\n")
+	case "notebook":
+		fmt.Printf("📓 Type: IPython/Jupyter Extension\n")
+		fmt.Printf("📓 Notebook: %s\n", strings.TrimSuffix(config.OutputFile, ".py")+".ipynb")
+	default:
 		fmt.Printf("💻 Type: Command Line Calculator\n")
 	}
 
 	if hasExternalLibraries(config) {
 		fmt.Printf("📦 Requirements file: requirements.txt\n")
-		fmt.Printf("💡 Install dependencies with: pip install -r requirements.txt\n")
+		if config.Dependencies.Constraints {
+			fmt.Printf("📦 Constraints file: constraints.txt\n")
+			fmt.Printf("💡 Install dependencies with: pip install -r requirements.txt -c constraints.txt\n")
+		} else {
+			fmt.Printf("💡 Install dependencies with: pip install -r requirements.txt\n")
+		}
 	}
 
-	if config.UI.Style == "gui" {
+	switch config.UI.Style {
+	case "gui":
 		fmt.Printf("🚀 Run GUI with: python %s\n", config.OutputFile)
 		fmt.Printf("💡 Note: Tkinter is included with Python (no additional install needed)\n")
-	} else {
+	case "web":
+		fmt.Printf("🚀 Run with: python %s, then open http://127.0.0.1:8000\n", config.OutputFile)
+	case "tui":
+		fmt.Printf("🚀 Run with: python %s\n", config.OutputFile)
+		fmt.Printf("💡 Note: On Windows install curses support with: pip install windows-curses\n")
+	case "api":
+		fmt.Printf("🚀 Run with: python %s, then POST to http://127.0.0.1:8080/evaluate\n", config.OutputFile)
+	case "notebook":
+		fmt.Printf("🚀 In Jupyter, next to %s run: %%load_ext %s\n", config.OutputFile, strings.TrimSuffix(filepath.Base(config.OutputFile), ".py"))
+	default:
 		fmt.Printf("🚀 Run with: python %s\n", config.OutputFile)
 	}
-
-	return nil
 }
 
-func buildConfigFromFlags() internal.CalculatorConfig {
-	config := internal.GetDefaultConfig()
+// printGeneratedPackage describes the generated project and how to install it
+func printGeneratedPackage(config internal.CalculatorConfig) {
+	dir := internal.ProjectDir(config)
+	command := internal.ProjectCommand(config)
+	files, _ := internal.NewPackageGenerator(config).Files()
+
+	fmt.Printf("✅ Calculator project generated successfully!\n")
+	fmt.Printf("📁 Project directory: %s (%d files)\n", dir, len(files))
+	for _, file := range files {
+		fmt.Printf("   %s\n", file.Path)
+	}
+	// A bare name would make pip look the project up on PyPI
+	target := dir
+	if !filepath.IsAbs(target) && !strings.HasPrefix(target, ".") {
+		target = "." + string(filepath.Separator) + target
+	}
+	fmt.Printf("🚀 Install with: pip install %s\n", target)
+	fmt.Printf("🚀 Then run: %s\n", command)
+	if config.UI.Style == "gui" {
+		fmt.Printf("🖥️  Desktop calculator: %s-gui\n", command)
+	}
+}
 
+// applyConfigFlags overrides the type's configuration with the flags. The
+// switches the scientific type turns on only change when they are set.
+func applyConfigFlags(config *internal.CalculatorConfig) {
 	// Basic information
 	config.ProjectName = viper.GetString("name")
 	config.Author = viper.GetString("author")
@@ -153,52 +367,73 @@ func buildConfigFromFlags() internal.CalculatorConfig {
 	// General settings
 	config.Interactive = viper.GetBool("interactive")
 
-	// Library settings
-	config.Libraries.UseMath = viper.GetBool("math")
-
 	// Feature settings
-	config.Features.Memory = viper.GetBool("memory")
-	config.Features.History = viper.GetBool("history")
+	if viper.IsSet("memory") {
+		config.Features.Memory = viper.GetBool("memory")
+	}
+	if viper.IsSet("history") {
+		config.Features.History = viper.GetBool("history")
+	}
+
+	// Storage settings
+	config.Storage.PersistMemory = viper.GetBool("persist-memory")
+	config.Storage.MemoryFile = viper.GetString("memory-file")
+	config.Storage.PersistHistory = viper.GetBool("persist-history")
+	config.Storage.HistoryFile = viper.GetString("history-file")
+	config.Storage.HistoryMaxEntries = viper.GetInt("history-size")
 
 	// UI settings
 	config.UI.Style = viper.GetString("style")
 	config.UI.Theme = viper.GetString("theme")
+	config.UI.Keypad = viper.GetString("keypad")
+	config.UI.Resizable = viper.GetBool("resizable")
+	config.UI.FontScale = viper.GetFloat64("font-scale")
+	config.UI.DisplayFont = viper.GetString("display-font")
+	config.UI.Announce = viper.GetString("announce")
 	config.UI.Precision = viper.GetInt("precision")
 	config.UI.AngleUnit = viper.GetString("angle-unit")
+	config.UI.Locale = viper.GetString("locale")
 	config.UI.ShowHelp = viper.GetBool("show-help")
 	config.UI.ShowBanner = viper.GetBool("show-banner")
+	config.UI.OutputFormat = viper.GetString("output-format")
+
+	// Project layout
+	config.Layout = viper.GetString("layout")
+	config.License = viper.GetString("license")
 
-	return config
+	// Dependency settings
+	config.Dependencies.PythonVersion = viper.GetString("python-version")
+	config.Dependencies.Pin = viper.GetBool("pin")
+	config.Dependencies.Constraints = viper.GetBool("constraints")
 }
 
-func applyLibrariesFromFlags(config *internal.CalculatorConfig) error {
+// libraryChoicesFromFlags collects the libraries --libraries and --math turn
+// on or off explicitly. A library prefixed with - in --libraries is off.
+func libraryChoicesFromFlags() (internal.LibraryChoices, error) {
+	choices := internal.LibraryChoices{}
+	if viper.IsSet("math") {
+		choices["math"] = viper.GetBool("math")
+	}
+
 	librariesStr := viper.GetString("libraries")
 	if librariesStr == "" {
-		return nil
+		return choices, nil
 	}
 
 	libraries := strings.Split(librariesStr, ",")
 	for _, lib := range libraries {
 		lib = strings.TrimSpace(strings.ToLower(lib))
+		enable := !strings.HasPrefix(lib, "-")
+		lib = strings.TrimPrefix(lib, "-")
 		switch lib {
-		case "numpy":
-			config.Libraries.UseNumpy = true
-		case "pandas":
-			config.Libraries.UsePandas = true
-		case "scipy":
-			config.Libraries.UseScipy = true
-		case "sympy":
-			config.Libraries.UseSympy = true
-		case "plotly":
-			config.Libraries.UsePlotly = true
-		case "math":
-			config.Libraries.UseMath = true
+		case "numpy", "pandas", "scipy", "sympy", "plotly", "math":
+			choices[lib] = enable
 		default:
-			return fmt.Errorf("unknown library: %s", lib)
+			return nil, fmt.Errorf("unknown library: %s", lib)
 		}
 	}
 
-	return nil
+	return choices, nil
 }
 
 func applyFeaturesFromFlags(config *internal.CalculatorConfig) error {
@@ -215,42 +450,30 @@ func applyFeaturesFromFlags(config *internal.CalculatorConfig) error {
 			config.Features.BasicArithmetic = true
 		case "trigonometric", "trig":
 			config.Features.Trigonometric = true
-			config.Libraries.UseMath = true
 		case "logarithmic", "log":
 			config.Features.Logarithmic = true
-			config.Libraries.UseMath = true
 		case "exponential", "exp":
 			config.Features.Exponential = true
-			config.Libraries.UseMath = true
 		case "statistical", "stats":
 			config.Features.Statistical = true
-			config.Libraries.UseNumpy = true
 		case "linear-algebra", "linalg":
 			config.Features.LinearAlgebra = true
-			config.Libraries.UseNumpy = true
 		case "calculus":
 			config.Features.Calculus = true
-			config.Libraries.UseSympy = true
 		case "plotting", "plot":
 			config.Features.Plotting = true
-			config.Libraries.UsePlotly = true
 		case "unit-conversion", "units":
 			config.Features.UnitConversion = true
 		case "complex-numbers", "complex":
 			config.Features.ComplexNumbers = true
 		case "equation-solver", "solver":
 			config.Features.EquationSolver = true
-			config.Libraries.UseSympy = true
 		case "matrix-operations", "matrix":
 			config.Features.MatrixOperations = true
-			config.Libraries.UseNumpy = true
 		case "data-analysis", "data":
 			config.Features.DataAnalysis = true
-			config.Libraries.UsePandas = true
-			config.Libraries.UseNumpy = true
 		case "graphing", "graph":
 			config.Features.Graphing = true
-			config.Libraries.UsePlotly = true
 		case "programming", "prog":
 			config.Features.Programming = true
 		case "memory", "mem":
@@ -272,3 +495,12 @@ func hasExternalLibraries(config internal.CalculatorConfig) bool {
 		config.Libraries.UseSympy ||
 		config.Libraries.UsePlotly
 }
+
+// parseWindowSize parses a WIDTHxHEIGHT size such as 480x720
+func parseWindowSize(size string) (int, int, error) {
+	var width, height int
+	if _, err := fmt.Sscanf(strings.ToLower(size), "%dx%d", &width, &height); err != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("%q is not a size such as 480x720", size)
+	}
+	return width, height, nil
+}
@@ -5,7 +5,6 @@ import (
 	"calculator-generator/internal"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -22,60 +21,81 @@ and what features you'd like to include. It's perfect for beginners or when you
 want to explore all available options.
 
 The wizard will guide you through:
-- Calculator type selection
+- Project information and calculator type
 - Feature selection
 - Library dependencies
-- UI customization
-- Output configuration`,
+- Interface, display, locale and theme
+- GUI window and keyboard settings
+- Memory and history storage
+- Project layout, license and Python dependencies
+- Output configuration
+
+Type 'back' at any prompt to return to the previous step. The wizard ends on a
+summary where any section can be edited before generating.
+
+The wizard needs a terminal. Scripts can replay a session from a YAML answer
+file that maps question keys to answers; questions without an answer keep
+their defaults. Record a session to get a starting point.
+
+Examples:
+  calculator-generator interactive --record answers.yaml
+  calculator-generator interactive --answers answers.yaml`,
 	RunE: runInteractive,
 }
 
 func init() {
 	rootCmd.AddCommand(interactiveCmd)
+
+	interactiveCmd.Flags().String("answers", "", "replay the answers of a YAML answer file instead of asking")
+	interactiveCmd.Flags().String("record", "", "save the answers of this session to a YAML answer file")
 }
 
 func runInteractive(cmd *cobra.Command, args []string) error {
-	reader := bufio.NewReader(os.Stdin)
-
-	fmt.Println("🔢 Welcome to the Calculator Generator Interactive Wizard!")
-	fmt.Println("This wizard will help you create a customized Python calculator.")
-	fmt.Println()
-
-	config := internal.GetDefaultConfig()
-
-	// Project Information
-	if err := askProjectInfo(reader, &config); err != nil {
-		return err
+	answersFile, _ := cmd.Flags().GetString("answers")
+	recordFile, _ := cmd.Flags().GetString("record")
+	if answersFile != "" && recordFile != "" {
+		return fmt.Errorf("--answers and --record cannot be used together")
 	}
 
-	// Calculator Type
-	if err := askCalculatorType(reader, &config); err != nil {
-		return err
-	}
-
-	// Features Selection
-	if err := askFeatures(reader, &config); err != nil {
-		return err
+	w := newWizard(os.Stdout)
+	if answersFile != "" {
+		answers, err := loadAnswers(answersFile)
+		if err != nil {
+			return err
+		}
+		w.answers = answers
+	} else {
+		if !isTerminal(os.Stdin) {
+			return fmt.Errorf("the interactive wizard needs a terminal; use --answers FILE to run it from a script")
+		}
+		w.reader = bufio.NewReader(os.Stdin)
+		if term, err := openTerminal(os.Stdin, os.Stdout, w.reader); err == nil {
+			w.terminal = term
+		}
+		w.pager = func(text string) error { return page(text, os.Stdout) }
+		if recordFile != "" {
+			w.recorder = &answerRecorder{}
+		}
 	}
 
-	// Libraries Selection
-	if err := askLibraries(reader, &config); err != nil {
-		return err
-	}
+	fmt.Println("🔢 Welcome to the Calculator Generator Interactive Wizard!")
+	fmt.Println("This wizard will help you create a customized Python calculator.")
+	fmt.Println("Press Enter to keep the current value, or type 'back' to return to the previous step.")
+	fmt.Println()
 
-	// UI Configuration
-	if err := askUIConfig(reader, &config); err != nil {
+	if err := w.run(); err != nil {
 		return err
 	}
+	config := w.config
 
-	// Output Configuration
-	if err := askOutputConfig(reader, &config); err != nil {
-		return err
+	if unused := w.unusedAnswers(); len(unused) > 0 {
+		fmt.Printf("⚠️  Answers not used by any question: %s\n", strings.Join(unused, ", "))
 	}
-
-	// Summary and Confirmation
-	if err := showSummaryAndConfirm(reader, &config); err != nil {
-		return err
+	if w.recorder != nil {
+		if err := w.recorder.save(recordFile); err != nil {
+			return err
+		}
+		fmt.Printf("📝 Answers recorded to: %s\n", recordFile)
 	}
 
 	// Generate the calculator
@@ -84,426 +104,682 @@ func runInteractive(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("generation failed: %w", err)
 	}
 
-	// Success message
-	fmt.Printf("✅ Calculator generated successfully!\n")
-	fmt.Printf("📁 Output file: %s\n", config.OutputFile)
-
-	if config.UI.Style == "gui" {
-		fmt.Printf("🖥️  Type: Desktop GUI Calculator\n")
-	} else {
-		fmt.Printf("💻 Type: Command Line Calculator\n")
-	}
-
-	if hasExternalLibrariesInteractive(config) {
-		fmt.Printf("📦 Requirements file: requirements.txt\n")
-		fmt.Printf("💡 Install dependencies with: pip install -r requirements.txt\n")
-	}
-
-	if config.UI.Style == "gui" {
-		fmt.Printf("🚀 Run GUI with: python %s\n", config.OutputFile)
-		fmt.Printf("💡 Note: Tkinter is included with Python (no additional install needed)\n")
-	} else {
-		fmt.Printf("🚀 Run with: python %s\n", config.OutputFile)
-	}
-
+	printGenerated(config)
 	return nil
 }
 
-func askProjectInfo(reader *bufio.Reader, config *internal.CalculatorConfig) error {
-	fmt.Println("📋 Project Information")
-	fmt.Println("======================")
+// wizardSteps returns the wizard's steps in the order they are asked
+func wizardSteps() []wizardStep {
+	return []wizardStep{
+		{title: "📋 Project Information", ask: askProjectInfo, summary: summarizeProjectInfo},
+		{title: "🔢 Calculator Type", ask: askCalculatorType, summary: summarizeCalculatorType},
+		{title: "🚀 Features Selection", ask: askFeatures, summary: summarizeFeatures},
+		{title: "📚 Library Dependencies", ask: askLibraries, summary: summarizeLibraries},
+		{title: "🎨 User Interface Configuration", ask: askUIConfig, summary: summarizeUIConfig},
+		{title: "🌍 Display, Locale and Theme", ask: askDisplayConfig, summary: summarizeDisplayConfig},
+		{title: "🖥️ GUI Window and Keyboard", applies: isGUIConfig, ask: askGUIConfig, summary: summarizeGUIConfig},
+		{title: "💾 Memory and History Storage", applies: hasStorage, ask: askStorageConfig, summary: summarizeStorageConfig},
+		{title: "📦 Packaging and Dependencies", ask: askPackagingConfig, summary: summarizePackagingConfig},
+		{title: "📁 Output Configuration", ask: askOutputConfig, summary: summarizeOutputConfig},
+	}
+}
 
-	// Project name
-	fmt.Printf("Project name [%s]: ", config.ProjectName)
-	name, err := reader.ReadString('\n')
-	if err != nil {
+func askProjectInfo(w *wizard) error {
+	if err := w.askText("project.name", "Project name", &w.config.ProjectName); err != nil {
 		return err
 	}
-	name = strings.TrimSpace(name)
-	if name != "" {
-		config.ProjectName = name
-	}
-
-	// Author
-	fmt.Printf("Author name [%s]: ", config.Author)
-	author, err := reader.ReadString('\n')
-	if err != nil {
+	if err := w.askText("project.author", "Author name", &w.config.Author); err != nil {
 		return err
 	}
-	author = strings.TrimSpace(author)
-	if author != "" {
-		config.Author = author
+	return w.askText("project.description", "Description", &w.config.Description)
+}
+
+func summarizeProjectInfo(config internal.CalculatorConfig) []string {
+	return []string{
+		"Project Name: " + config.ProjectName,
+		"Author: " + config.Author,
+		"Description: " + config.Description,
 	}
+}
 
-	// Description
-	fmt.Printf("Description [%s]: ", config.Description)
-	description, err := reader.ReadString('\n')
+func askCalculatorType(w *wizard) error {
+	calcType := string(w.config.Type)
+	err := w.askChoice("type", "Calculator type", []wizardOption{
+		{value: "basic", label: "Basic Calculator", description: "Simple arithmetic operations (+, -, *, /, etc.)", aliases: []string{"b"}},
+		{value: "scientific", label: "Scientific Calculator", description: "Advanced mathematical functions", aliases: []string{"s"}},
+	}, &calcType)
 	if err != nil {
 		return err
 	}
-	description = strings.TrimSpace(description)
-	if description != "" {
-		config.Description = description
-	}
 
-	fmt.Println()
+	// Changing the type starts from that type's features and the libraries
+	// they need
+	if internal.CalculatorType(calcType) != w.config.Type {
+		defaults := internal.GetDefaultConfig()
+		if calcType == "scientific" {
+			defaults, _ = internal.ResolveLibraries(internal.GetScientificConfig(), nil)
+		}
+		w.config.Type = defaults.Type
+		w.config.Libraries = defaults.Libraries
+		w.config.Features = defaults.Features
+	}
+	fmt.Fprintf(w.out, "✅ %s calculator selected\n", calcType)
 	return nil
 }
 
-func askCalculatorType(reader *bufio.Reader, config *internal.CalculatorConfig) error {
-	fmt.Println("🔢 Calculator Type")
-	fmt.Println("==================")
-	fmt.Println("1. Basic Calculator - Simple arithmetic operations (+, -, *, /, etc.)")
-	fmt.Println("2. Scientific Calculator - Advanced mathematical functions")
-	fmt.Println()
+func summarizeCalculatorType(config internal.CalculatorConfig) []string {
+	return []string{"Type: " + string(config.Type)}
+}
 
-	for {
-		fmt.Print("Choose calculator type [1-2]: ")
-		choice, err := reader.ReadString('\n')
-		if err != nil {
-			return err
-		}
-		choice = strings.TrimSpace(choice)
+// wizardFeature is a feature toggle and the libraries it needs
+type wizardFeature struct {
+	name        string
+	label       string
+	description string
+	field       *bool
+	libraries   []string
+}
 
-		switch choice {
-		case "1", "basic", "b":
-			config.Type = internal.BasicCalculator
-			fmt.Println("✅ Basic calculator selected")
-			fmt.Println()
-			return nil
-		case "2", "scientific", "s":
-			config.Type = internal.ScientificCalculator
-			// Apply scientific defaults
-			scientificConfig := internal.GetScientificConfig()
-			config.Libraries = scientificConfig.Libraries
-			config.Features = scientificConfig.Features
-			fmt.Println("✅ Scientific calculator selected")
-			fmt.Println()
-			return nil
-		default:
-			fmt.Println("❌ Invalid choice. Please enter 1 or 2.")
-		}
-	}
+// wizardFeatures lists every feature of the configuration
+func wizardFeatures(config *internal.CalculatorConfig) []wizardFeature {
+	features := &config.Features
+	list := []wizardFeature{
+		{"basic-arithmetic", "Basic Arithmetic", "+, -, *, /, % and powers", &features.BasicArithmetic, nil},
+		{"memory", "Memory", "Store and recall values", &features.Memory, nil},
+		{"history", "History", "Keep track of calculations", &features.History, nil},
+		{"trigonometric", "Trigonometric", "sin, cos, tan functions", &features.Trigonometric, nil},
+		{"logarithmic", "Logarithmic", "log, ln functions", &features.Logarithmic, nil},
+		{"exponential", "Exponential", "exp and power functions", &features.Exponential, nil},
+		{"statistical", "Statistical", "mean, median, std dev", &features.Statistical, nil},
+		{"linear-algebra", "Linear Algebra", "Matrix operations", &features.LinearAlgebra, nil},
+		{"calculus", "Calculus", "Derivatives and integrals", &features.Calculus, nil},
+		{"plotting", "Plotting", "Create graphs and charts", &features.Plotting, nil},
+		{"unit-conversion", "Unit Conversion", "Convert between units", &features.UnitConversion, nil},
+		{"complex-numbers", "Complex Numbers", "Complex number arithmetic", &features.ComplexNumbers, nil},
+		{"equation-solver", "Equation Solver", "Solve algebraic equations", &features.EquationSolver, nil},
+		{"matrix-operations", "Matrix Operations", "Matrix arithmetic and decompositions", &features.MatrixOperations, nil},
+		{"data-analysis", "Data Analysis", "Advanced data manipulation", &features.DataAnalysis, nil},
+		{"graphing", "Graphing", "Function graphing", &features.Graphing, nil},
+		{"programming", "Programming", "Hex, binary and bitwise operations", &features.Programming, nil},
+	}
+	// The libraries come from the generator's feature requirements
+	for i := range list {
+		list[i].libraries = internal.FeatureLibraries(strings.ReplaceAll(list[i].name, "-", "_"))
+	}
+	return list
 }
 
-func askFeatures(reader *bufio.Reader, config *internal.CalculatorConfig) error {
-	fmt.Println("🚀 Features Selection")
-	fmt.Println("====================")
+// wizardLibrary is a library toggle
+type wizardLibrary struct {
+	name        string
+	label       string
+	description string
+	field       *bool
+}
 
-	features := []struct {
-		name        string
-		description string
-		field       *bool
-		dependency  string
-	}{
-		{"Memory", "Store and recall values", &config.Features.Memory, ""},
-		{"History", "Keep track of calculations", &config.Features.History, ""},
-		{"Trigonometric", "sin, cos, tan functions", &config.Features.Trigonometric, "math"},
-		{"Logarithmic", "log, ln functions", &config.Features.Logarithmic, "math"},
-		{"Statistical", "mean, median, std dev", &config.Features.Statistical, "numpy"},
-		{"Linear Algebra", "Matrix operations", &config.Features.LinearAlgebra, "numpy"},
-		{"Plotting", "Create graphs and charts", &config.Features.Plotting, "plotly"},
-		{"Equation Solver", "Solve algebraic equations", &config.Features.EquationSolver, "sympy"},
-		{"Complex Numbers", "Complex number arithmetic", &config.Features.ComplexNumbers, ""},
-		{"Unit Conversion", "Convert between units", &config.Features.UnitConversion, ""},
+// wizardLibraries lists every library of the configuration
+func wizardLibraries(config *internal.CalculatorConfig) []wizardLibrary {
+	libraries := &config.Libraries
+	return []wizardLibrary{
+		{"math", "math", "Python's standard math module", &libraries.UseMath},
+		{"numpy", "NumPy", "Numerical computing library", &libraries.UseNumpy},
+		{"pandas", "Pandas", "Data analysis and manipulation", &libraries.UsePandas},
+		{"scipy", "SciPy", "Scientific computing functions", &libraries.UseScipy},
+		{"sympy", "SymPy", "Symbolic mathematics", &libraries.UseSympy},
+		{"plotly", "Plotly", "Interactive plotting library", &libraries.UsePlotly},
 	}
+}
 
-	for _, feature := range features {
-		current := "no"
-		if *feature.field {
-			current = "yes"
+func askFeatures(w *wizard) error {
+	libraries := make(map[string]*bool)
+	for _, lib := range wizardLibraries(&w.config) {
+		libraries[lib.name] = lib.field
+	}
+
+	if w.terminal != nil {
+		c := &checklist{title: "🚀 Features Selection", preview: requirementsPreview(&w.config)}
+		for _, feature := range wizardFeatures(&w.config) {
+			feature := feature
+			item := checklistItem{
+				key:         "features." + feature.name,
+				label:       feature.label,
+				description: feature.description,
+				field:       feature.field,
+				enable: func() {
+					for _, name := range feature.libraries {
+						*libraries[name] = true
+					}
+				},
+			}
+			if len(feature.libraries) > 0 {
+				note := "needs " + strings.Join(feature.libraries, ", ")
+				item.note = func() string { return note }
+			}
+			c.items = append(c.items, item)
 		}
+		return w.askChecklist(c)
+	}
 
-		dependency := ""
-		if feature.dependency != "" {
-			dependency = fmt.Sprintf(" (requires %s)", feature.dependency)
+	for _, feature := range wizardFeatures(&w.config) {
+		label := fmt.Sprintf("Include %s - %s", feature.label, feature.description)
+		if len(feature.libraries) > 0 {
+			label += fmt.Sprintf(" (requires %s)", strings.Join(feature.libraries, ", "))
 		}
-
-		fmt.Printf("Include %s - %s%s? [y/N] (current: %s): ",
-			feature.name, feature.description, dependency, current)
-
-		choice, err := reader.ReadString('\n')
-		if err != nil {
+		if err := w.askBool("features."+feature.name, label, feature.field); err != nil {
 			return err
 		}
-		choice = strings.TrimSpace(strings.ToLower(choice))
-
-		if choice == "y" || choice == "yes" {
-			*feature.field = true
-			// Enable required libraries
-			switch feature.dependency {
-			case "numpy":
-				config.Libraries.UseNumpy = true
-			case "plotly":
-				config.Libraries.UsePlotly = true
-			case "sympy":
-				config.Libraries.UseSympy = true
-			case "math":
-				config.Libraries.UseMath = true
+
+		// Enable required libraries
+		if *feature.field {
+			for _, name := range feature.libraries {
+				*libraries[name] = true
 			}
-		} else if choice == "n" || choice == "no" {
-			*feature.field = false
 		}
-		// If empty, keep current value
 	}
-
-	fmt.Println()
 	return nil
 }
 
-func askLibraries(reader *bufio.Reader, config *internal.CalculatorConfig) error {
-	fmt.Println("📚 Library Dependencies")
-	fmt.Println("=======================")
-	fmt.Println("Additional libraries can provide more functionality:")
-
-	libraries := []struct {
-		name        string
-		description string
-		field       *bool
-	}{
-		{"NumPy", "Numerical computing library", &config.Libraries.UseNumpy},
-		{"Pandas", "Data analysis and manipulation", &config.Libraries.UsePandas},
-		{"SciPy", "Scientific computing functions", &config.Libraries.UseScipy},
-		{"SymPy", "Symbolic mathematics", &config.Libraries.UseSympy},
-		{"Plotly", "Interactive plotting library", &config.Libraries.UsePlotly},
+func summarizeFeatures(config internal.CalculatorConfig) []string {
+	var enabled []string
+	for _, feature := range wizardFeatures(&config) {
+		if *feature.field {
+			enabled = append(enabled, feature.name)
+		}
 	}
+	return []string{"Features: " + listOrNone(enabled)}
+}
 
-	for _, lib := range libraries {
-		current := "no"
-		if *lib.field {
-			current = "yes"
+func askLibraries(w *wizard) error {
+	if w.terminal != nil {
+		c := &checklist{title: "📚 Library Dependencies", preview: requirementsPreview(&w.config)}
+		for _, lib := range wizardLibraries(&w.config) {
+			name := lib.name
+			c.items = append(c.items, checklistItem{
+				key:         "libraries." + lib.name,
+				label:       lib.label,
+				description: lib.description,
+				field:       lib.field,
+				note:        func() string { return libraryUsers(w.config, name) },
+			})
 		}
+		return w.askChecklist(c)
+	}
 
-		fmt.Printf("Include %s - %s? [y/N] (current: %s): ",
-			lib.name, lib.description, current)
-
-		choice, err := reader.ReadString('\n')
-		if err != nil {
+	fmt.Fprintln(w.out, "Additional libraries can provide more functionality:")
+	for _, lib := range wizardLibraries(&w.config) {
+		if err := w.askBool("libraries."+lib.name, fmt.Sprintf("Include %s - %s", lib.label, lib.description), lib.field); err != nil {
 			return err
 		}
-		choice = strings.TrimSpace(strings.ToLower(choice))
+	}
+	return nil
+}
 
-		if choice == "y" || choice == "yes" {
-			*lib.field = true
-		} else if choice == "n" || choice == "no" {
-			*lib.field = false
+// libraryUsers notes which enabled features need a library
+func libraryUsers(config internal.CalculatorConfig, library string) string {
+	var users []string
+	for _, feature := range wizardFeatures(&config) {
+		for _, name := range feature.libraries {
+			if name == library && *feature.field {
+				users = append(users, feature.name)
+			}
 		}
 	}
+	if len(users) == 0 {
+		return ""
+	}
+	return "needed by " + strings.Join(users, ", ")
+}
 
-	fmt.Println()
-	return nil
+// requirementsPreview returns the preview pane of the feature and library
+// checklists: the requirements.txt the configuration would get and its
+// estimated install size
+func requirementsPreview(config *internal.CalculatorConfig) func() []string {
+	return func() []string {
+		generator := internal.NewGenerator(*config)
+		requirements := generator.Requirements()
+		if !hasExternalLibraries(*config) {
+			return append([]string{"requirements.txt: nothing to install, the standard library is enough"}, indent(requirements)...)
+		}
+		header := fmt.Sprintf("requirements.txt: about %d MB to install", generator.EstimatedInstallSize())
+		return append([]string{header}, indent(requirements)...)
+	}
 }
 
-func askUIConfig(reader *bufio.Reader, config *internal.CalculatorConfig) error {
-	fmt.Println("🎨 User Interface Configuration")
-	fmt.Println("==============================")
+// indent prefixes lines with two spaces
+func indent(lines []string) []string {
+	indented := make([]string, len(lines))
+	for i, line := range lines {
+		indented[i] = "  " + line
+	}
+	return indented
+}
 
-	// Interactive mode
-	fmt.Printf("Create interactive calculator? [Y/n] (current: %v): ", config.Interactive)
-	choice, err := reader.ReadString('\n')
+func summarizeLibraries(config internal.CalculatorConfig) []string {
+	var enabled []string
+	for _, lib := range wizardLibraries(&config) {
+		if *lib.field {
+			enabled = append(enabled, lib.name)
+		}
+	}
+	return []string{"Libraries: " + listOrNone(enabled)}
+}
+
+func askUIConfig(w *wizard) error {
+	ui := &w.config.UI
+	err := w.askChoice("ui.style", "Calculator interface", []wizardOption{
+		{value: "cli", label: "Command Line Interface (CLI)", aliases: []string{"command", "c"}},
+		{value: "gui", label: "Desktop GUI Application", aliases: []string{"desktop", "g"}},
+		{value: "web", label: "Browser Calculator (web)", aliases: []string{"browser", "w"}},
+		{value: "tui", label: "Full-screen Terminal UI (tui)", aliases: []string{"terminal", "t"}},
+		{value: "notebook", label: "IPython/Jupyter Extension (notebook)", aliases: []string{"jupyter", "n"}},
+		{value: "api", label: "HTTP/JSON API Service (api)", aliases: []string{"http", "a"}},
+	}, &ui.Style)
 	if err != nil {
 		return err
 	}
-	choice = strings.TrimSpace(strings.ToLower(choice))
-	if choice == "n" || choice == "no" {
-		config.Interactive = false
-	} else if choice == "y" || choice == "yes" || choice == "" {
-		config.Interactive = true
-	}
 
-	// Precision
-	fmt.Printf("Decimal precision (1-20) [%d]: ", config.UI.Precision)
-	precisionStr, err := reader.ReadString('\n')
+	// The remaining settings only affect the command line calculator
+	if ui.Style != "" && ui.Style != "cli" {
+		return nil
+	}
+	if err := w.askBool("ui.interactive", "Create interactive calculator", &w.config.Interactive); err != nil {
+		return err
+	}
+	err = w.askChoice("ui.output_format", "Result output format", []wizardOption{
+		{value: "text", label: "Text", description: "Readable results"},
+		{value: "json", label: "JSON", description: "One JSON record per evaluation"},
+	}, &ui.OutputFormat)
 	if err != nil {
 		return err
 	}
-	precisionStr = strings.TrimSpace(precisionStr)
-	if precisionStr != "" {
-		if precision, err := strconv.Atoi(precisionStr); err == nil && precision >= 1 && precision <= 20 {
-			config.UI.Precision = precision
-		}
+	if err := w.askBool("ui.show_banner", "Show application banner", &ui.ShowBanner); err != nil {
+		return err
 	}
+	return w.askBool("ui.show_help", "Show help information", &ui.ShowHelp)
+}
 
-	// Angle unit
-	// UI Style
-	fmt.Println("Calculator interface:")
-	fmt.Println("1. Command Line Interface (CLI)")
-	fmt.Println("2. Desktop GUI Application")
-	fmt.Printf("Choose [1-2] (current: %s): ", func() string {
-		if config.UI.Style == "gui" {
-			return "gui"
-		}
-		return "cli"
-	}())
-	choice, err = reader.ReadString('\n')
-	if err != nil {
-		return err
+func summarizeUIConfig(config internal.CalculatorConfig) []string {
+	ui := config.UI
+	if ui.Style != "" && ui.Style != "cli" {
+		return []string{"UI Style: " + ui.Style}
 	}
-	choice = strings.TrimSpace(choice)
-	switch choice {
-	case "1", "cli", "command", "c":
-		config.UI.Style = "cli"
-	case "2", "gui", "desktop", "g":
-		config.UI.Style = "gui"
+	return []string{
+		"UI Style: cli",
+		fmt.Sprintf("Interactive: %s, output format: %s", yesNo(config.Interactive), ui.OutputFormat),
+		fmt.Sprintf("Banner: %s, help: %s", yesNo(ui.ShowBanner), yesNo(ui.ShowHelp)),
 	}
+}
 
-	// Angle unit
-	fmt.Println("Angle unit:")
-	fmt.Println("1. Degrees")
-	fmt.Println("2. Radians")
-	fmt.Printf("Choose [1-2] (current: %s): ", config.UI.AngleUnit)
-	choice, err = reader.ReadString('\n')
-	if err != nil {
+func askDisplayConfig(w *wizard) error {
+	ui := &w.config.UI
+	if err := w.askInt("display.precision", "Decimal precision", &ui.Precision, 1, 20); err != nil {
 		return err
 	}
-	choice = strings.TrimSpace(choice)
-	switch choice {
-	case "1", "degrees", "deg":
-		config.UI.AngleUnit = "degrees"
-	case "2", "radians", "rad":
-		config.UI.AngleUnit = "radians"
-	}
 
-	// Show banner
-	fmt.Printf("Show application banner? [Y/n] (current: %v): ", config.UI.ShowBanner)
-	choice, err = reader.ReadString('\n')
+	err := w.askChoice("display.angle_unit", "Angle unit", []wizardOption{
+		{value: "degrees", label: "Degrees", aliases: []string{"deg"}},
+		{value: "radians", label: "Radians", aliases: []string{"rad"}},
+	}, &ui.AngleUnit)
 	if err != nil {
 		return err
 	}
-	choice = strings.TrimSpace(strings.ToLower(choice))
-	if choice == "n" || choice == "no" {
-		config.UI.ShowBanner = false
-	} else if choice == "y" || choice == "yes" || choice == "" {
-		config.UI.ShowBanner = true
+
+	var locales []wizardOption
+	for _, name := range internal.LocaleNames() {
+		locale, _ := internal.LookupLocale(name)
+		locales = append(locales, wizardOption{value: name, label: name, description: "numbers like " + locale.NumberExample()})
+	}
+	if err := w.askChoice("display.locale", "Locale for messages and numbers", locales, &ui.Locale); err != nil {
+		return err
 	}
 
-	// Show help
-	fmt.Printf("Show help information? [Y/n] (current: %v): ", config.UI.ShowHelp)
-	choice, err = reader.ReadString('\n')
-	if err != nil {
+	return askTheme(w)
+}
+
+// askTheme asks for a built-in theme or a YAML theme file
+func askTheme(w *wizard) error {
+	ui := &w.config.UI
+	var options []wizardOption
+	for _, name := range internal.ThemeNames() {
+		options = append(options, wizardOption{value: name, label: name})
+	}
+	options = append(options, wizardOption{value: "file", label: "file", description: "Load a YAML theme file"})
+
+	theme := ui.Theme
+	if ui.CustomTheme != nil {
+		theme = "file"
+	}
+	if err := w.askChoice("display.theme", "Theme", options, &theme); err != nil {
 		return err
 	}
-	choice = strings.TrimSpace(strings.ToLower(choice))
-	if choice == "n" || choice == "no" {
-		config.UI.ShowHelp = false
-	} else if choice == "y" || choice == "yes" || choice == "" {
-		config.UI.ShowHelp = true
+	if theme != "file" {
+		ui.Theme, ui.CustomTheme = theme, nil
+		return nil
 	}
 
-	fmt.Println()
-	return nil
+	for {
+		path, err := w.prompt("display.theme_file", "Theme file (YAML): ")
+		if err != nil {
+			return err
+		}
+		if path == "" && ui.CustomTheme != nil {
+			return nil
+		}
+		custom, err := internal.LoadThemeFile(path)
+		if err == nil {
+			ui.CustomTheme = &custom
+			return nil
+		}
+		fmt.Fprintf(w.out, "❌ %v\n", err)
+	}
 }
 
-func askOutputConfig(reader *bufio.Reader, config *internal.CalculatorConfig) error {
-	fmt.Println("📁 Output Configuration")
-	fmt.Println("======================")
+func summarizeDisplayConfig(config internal.CalculatorConfig) []string {
+	ui := config.UI
+	theme := ui.Theme
+	if ui.CustomTheme != nil {
+		theme = ui.CustomTheme.Name + " (file)"
+	}
+	return []string{
+		fmt.Sprintf("Precision: %d, angle unit: %s", ui.Precision, ui.AngleUnit),
+		fmt.Sprintf("Locale: %s, theme: %s", ui.Locale, theme),
+	}
+}
 
-	// Output file
-	fmt.Printf("Output file path [%s]: ", config.OutputFile)
-	outputFile, err := reader.ReadString('\n')
-	if err != nil {
+// isGUIConfig reports whether the desktop GUI is being generated
+func isGUIConfig(config internal.CalculatorConfig) bool {
+	return config.UI.Style == "gui"
+}
+
+func askGUIConfig(w *wizard) error {
+	ui := &w.config.UI
+	if err := askKeypad(w); err != nil {
 		return err
 	}
-	outputFile = strings.TrimSpace(outputFile)
-	if outputFile != "" {
-		config.OutputFile = outputFile
+	if err := w.askSize("gui.window_size", "Window size", &ui.WindowWidth, &ui.WindowHeight); err != nil {
+		return err
 	}
-
-	fmt.Println()
-	return nil
+	if err := w.askSize("gui.min_window_size", "Minimum window size", &ui.MinWindowWidth, &ui.MinWindowHeight); err != nil {
+		return err
+	}
+	if err := w.askBool("gui.resizable", "Allow resizing the window", &ui.Resizable); err != nil {
+		return err
+	}
+	if err := w.askFloat("gui.font_scale", "Font scale", &ui.FontScale, 0.5, 4); err != nil {
+		return err
+	}
+	if err := w.askOptionalText("gui.display_font", "Display font family (none uses the theme's)", &ui.DisplayFont); err != nil {
+		return err
+	}
+	if err := w.askOptionalText("gui.announce", "Announce results for screen readers to 'stdout' or a log file", &ui.Announce); err != nil {
+		return err
+	}
+	return askKeymap(w)
 }
 
-func showSummaryAndConfirm(reader *bufio.Reader, config *internal.CalculatorConfig) error {
-	fmt.Println("📋 Configuration Summary")
-	fmt.Println("========================")
-	fmt.Printf("Project Name: %s\n", config.ProjectName)
-	fmt.Printf("Author: %s\n", config.Author)
-	fmt.Printf("Description: %s\n", config.Description)
-	fmt.Printf("Type: %s\n", config.Type)
-	fmt.Printf("UI Style: %s\n", config.UI.Style)
-	fmt.Printf("Output File: %s\n", config.OutputFile)
-	fmt.Printf("Interactive: %v\n", config.Interactive)
-	fmt.Printf("Precision: %d\n", config.UI.Precision)
-	fmt.Printf("Angle Unit: %s\n", config.UI.AngleUnit)
+// askKeypad asks for a built-in keypad or a YAML keypad file
+func askKeypad(w *wizard) error {
+	ui := &w.config.UI
+	options := []wizardOption{{value: "", label: "auto", description: "Follow the calculator type"}}
+	for _, name := range internal.KeypadNames() {
+		options = append(options, wizardOption{value: name, label: name})
+	}
+	options = append(options, wizardOption{value: "file", label: "file", description: "Load a YAML keypad layout"})
 
-	// Show enabled libraries
-	var enabledLibs []string
-	if config.Libraries.UseMath {
-		enabledLibs = append(enabledLibs, "math")
+	keypad := ui.Keypad
+	if ui.CustomKeypad != nil {
+		keypad = "file"
 	}
-	if config.Libraries.UseNumpy {
-		enabledLibs = append(enabledLibs, "numpy")
+	if err := w.askChoice("gui.keypad", "Keypad layout", options, &keypad); err != nil {
+		return err
 	}
-	if config.Libraries.UsePandas {
-		enabledLibs = append(enabledLibs, "pandas")
+	if keypad != "file" {
+		ui.Keypad, ui.CustomKeypad = keypad, nil
+		return nil
 	}
-	if config.Libraries.UseScipy {
-		enabledLibs = append(enabledLibs, "scipy")
+
+	for {
+		path, err := w.prompt("gui.keypad_file", "Keypad file (YAML): ")
+		if err != nil {
+			return err
+		}
+		if path == "" && ui.CustomKeypad != nil {
+			return nil
+		}
+		layout, err := internal.LoadKeypadFile(path)
+		if err == nil {
+			ui.CustomKeypad = &layout
+			return nil
+		}
+		fmt.Fprintf(w.out, "❌ %v\n", err)
 	}
-	if config.Libraries.UseSympy {
-		enabledLibs = append(enabledLibs, "sympy")
+}
+
+// askKeymap asks for extra keyboard shortcuts as KEYS=ACTION pairs
+func askKeymap(w *wizard) error {
+	ui := &w.config.UI
+	for {
+		current := formatKeymap(ui.Keymap)
+		if current == "" {
+			current = "defaults"
+		}
+		answer, err := w.prompt("gui.keymap", "Keyboard shortcuts as KEYS=ACTION, separated by spaces ('-' for defaults) [%s]: ", current)
+		if err != nil || answer == "" {
+			return err
+		}
+		if answer == "-" {
+			ui.Keymap = nil
+			return nil
+		}
+
+		var keymap []internal.KeyBinding
+		for _, value := range strings.Fields(answer) {
+			binding, err := internal.ParseKeyBinding(value)
+			if err != nil {
+				keymap = nil
+				fmt.Fprintf(w.out, "❌ %v\n", err)
+				break
+			}
+			keymap = append(keymap, binding)
+		}
+		if keymap == nil {
+			continue
+		}
+		candidate := w.config
+		candidate.UI.Keymap = keymap
+		if err := internal.ValidateKeymap(candidate); err != nil {
+			fmt.Fprintf(w.out, "❌ %v\n", err)
+			continue
+		}
+		ui.Keymap = keymap
+		return nil
 	}
-	if config.Libraries.UsePlotly {
-		enabledLibs = append(enabledLibs, "plotly")
+}
+
+// formatKeymap writes bindings as KEYS=ACTION pairs
+func formatKeymap(keymap []internal.KeyBinding) string {
+	var pairs []string
+	for _, binding := range keymap {
+		pairs = append(pairs, binding.Keys+"="+binding.Action)
 	}
-	fmt.Printf("Libraries: %s\n", strings.Join(enabledLibs, ", "))
+	return strings.Join(pairs, " ")
+}
 
-	// Show enabled features
-	var enabledFeatures []string
-	if config.Features.BasicArithmetic {
-		enabledFeatures = append(enabledFeatures, "basic-arithmetic")
+func summarizeGUIConfig(config internal.CalculatorConfig) []string {
+	ui := config.UI
+	keypad := ui.Keypad
+	switch {
+	case ui.CustomKeypad != nil:
+		keypad = ui.CustomKeypad.Name + " (file)"
+	case keypad == "":
+		keypad = "auto"
+	}
+	size := func(width, height int) string {
+		if width > 0 && height > 0 {
+			return fmt.Sprintf("%dx%d", width, height)
+		}
+		return "auto"
 	}
-	if config.Features.Memory {
-		enabledFeatures = append(enabledFeatures, "memory")
+	optional := func(value string) string {
+		if value == "" {
+			return "none"
+		}
+		return value
 	}
-	if config.Features.History {
-		enabledFeatures = append(enabledFeatures, "history")
+	return []string{
+		"Keypad: " + keypad,
+		fmt.Sprintf("Window: %s, minimum: %s, resizable: %s", size(ui.WindowWidth, ui.WindowHeight), size(ui.MinWindowWidth, ui.MinWindowHeight), yesNo(ui.Resizable)),
+		fmt.Sprintf("Font scale: %g, display font: %s, announce: %s", ui.FontScale, optional(ui.DisplayFont), optional(ui.Announce)),
+		"Keyboard shortcuts: " + listOrNone(strings.Fields(formatKeymap(ui.Keymap))),
 	}
-	if config.Features.Trigonometric {
-		enabledFeatures = append(enabledFeatures, "trigonometric")
+}
+
+// hasStorage reports whether memory or history can be persisted
+func hasStorage(config internal.CalculatorConfig) bool {
+	return config.Features.Memory || config.Features.History
+}
+
+func askStorageConfig(w *wizard) error {
+	storage := &w.config.Storage
+	if w.config.Features.Memory {
+		if err := w.askBool("storage.persist_memory", "Keep memory registers between sessions", &storage.PersistMemory); err != nil {
+			return err
+		}
+		if storage.PersistMemory {
+			if err := w.askOptionalText("storage.memory_file", "Memory file (none uses ~/.<project>_memory.json)", &storage.MemoryFile); err != nil {
+				return err
+			}
+		}
 	}
-	if config.Features.Logarithmic {
-		enabledFeatures = append(enabledFeatures, "logarithmic")
+
+	if w.config.Features.History {
+		if err := w.askBool("storage.persist_history", "Keep history between sessions", &storage.PersistHistory); err != nil {
+			return err
+		}
+		if storage.PersistHistory {
+			if err := w.askOptionalText("storage.history_file", "History file (none uses ~/.<project>_history.json)", &storage.HistoryFile); err != nil {
+				return err
+			}
+		}
+		if err := w.askInt("storage.history_size", "History size", &storage.HistoryMaxEntries, 1, 100000); err != nil {
+			return err
+		}
 	}
-	if config.Features.Statistical {
-		enabledFeatures = append(enabledFeatures, "statistical")
+	return nil
+}
+
+func summarizeStorageConfig(config internal.CalculatorConfig) []string {
+	storage := config.Storage
+	persisted := func(persist bool, file string) string {
+		switch {
+		case !persist:
+			return "this session only"
+		case file == "":
+			return "kept in the default file"
+		default:
+			return "kept in " + file
+		}
 	}
-	if config.Features.LinearAlgebra {
-		enabledFeatures = append(enabledFeatures, "linear-algebra")
+
+	var lines []string
+	if config.Features.Memory {
+		lines = append(lines, "Memory: "+persisted(storage.PersistMemory, storage.MemoryFile))
 	}
-	if config.Features.Plotting {
-		enabledFeatures = append(enabledFeatures, "plotting")
+	if config.Features.History {
+		lines = append(lines, fmt.Sprintf("History: %s, up to %d entries", persisted(storage.PersistHistory, storage.HistoryFile), storage.HistoryMaxEntries))
 	}
-	if config.Features.EquationSolver {
-		enabledFeatures = append(enabledFeatures, "equation-solver")
+	return lines
+}
+
+// configErrors lists the validation errors reported against one field
+func configErrors(config internal.CalculatorConfig, field string) []string {
+	var problems []string
+	for _, issue := range internal.ValidateConfig(config) {
+		if issue.Field == field && issue.Severity == internal.SeverityError {
+			problems = append(problems, issue.Message)
+		}
 	}
-	if config.Features.ComplexNumbers {
-		enabledFeatures = append(enabledFeatures, "complex-numbers")
+	return problems
+}
+
+func askPackagingConfig(w *wizard) error {
+	// The package layout only holds some styles, so others stay scripts
+	candidate := w.config
+	candidate.Layout = "package"
+	if len(configErrors(candidate, "layout")) > 0 {
+		w.config.Layout = "script"
+	} else {
+		err := w.askChoice("packaging.layout", "Project layout", []wizardOption{
+			{value: "script", label: "Script", description: "One Python file"},
+			{value: "package", label: "Package", description: "An installable project with pyproject.toml"},
+		}, &w.config.Layout)
+		if err != nil {
+			return err
+		}
 	}
-	if config.Features.UnitConversion {
-		enabledFeatures = append(enabledFeatures, "unit-conversion")
+	if w.config.Layout == "package" {
+		var options []wizardOption
+		for _, name := range internal.LicenseNames() {
+			options = append(options, wizardOption{value: name, label: name})
+		}
+		if err := w.askChoice("packaging.license", "License", options, &w.config.License); err != nil {
+			return err
+		}
 	}
-	fmt.Printf("Features: %s\n", strings.Join(enabledFeatures, ", "))
 
-	fmt.Println()
-	fmt.Print("Generate calculator with this configuration? [Y/n]: ")
-	choice, err := reader.ReadString('\n')
-	if err != nil {
+	deps := &w.config.Dependencies
+	for {
+		previous := deps.PythonVersion
+		if err := w.askOptionalText("dependencies.python_version", "Target Python version such as "+internal.DefaultPythonVersion+" (none keeps broad lower bounds)", &deps.PythonVersion); err != nil {
+			return err
+		}
+		// The target is checked against the style and layout chosen so far
+		problems := configErrors(w.config, "dependencies.python_version")
+		if len(problems) == 0 {
+			break
+		}
+		deps.PythonVersion = previous
+		fmt.Fprintf(w.out, "❌ %s\n", strings.Join(problems, "; "))
+	}
+
+	if err := w.askBool("dependencies.pin", "Pin exact package versions in requirements.txt", &deps.Pin); err != nil {
 		return err
 	}
-	choice = strings.TrimSpace(strings.ToLower(choice))
+	if deps.Pin {
+		deps.Constraints = false
+		return nil
+	}
+	return w.askBool("dependencies.constraints", "Also write constraints.txt with exact versions", &deps.Constraints)
+}
 
-	if choice == "n" || choice == "no" {
-		return fmt.Errorf("generation cancelled by user")
+func summarizePackagingConfig(config internal.CalculatorConfig) []string {
+	layout := "Layout: " + config.Layout
+	if config.Layout == "package" {
+		layout += ", license: " + config.License
 	}
+	deps := config.Dependencies
+	python := deps.PythonVersion
+	if python == "" {
+		python = "any (pins for " + internal.DefaultPythonVersion + ")"
+	}
+	return []string{
+		layout,
+		fmt.Sprintf("Python: %s, pinned: %s, constraints file: %s", python, yesNo(deps.Pin), yesNo(deps.Constraints)),
+	}
+}
 
-	return nil
+func askOutputConfig(w *wizard) error {
+	return w.askText("output.file", "Output file path", &w.config.OutputFile)
 }
 
-func hasExternalLibrariesInteractive(config internal.CalculatorConfig) bool {
-	return config.Libraries.UseNumpy ||
-		config.Libraries.UsePandas ||
-		config.Libraries.UseScipy ||
-		config.Libraries.UseSympy ||
-		config.Libraries.UsePlotly
+func summarizeOutputConfig(config internal.CalculatorConfig) []string {
+	return []string{"Output File: " + config.OutputFile}
+}
+
+// listOrNone joins names, or returns "none" when there are none
+func listOrNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
 }
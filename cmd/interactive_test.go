@@ -0,0 +1,381 @@
+package cmd
+
+import (
+	"bufio"
+	"calculator-generator/internal"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// replayWizard runs the wizard over a set of answers
+func replayWizard(answers map[string]string) (*wizard, error) {
+	w := newWizard(io.Discard)
+	w.answers = answers
+	return w, w.run()
+}
+
+func TestWizardPaths(t *testing.T) {
+	themeFile := filepath.Join(t.TempDir(), "night.yaml")
+	if err := os.WriteFile(themeFile, []byte("base: dark\nname: Night\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		answers map[string]string
+		check   func(t *testing.T, config internal.CalculatorConfig)
+		asked   []string // questions that must be asked
+		skipped []string // questions that must not be asked
+	}{
+		{
+			name:    "defaults",
+			answers: map[string]string{},
+			check: func(t *testing.T, config internal.CalculatorConfig) {
+				if want := internal.GetDefaultConfig(); !reflect.DeepEqual(config, want) {
+					t.Errorf("config = %+v, want the defaults", config)
+				}
+			},
+			asked:   []string{"ui.interactive", "ui.output_format"},
+			skipped: []string{"gui.keypad", "storage.persist_memory", "display.theme_file"},
+		},
+		{
+			name: "scientific gui",
+			answers: map[string]string{
+				"project.name":    "Lab Calc",
+				"type":            "s",
+				"ui.style":        "gui",
+				"gui.window_size": "640x800",
+				"gui.keymap":      "F9=memory_store",
+				"display.locale":  "de-DE",
+			},
+			check: func(t *testing.T, config internal.CalculatorConfig) {
+				if config.ProjectName != "Lab Calc" || config.Type != internal.ScientificCalculator || config.UI.Style != "gui" {
+					t.Errorf("config = %+v", config)
+				}
+				if config.UI.WindowWidth != 640 || config.UI.WindowHeight != 800 || config.UI.Locale != "de-DE" {
+					t.Errorf("UI = %+v", config.UI)
+				}
+				if len(config.UI.Keymap) != 1 || config.UI.Keymap[0].Action != "memory_store" {
+					t.Errorf("keymap = %+v", config.UI.Keymap)
+				}
+			},
+			asked:   []string{"gui.keypad", "storage.persist_memory"},
+			skipped: []string{"ui.interactive", "ui.output_format"},
+		},
+		{
+			name: "feature enables its libraries",
+			answers: map[string]string{
+				"features.data-analysis": "y",
+				"features.history":       "n",
+			},
+			check: func(t *testing.T, config internal.CalculatorConfig) {
+				if !config.Libraries.UsePandas {
+					t.Errorf("libraries = %+v, want pandas", config.Libraries)
+				}
+			},
+			skipped: []string{"storage.persist_history"},
+		},
+		{
+			name: "persisted memory",
+			answers: map[string]string{
+				"features.memory":        "true",
+				"storage.persist_memory": "yes",
+				"storage.memory_file":    "mem.json",
+			},
+			check: func(t *testing.T, config internal.CalculatorConfig) {
+				if !config.Storage.PersistMemory || config.Storage.MemoryFile != "mem.json" {
+					t.Errorf("storage = %+v", config.Storage)
+				}
+			},
+		},
+		{
+			name: "package layout",
+			answers: map[string]string{
+				"ui.style":                    "gui",
+				"packaging.layout":            "package",
+				"packaging.license":           "Apache-2.0",
+				"dependencies.python_version": "3.11",
+				"dependencies.pin":            "y",
+			},
+			check: func(t *testing.T, config internal.CalculatorConfig) {
+				if config.Layout != "package" || config.License != "Apache-2.0" {
+					t.Errorf("layout = %q, license = %q", config.Layout, config.License)
+				}
+				want := internal.DependencyConfig{PythonVersion: "3.11", Pin: true}
+				if config.Dependencies != want {
+					t.Errorf("dependencies = %+v, want %+v", config.Dependencies, want)
+				}
+			},
+			skipped: []string{"dependencies.constraints"},
+		},
+		{
+			name: "styles outside the package layout stay scripts",
+			answers: map[string]string{
+				"ui.style":                 "tui",
+				"dependencies.constraints": "y",
+			},
+			check: func(t *testing.T, config internal.CalculatorConfig) {
+				if config.Layout != "script" || !config.Dependencies.Constraints {
+					t.Errorf("layout = %q, dependencies = %+v", config.Layout, config.Dependencies)
+				}
+			},
+			skipped: []string{"packaging.layout", "packaging.license"},
+		},
+		{
+			name: "theme file",
+			answers: map[string]string{
+				"display.theme":      "file",
+				"display.theme_file": themeFile,
+			},
+			check: func(t *testing.T, config internal.CalculatorConfig) {
+				if config.UI.CustomTheme == nil || config.UI.CustomTheme.Name != "Night" {
+					t.Errorf("custom theme = %+v, want Night", config.UI.CustomTheme)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := replayWizard(tt.answers)
+			if err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			tt.check(t, w.config)
+			for _, key := range tt.asked {
+				if !w.asked[key] {
+					t.Errorf("%s was not asked", key)
+				}
+			}
+			for _, key := range tt.skipped {
+				if w.asked[key] {
+					t.Errorf("%s was asked", key)
+				}
+			}
+			if unused := w.unusedAnswers(); len(unused) > 0 {
+				t.Errorf("unused answers %v", unused)
+			}
+		})
+	}
+}
+
+func TestWizardReplayErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers map[string]string
+		want    string
+	}{
+		{"invalid choice", map[string]string{"ui.style": "holographic"}, `"holographic" is not a valid answer to ui.style`},
+		{"invalid number", map[string]string{"display.precision": "99"}, `"99" is not a valid answer to display.precision`},
+		{"back", map[string]string{"type": "back"}, "type cannot go back"},
+		{"cancelled", map[string]string{"summary": "n"}, "cancelled"},
+		{"invalid keymap", map[string]string{"ui.style": "gui", "gui.keymap": "F9=memory_store"}, `"F9=memory_store" is not a valid answer to gui.keymap`},
+		{"unsupported python", map[string]string{"dependencies.python_version": "2.7"}, `"2.7" is not a valid answer to dependencies.python_version`},
+		{"python too old for the package layout", map[string]string{"ui.style": "gui", "packaging.layout": "package", "dependencies.python_version": "3.6"}, `"3.6" is not a valid answer to dependencies.python_version`},
+		{"hex digit shortcut", map[string]string{"ui.style": "gui", "features.programming": "y", "gui.keymap": "C=clear"}, `"C=clear" is not a valid answer to gui.keymap`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := replayWizard(tt.answers)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("run() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWizardRecordReplay(t *testing.T) {
+	// A typed session that goes back a step and edits a section from the summary
+	input := strings.Join([]string{
+		"My Calc", "", "", // project
+		"back",        // back to the project step
+		"", "Ada", "", // project again
+		"scientific",                                                        // type
+		"", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "y", // features
+		"", "", "", "", "", "", // libraries
+		"tui",                        // interface
+		"12", "rad", "fr-FR", "dark", // display
+		"y", "", "n", "250", // storage
+		"3.11", "", "y", // packaging: tui stays a script
+		"",                               // output
+		"1", "", "", "Pocket calculator", // edit the project from the summary
+		"y",
+	}, "\n") + "\n"
+
+	w := newWizard(io.Discard)
+	w.reader = bufio.NewReader(strings.NewReader(input))
+	w.recorder = &answerRecorder{}
+	if err := w.run(); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if w.config.ProjectName != "My Calc" || w.config.Author != "Ada" || w.config.Description != "Pocket calculator" ||
+		w.config.UI.Locale != "fr-FR" || !w.config.Features.Programming || !w.config.Storage.PersistMemory || w.config.Storage.PersistHistory {
+		t.Fatalf("typed config = %+v", w.config)
+	}
+
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	if err := w.recorder.save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "summary") || strings.Contains(string(data), "back") {
+		t.Errorf("recorded navigation answers:\n%s", data)
+	}
+
+	answers, err := loadAnswers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := replayWizard(answers)
+	if err != nil {
+		t.Fatalf("replay error = %v", err)
+	}
+	if !reflect.DeepEqual(replayed.config, w.config) {
+		t.Errorf("replayed config = %+v\nwant %+v", replayed.config, w.config)
+	}
+}
+
+// configFieldKeys maps configuration fields, by their JSON path, to the
+// answer key of the question that sets them. Features and libraries follow
+// the features.<feature> and libraries.<library> pattern instead.
+var configFieldKeys = map[string]string{
+	"type":                        "type",
+	"output_file":                 "output.file",
+	"project_name":                "project.name",
+	"author":                      "project.author",
+	"description":                 "project.description",
+	"interactive":                 "ui.interactive",
+	"ui.style":                    "ui.style",
+	"ui.theme":                    "display.theme",
+	"ui.keypad":                   "gui.keypad",
+	"ui.show_help":                "ui.show_help",
+	"ui.show_banner":              "ui.show_banner",
+	"ui.precision":                "display.precision",
+	"ui.angle_unit":               "display.angle_unit",
+	"ui.locale":                   "display.locale",
+	"ui.output_format":            "ui.output_format",
+	"ui.window_width":             "gui.window_size",
+	"ui.window_height":            "gui.window_size",
+	"ui.min_window_width":         "gui.min_window_size",
+	"ui.min_window_height":        "gui.min_window_size",
+	"ui.resizable":                "gui.resizable",
+	"ui.font_scale":               "gui.font_scale",
+	"ui.display_font":             "gui.display_font",
+	"ui.keymap":                   "gui.keymap",
+	"ui.announce":                 "gui.announce",
+	"ui.custom_theme":             "display.theme_file",
+	"ui.custom_keypad":            "gui.keypad_file",
+	"storage.persist_memory":      "storage.persist_memory",
+	"storage.memory_file":         "storage.memory_file",
+	"storage.persist_history":     "storage.persist_history",
+	"storage.history_file":        "storage.history_file",
+	"storage.history_max_entries": "storage.history_size",
+	"dependencies.python_version": "dependencies.python_version",
+	"dependencies.pin":            "dependencies.pin",
+	"dependencies.constraints":    "dependencies.constraints",
+	"layout":                      "packaging.layout",
+	"license":                     "packaging.license",
+}
+
+// configFieldPaths lists the JSON paths of the settings in a configuration
+// struct, descending into nested sections but not into loaded files
+func configFieldPaths(t reflect.Type, prefix string) []string {
+	var paths []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			paths = append(paths, configFieldPaths(field.Type, prefix+name+".")...)
+			continue
+		}
+		paths = append(paths, prefix+name)
+	}
+	return paths
+}
+
+func TestWizardCoversConfig(t *testing.T) {
+	dir := t.TempDir()
+	themeFile := filepath.Join(dir, "night.yaml")
+	if err := os.WriteFile(themeFile, []byte("base: dark\nname: Night\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	layout, _ := internal.ResolveKeypad(internal.GetDefaultConfig())
+	data, err := yaml.Marshal(layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keypadFile := filepath.Join(dir, "keypad.yaml")
+	if err := os.WriteFile(keypadFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Between them these sessions reach every step and every follow-up question
+	sessions := []map[string]string{
+		{
+			"features.memory":         "y",
+			"features.history":        "y",
+			"storage.persist_memory":  "y",
+			"storage.persist_history": "y",
+			"display.theme":           "file",
+			"display.theme_file":      themeFile,
+		},
+		{
+			"ui.style":               "gui",
+			"gui.keypad":             "file",
+			"gui.keypad_file":        keypadFile,
+			"packaging.layout":       "package",
+			"dependencies.pin":       "n",
+			"features.memory":        "y",
+			"features.history":       "y",
+			"storage.persist_memory": "y",
+		},
+	}
+	asked := make(map[string]bool)
+	for _, answers := range sessions {
+		w, err := replayWizard(answers)
+		if err != nil {
+			t.Fatalf("run() error = %v", err)
+		}
+		for key := range w.asked {
+			asked[key] = true
+		}
+	}
+
+	for _, path := range configFieldPaths(reflect.TypeOf(internal.CalculatorConfig{}), "") {
+		key, ok := configFieldKeys[path]
+		switch section, name, _ := strings.Cut(path, "."); section {
+		case "features":
+			key, ok = "features."+strings.ReplaceAll(name, "_", "-"), true
+		case "libraries":
+			key, ok = "libraries."+strings.TrimPrefix(name, "use_"), true
+		}
+		if !ok {
+			t.Errorf("%s has no wizard question; add one and list its answer key in configFieldKeys", path)
+			continue
+		}
+		if !asked[key] {
+			t.Errorf("%s: question %s was never asked", path, key)
+		}
+	}
+}
+
+func TestWizardInputEnds(t *testing.T) {
+	w := newWizard(io.Discard)
+	w.reader = bufio.NewReader(strings.NewReader("My Calc\n"))
+	if err := w.run(); err == nil || !strings.Contains(err.Error(), "input ended") {
+		t.Errorf("run() error = %v, want the input to end", err)
+	}
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"calculator-generator/internal"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -19,7 +21,9 @@ dependencies between features and libraries.
 Examples:
   calculator-generator list features
   calculator-generator list libraries
-  calculator-generator list types`,
+  calculator-generator list types
+  calculator-generator list shortcuts
+  calculator-generator list locales`,
 }
 
 // listFeaturesCmd lists all available features
@@ -41,30 +45,30 @@ var listFeaturesCmd = &cobra.Command{
 		fmt.Println("🧮 Scientific Features:")
 		fmt.Println("  • trigonometric      - sin, cos, tan, asin, acos, atan")
 		fmt.Println("  • logarithmic        - log, ln, log10, log2")
-		fmt.Println("  • exponential        - exp, power functions")
+		fmt.Println("  • exponential        - exp, root")
 		fmt.Println("  • complex-numbers    - Complex number arithmetic")
 		fmt.Println()
 
 		fmt.Println("📊 Statistical Features:")
 		fmt.Println("  • statistical        - mean, median, std dev, variance")
-		fmt.Println("  • data-analysis      - Advanced data manipulation")
+		fmt.Println("  • data-analysis      - quantile, iqr (pandas)")
 		fmt.Println()
 
 		fmt.Println("🔬 Advanced Mathematical Features:")
 		fmt.Println("  • linear-algebra     - Matrix operations, eigenvalues")
-		fmt.Println("  • calculus           - Derivatives, integrals")
+		fmt.Println("  • calculus           - integral, derivative, find_root (scipy)")
 		fmt.Println("  • equation-solver    - Solve algebraic equations")
-		fmt.Println("  • matrix-operations  - Advanced matrix functions")
+		fmt.Println("  • matrix-operations  - Planned, generates no code yet")
 		fmt.Println()
 
 		fmt.Println("📈 Visualization Features:")
 		fmt.Println("  • plotting           - Create 2D plots and charts")
-		fmt.Println("  • graphing           - Interactive graphing capabilities")
+		fmt.Println("  • graphing           - Planned, generates no code yet")
 		fmt.Println()
 
 		fmt.Println("🔧 Utility Features:")
-		fmt.Println("  • unit-conversion    - Convert between different units")
-		fmt.Println("  • programming        - Programmable calculator functions")
+		fmt.Println("  • unit-conversion    - Planned, generates no code yet")
+		fmt.Println("  • programming        - Hex, bin and oct keys on the GUI keypad")
 		fmt.Println()
 
 		fmt.Println("💡 Usage:")
@@ -92,7 +96,7 @@ var listLibrariesCmd = &cobra.Command{
 		fmt.Println("🧮 Numerical Computing:")
 		fmt.Println("  • numpy              - Numerical computing with Python")
 		fmt.Println("                        Features: Arrays, mathematical functions, linear algebra")
-		fmt.Println("                        Required for: statistical, linear-algebra, matrix-operations")
+		fmt.Println("                        Required for: statistical, linear-algebra")
 		fmt.Println("                        Install: pip install numpy>=1.21.0")
 		fmt.Println()
 
@@ -106,21 +110,21 @@ var listLibrariesCmd = &cobra.Command{
 		fmt.Println("🔬 Scientific Computing:")
 		fmt.Println("  • scipy              - Scientific computing library")
 		fmt.Println("                        Features: Optimization, integration, interpolation")
-		fmt.Println("                        Required for: advanced statistical functions")
+		fmt.Println("                        Required for: calculus")
 		fmt.Println("                        Install: pip install scipy>=1.7.0")
 		fmt.Println()
 
 		fmt.Println("🔣 Symbolic Mathematics:")
 		fmt.Println("  • sympy              - Symbolic mathematics")
 		fmt.Println("                        Features: Algebraic manipulation, calculus, equation solving")
-		fmt.Println("                        Required for: equation-solver, calculus")
+		fmt.Println("                        Required for: equation-solver")
 		fmt.Println("                        Install: pip install sympy>=1.9.0")
 		fmt.Println()
 
 		fmt.Println("📈 Visualization:")
 		fmt.Println("  • plotly             - Interactive plotting library")
 		fmt.Println("                        Features: 2D/3D plots, interactive charts, web-based visualization")
-		fmt.Println("                        Required for: plotting, graphing")
+		fmt.Println("                        Required for: plotting")
 		fmt.Println("                        Install: pip install plotly>=5.0.0")
 		fmt.Println()
 
@@ -163,13 +167,14 @@ var listTypesCmd = &cobra.Command{
 		fmt.Println("    - Complex number support")
 		fmt.Println("    - Memory and history functionality")
 		fmt.Println("  • Best For: Engineering, science, advanced mathematics")
-		fmt.Println("  • Dependencies: numpy, scipy, sympy")
+		fmt.Println("  • Dependencies: numpy")
 		fmt.Println()
 
 		fmt.Println("🎨 Interface Options:")
 		fmt.Println("  Both calculator types support multiple interfaces!")
 		fmt.Println("  • CLI: Command-line interface for terminal use")
 		fmt.Println("  • GUI: Desktop application with buttons and display")
+		fmt.Println("  • Web: Browser calculator served locally (standard library only)")
 		fmt.Println()
 
 		fmt.Println("🎨 Customization:")
@@ -184,10 +189,66 @@ var listTypesCmd = &cobra.Command{
 		fmt.Println("  calculator-generator generate --type basic")
 		fmt.Println("  calculator-generator generate --type scientific")
 		fmt.Println("  calculator-generator generate --style gui  # For desktop GUI")
+		fmt.Println("  calculator-generator generate --style web  # For a browser calculator")
+		fmt.Println("  calculator-generator generate --style tui  # For a full-screen terminal UI")
+		fmt.Println("  calculator-generator generate --style api  # For a headless HTTP/JSON service")
+		fmt.Println("  calculator-generator generate --style notebook -o calc_magic.py  # For Jupyter notebooks")
 		fmt.Println("  calculator-generator interactive  # For guided selection")
 	},
 }
 
+// listShortcutsCmd lists the GUI keyboard shortcut actions
+var listShortcutsCmd = &cobra.Command{
+	Use:   "shortcuts",
+	Short: "List GUI keyboard shortcuts and bindable actions",
+	Long:  `Display the default GUI keyboard shortcuts and every action that --bind can assign a shortcut to.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("⌨️  GUI Keyboard Shortcuts")
+		fmt.Println("=========================")
+		fmt.Println()
+
+		defaults := make(map[string][]string)
+		for _, binding := range internal.DefaultKeymap() {
+			defaults[binding.Action] = append(defaults[binding.Action], binding.Keys)
+		}
+
+		for _, action := range internal.KeymapActionNames() {
+			keys := strings.Join(defaults[action[0]], ", ")
+			if keys == "" {
+				keys = "-"
+			}
+			fmt.Printf("  • %-18s %-24s %s\n", action[0], action[1], keys)
+		}
+		fmt.Println()
+
+		fmt.Println("💡 Usage:")
+		fmt.Println("  Bind or rebind shortcuts with --bind KEYS=ACTION (modifiers: Ctrl, Alt, Shift):")
+		fmt.Println("  calculator-generator generate --style gui --bind Ctrl+K=memory_store --bind F5=clear")
+	},
+}
+
+// listLocalesCmd lists the locales calculators can be generated for
+var listLocalesCmd = &cobra.Command{
+	Use:   "locales",
+	Short: "List locales for messages and number format",
+	Long:  `Display the locales --locale accepts, with the number notation each one reads and writes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("🌍 Available Locales")
+		fmt.Println("====================")
+		fmt.Println()
+
+		for _, name := range internal.LocaleNames() {
+			locale, _ := internal.LookupLocale(name)
+			fmt.Printf("  • %-8s numbers like %-10s function arguments separated by %q\n",
+				locale.Tag, locale.NumberExample(), locale.ArgumentSeparator)
+		}
+		fmt.Println()
+
+		fmt.Println("💡 Usage:")
+		fmt.Println("  calculator-generator generate --locale de-DE")
+	},
+}
+
 // listExamplesCmd shows example command combinations
 var listExamplesCmd = &cobra.Command{
 	Use:   "examples",
@@ -253,6 +314,13 @@ var listExamplesCmd = &cobra.Command{
 		fmt.Println("    --name \"My Calculator\" \\")
 		fmt.Println("    --author \"John Doe\"")
 		fmt.Println()
+		fmt.Println("  # German calculator reading and writing 1.234,56")
+		fmt.Println("  calculator-generator generate --locale de-DE")
+		fmt.Println()
+		fmt.Println("  # GUI calculator with the programmer keypad")
+		fmt.Println("  calculator-generator generate --style gui --keypad programmer \\")
+		fmt.Println("    --features programming,memory")
+		fmt.Println()
 
 		fmt.Println("🎮 Interactive Mode:")
 		fmt.Println("  # Launch interactive wizard")
@@ -277,4 +345,6 @@ func init() {
 	listCmd.AddCommand(listLibrariesCmd)
 	listCmd.AddCommand(listTypesCmd)
 	listCmd.AddCommand(listExamplesCmd)
+	listCmd.AddCommand(listShortcutsCmd)
+	listCmd.AddCommand(listLocalesCmd)
 }
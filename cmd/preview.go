@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"calculator-generator/internal"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+var (
+	pythonClassPattern  = regexp.MustCompile(`^class (\w+)`)
+	pythonDefPattern    = regexp.MustCompile(`^(\s*)def (\w+)\(`)
+	pythonImportPattern = regexp.MustCompile(`(?m)^\s*(import|from) \w`)
+)
+
+// pythonFunctions returns the names of the functions a script defines, in
+// order, with methods of top-level classes named Class.method
+func pythonFunctions(source string) []string {
+	var names []string
+	class := ""
+	for _, line := range strings.Split(source, "\n") {
+		if match := pythonClassPattern.FindStringSubmatch(line); match != nil {
+			class = match[1]
+			continue
+		}
+		match := pythonDefPattern.FindStringSubmatch(line)
+		switch {
+		case match == nil:
+		case match[1] == "":
+			class = ""
+			names = append(names, match[2])
+		case class != "":
+			names = append(names, class+"."+match[2])
+		default:
+			names = append(names, match[2])
+		}
+	}
+	return names
+}
+
+// featureFunctions lists, for each enabled feature, the functions that are
+// only generated because of it. Each feature is switched off in turn and
+// the functions missing from that script are the feature's.
+func featureFunctions(config internal.CalculatorConfig, source string) [][2]string {
+	all := pythonFunctions(source)
+	var contributions [][2]string
+	for _, feature := range wizardFeatures(&config) {
+		if !*feature.field {
+			continue
+		}
+
+		without := config
+		for _, other := range wizardFeatures(&without) {
+			if other.name == feature.name {
+				*other.field = false
+			}
+		}
+		reduced, err := internal.NewGenerator(without).Render()
+		if err != nil {
+			// Another setting depends on the feature
+			contributions = append(contributions, [2]string{feature.name, "(needed by other settings)"})
+			continue
+		}
+
+		remaining := make(map[string]int)
+		for _, name := range pythonFunctions(reduced) {
+			remaining[name]++
+		}
+		var added []string
+		for _, name := range all {
+			if remaining[name] > 0 {
+				remaining[name]--
+				continue
+			}
+			added = append(added, name)
+		}
+		if len(added) > 0 {
+			contributions = append(contributions, [2]string{feature.name, summarizeNames(added, 8)})
+		}
+	}
+	return contributions
+}
+
+// summarizeNames joins up to max names and counts the rest
+func summarizeNames(names []string, max int) string {
+	if len(names) <= max {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:max], ", "), len(names)-max)
+}
+
+// previewText renders the calculator in memory and returns its statistics
+// followed by the code, or by a diff against the existing output file when
+// diff is set
+func previewText(config internal.CalculatorConfig, diff bool) (string, error) {
+	source, err := internal.NewGenerator(config).Render()
+	if err != nil {
+		return "", err
+	}
+	lines := splitLines(source)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "📄 %s: %d lines, %d functions, %d imports\n", config.OutputFile,
+		len(lines), len(pythonFunctions(source)), len(pythonImportPattern.FindAllString(source, -1)))
+	if contributions := featureFunctions(config, source); len(contributions) > 0 {
+		b.WriteString("Functions by feature:\n")
+		for _, contribution := range contributions {
+			fmt.Fprintf(&b, "  %s: %s\n", contribution[0], contribution[1])
+		}
+	}
+	b.WriteString("\n")
+
+	if !diff {
+		b.WriteString(source)
+		return b.String(), nil
+	}
+
+	existing, err := os.ReadFile(config.OutputFile)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", config.OutputFile, err)
+	}
+	changes := unifiedDiff(config.OutputFile+" (existing)", config.OutputFile+" (new)", splitLines(string(existing)), lines, 3)
+	if changes == "" {
+		fmt.Fprintf(&b, "No changes to %s\n", config.OutputFile)
+	}
+	b.WriteString(changes)
+	return b.String(), nil
+}
+
+// splitLines splits text into lines that keep their line endings
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// unifiedDiff compares two lists of lines and returns the differences in
+// unified diff format with the given lines of context, or "" when they are
+// equal
+func unifiedDiff(fromName, toName string, a, b []string, context int) string {
+	// Common lines at both ends need no comparison
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	if prefix == len(a) && prefix == len(b) {
+		return ""
+	}
+
+	// Longest common subsequence of the differing middle parts
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := make([][]int32, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	// Edit script over the whole inputs: ' ' keeps, '-' removes, '+' adds
+	type edit struct {
+		op   byte
+		line string
+	}
+	var edits []edit
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{' ', line})
+	}
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			edits = append(edits, edit{' ', midA[i]})
+			i++
+			j++
+		case j == len(midB) || (i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', midA[i]})
+			i++
+		default:
+			edits = append(edits, edit{'+', midB[j]})
+			j++
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{' ', line})
+	}
+
+	// Group the changes into hunks with their context
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(edits); {
+		if edits[start].op == ' ' {
+			start++
+			continue
+		}
+		first := start - context
+		if first < 0 {
+			first = 0
+		}
+		last := start
+		for k := start; k < len(edits) && k <= last+2*context; k++ {
+			if edits[k].op != ' ' {
+				last = k
+			}
+		}
+		end := last + context + 1
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		// Line numbers of the hunk's first line in each input
+		lineA, lineB := 1, 1
+		for _, e := range edits[:first] {
+			if e.op != '+' {
+				lineA++
+			}
+			if e.op != '-' {
+				lineB++
+			}
+		}
+		countA, countB := 0, 0
+		var body strings.Builder
+		for _, e := range edits[first:end] {
+			if e.op != '+' {
+				countA++
+			}
+			if e.op != '-' {
+				countB++
+			}
+			body.WriteByte(e.op)
+			body.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				body.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n%s", lineA, countA, lineB, countB, body.String())
+		start = end
+	}
+	return out.String()
+}
+
+// page shows text through $PAGER, or less, writing it straight to out when
+// no pager is available
+func page(text string, out io.Writer) error {
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = []string{"less", "-R"}
+	}
+	if _, err := exec.LookPath(pager[0]); err != nil {
+		_, err := io.WriteString(out, text)
+		return err
+	}
+
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"calculator-generator/internal"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{
+			name: "changed line",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- old\n+++ new\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			a:    "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			b:    "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+		},
+		{
+			name: "insertion without a final newline",
+			a:    "x\ny",
+			b:    "x\nnew\ny",
+			want: "--- old\n+++ new\n@@ -1,2 +1,3 @@\n x\n+new\n y\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unifiedDiff("old", "new", splitLines(tt.a), splitLines(tt.b), 3)
+			if got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviewText(t *testing.T) {
+	config := internal.GetDefaultConfig()
+	config.Features.Memory = true
+	text, err := previewText(config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, code, _ := strings.Cut(text, "\n\n")
+	if !strings.HasPrefix(header, "📄 calculator.py: ") || !strings.Contains(header, "functions") {
+		t.Errorf("header = %q", header)
+	}
+	if !strings.Contains(header, "  memory: ") || !strings.Contains(header, "Memory.store") {
+		t.Errorf("header does not credit the memory functions:\n%s", header)
+	}
+	if !strings.HasPrefix(code, "#!/usr/bin/env python3") {
+		t.Errorf("preview does not show the code")
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"calculator-generator/internal"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// schemaCmd represents the schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON schema of calculator output records",
+	Long: `Print the JSON Schema describing the records emitted by calculators
+generated with --output-format json.
+
+Each evaluation produces one JSON object per line containing the input,
+the normalized expression, the result value and type, the formatted result,
+the unit, any error and the evaluation duration.
+
+Examples:
+  calculator-generator schema
+  calculator-generator schema > evaluation-record.schema.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := json.MarshalIndent(internal.EvaluationRecordSchema(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode schema: %w", err)
+		}
+		fmt.Println(string(schema))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
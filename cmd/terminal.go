@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"os"
+)
+
+// errRawModeUnsupported is returned by openTerminal when the terminal cannot
+// switch to raw mode, in which case the wizard keeps to line prompts
+var errRawModeUnsupported = errors.New("the terminal does not support raw mode")
+
+// terminal is an interactive terminal that full-screen wizard screens
+// switch to raw mode, one key at a time
+type terminal struct {
+	in     *os.File
+	out    *os.File
+	reader *bufio.Reader // shared with the line prompts
+}
+
+// key is a key press understood by full-screen screens
+type key int
+
+const (
+	keyOther key = iota
+	keyUp
+	keyDown
+	keyHome
+	keyEnd
+	keyToggle
+	keyAll
+	keyNone
+	keyAccept
+	keyBack
+	keyCancel
+)
+
+// readKey waits for a key press. The terminal must be in raw mode.
+func (t *terminal) readKey() (key, error) {
+	return readKey(t.reader)
+}
+
+// readKey reads one key press from r, leaving any keys after it buffered
+// for the next call. A terminal sends an escape sequence in one write, so
+// the bytes that follow an escape belong to its sequence only when they are
+// already buffered; a lone Esc is not followed by anything.
+func readKey(r *bufio.Reader) (key, error) {
+	first, _, err := r.ReadRune()
+	if err != nil {
+		return keyOther, err
+	}
+	if first != '\x1b' || r.Buffered() == 0 {
+		return parseKey([]byte(string(first))), nil
+	}
+
+	sequence := []byte{0x1b}
+	next, _ := r.ReadByte()
+	sequence = append(sequence, next)
+	if next != '[' && next != 'O' {
+		return parseKey(sequence), nil
+	}
+	// CSI and SS3 sequences end with a byte from @ to ~
+	for r.Buffered() > 0 {
+		b, _ := r.ReadByte()
+		sequence = append(sequence, b)
+		if b >= '@' && b <= '~' {
+			break
+		}
+	}
+	return parseKey(sequence), nil
+}
+
+// parseKey maps the bytes of one key press to a key. Arrow keys arrive as
+// escape sequences, in either the normal or the application cursor mode.
+func parseKey(input []byte) key {
+	switch string(input) {
+	case "\x1b[A", "\x1bOA", "k":
+		return keyUp
+	case "\x1b[B", "\x1bOB", "j":
+		return keyDown
+	case "\x1b[H", "\x1bOH", "\x1b[1~", "g":
+		return keyHome
+	case "\x1b[F", "\x1bOF", "\x1b[4~", "G":
+		return keyEnd
+	case " ", "x":
+		return keyToggle
+	case "a":
+		return keyAll
+	case "n":
+		return keyNone
+	case "\r", "\n":
+		return keyAccept
+	case "b", "<", "\x7f", "\x08":
+		return keyBack
+	case "q", "\x03", "\x04":
+		return keyCancel
+	}
+	return keyOther
+}
+
+// enterScreen switches to the alternate screen and hides the cursor
+func (t *terminal) enterScreen() {
+	t.out.WriteString("\x1b[?1049h\x1b[?25l")
+}
+
+// leaveScreen shows the cursor and returns to the normal screen
+func (t *terminal) leaveScreen() {
+	t.out.WriteString("\x1b[?25h\x1b[?1049l")
+}
+
+// draw replaces the screen's contents
+func (t *terminal) draw(content string) {
+	t.out.WriteString("\x1b[H\x1b[2J" + content)
+}
@@ -0,0 +1,22 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package cmd
+
+import (
+	"bufio"
+	"os"
+)
+
+// openTerminal always falls back to line prompts on platforms without
+// termios
+func openTerminal(in, out *os.File, reader *bufio.Reader) (*terminal, error) {
+	return nil, errRawModeUnsupported
+}
+
+func (t *terminal) raw() (func(), error) {
+	return nil, errRawModeUnsupported
+}
+
+func (t *terminal) size() (int, int) {
+	return 80, 24
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadKeySplitsBursts(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []key
+	}{
+		{"letters", "jjk", []key{keyDown, keyDown, keyUp}},
+		{"arrows", "\x1b[B\x1bOB\x1b[A", []key{keyDown, keyDown, keyUp}},
+		{"keys around a sequence", " \x1b[4~\r", []key{keyToggle, keyEnd, keyAccept}},
+		{"lone escape", "\x1b", []key{keyOther}},
+		{"alt and a key", "\x1bxq", []key{keyOther, keyCancel}},
+		{"multibyte rune", "é\x03", []key{keyOther, keyCancel}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.input))
+			// Fill the buffer the way one read from the terminal would
+			r.Peek(len(tt.input))
+			var got []key
+			for range tt.want {
+				k, err := readKey(r)
+				if err != nil {
+					t.Fatalf("readKey() error = %v after %v", err, got)
+				}
+				got = append(got, k)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keys = %v, want %v", got, tt.want)
+			}
+			if r.Buffered() != 0 {
+				t.Errorf("%d bytes left over", r.Buffered())
+			}
+		})
+	}
+}
+
+func TestKeysLeaveTypedAheadLines(t *testing.T) {
+	// Keys typed quickly after accepting a checklist reach the next prompt
+	r := bufio.NewReader(strings.NewReader("\x1b[B \rMy Calc\n"))
+	for _, want := range []key{keyDown, keyToggle, keyAccept} {
+		if k, err := readKey(r); k != want || err != nil {
+			t.Fatalf("readKey() = %v, %v; want %v", k, err, want)
+		}
+	}
+	if line, err := r.ReadString('\n'); line != "My Calc\n" || err != nil {
+		t.Errorf("next line = %q, %v", line, err)
+	}
+}
@@ -0,0 +1,55 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package cmd
+
+import (
+	"bufio"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// openTerminal returns the terminal on in and out, or errRawModeUnsupported
+// when either is not a terminal whose modes can be read. Keys are read
+// through reader, the line prompts' reader of in, so input typed ahead is
+// not lost when the wizard switches between screens and prompts.
+func openTerminal(in, out *os.File, reader *bufio.Reader) (*terminal, error) {
+	if os.Getenv("TERM") == "dumb" || !isTerminal(in) || !isTerminal(out) {
+		return nil, errRawModeUnsupported
+	}
+	if _, err := unix.IoctlGetTermios(int(in.Fd()), ioctlGetTermios); err != nil {
+		return nil, errRawModeUnsupported
+	}
+	return &terminal{in: in, out: out, reader: reader}, nil
+}
+
+// raw switches the terminal to raw input: no echo, no line editing and no
+// signals, so every key press reaches readKey. Output processing stays on.
+// The returned function restores the previous mode.
+func (t *terminal) raw() (func(), error) {
+	fd := int(t.in.Fd())
+	saved, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := *saved
+	mode.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	mode.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	mode.Cc[unix.VMIN] = 1
+	mode.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &mode); err != nil {
+		return nil, err
+	}
+	return func() { unix.IoctlSetTermios(fd, ioctlSetTermios, saved) }, nil
+}
+
+// size returns the terminal's width and height in cells, falling back to
+// 80x24 when the size is unknown
+func (t *terminal) size() (int, int) {
+	ws, err := unix.IoctlGetWinsize(int(t.out.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 {
+		return 80, 24
+	}
+	return int(ws.Col), int(ws.Row)
+}
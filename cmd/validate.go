@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"calculator-generator/internal"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate [config]",
+	Short: "Check a configuration without generating anything",
+	Long: `Check a calculator configuration and report every problem at once.
+
+The configuration is read like the generate command reads it: from the config
+file (the argument, --config or ~/.calculator-generator.yaml) with the generate
+flags' defaults for anything it leaves out. Each problem is listed with the
+setting's path, its severity and a suggested fix. Errors stop generate;
+warnings point at settings that have no effect.
+
+Examples:
+  calculator-generator validate
+  calculator-generator validate my-calculator.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidate,
+	// The issues are listed above the error, and main prints the error
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	path := viper.ConfigFileUsed()
+	if len(args) == 1 {
+		path = args[0]
+		viper.SetConfigFile(path)
+		if err := viper.ReadInConfig(); err != nil {
+			return fmt.Errorf("reading config file: %w", err)
+		}
+	}
+
+	var issues []internal.Issue
+	if path != "" {
+		fmt.Printf("🔍 Validating %s\n", path)
+		unknown, err := unknownConfigKeys(path)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, unknown...)
+	} else {
+		fmt.Println("🔍 No config file found; validating the default configuration")
+	}
+
+	config, _, err := configFromFlags()
+	if err != nil {
+		return err
+	}
+	issues = append(issues, internal.ValidateConfig(config)...)
+
+	if err := reportIssues(os.Stdout, issues); err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Println("✅ Configuration is valid")
+	}
+	return nil
+}
+
+// reportIssues lists issues with their suggested fixes and returns an error
+// when any of them is an error
+func reportIssues(w io.Writer, issues []internal.Issue) error {
+	errors, warnings := 0, 0
+	for _, issue := range issues {
+		icon := "⚠️ "
+		if issue.Severity == internal.SeverityError {
+			icon = "❌"
+			errors++
+		} else {
+			warnings++
+		}
+		fmt.Fprintf(w, "%s %-7s %s: %s\n", icon, issue.Severity, issue.Field, issue.Message)
+		if issue.Fix != "" {
+			fmt.Fprintf(w, "           fix: %s\n", issue.Fix)
+		}
+	}
+	if len(issues) > 0 {
+		fmt.Fprintf(w, "%s, %s\n", plural(errors, "error"), plural(warnings, "warning"))
+	}
+	if errors > 0 {
+		return fmt.Errorf("configuration has %s", plural(errors, "error"))
+	}
+	return nil
+}
+
+// plural counts things in words, such as "1 error" or "2 errors"
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// unknownConfigKeys warns about config file settings that are not generate
+// flags, suggesting the closest flag
+func unknownConfigKeys(path string) ([]internal.Issue, error) {
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	known := make(map[string]bool)
+	var names []string
+	addFlag := func(flag *pflag.Flag) {
+		known[flag.Name] = true
+		names = append(names, flag.Name)
+	}
+	generateCmd.Flags().VisitAll(addFlag)
+	rootCmd.PersistentFlags().VisitAll(addFlag)
+
+	keys := file.AllKeys()
+	sort.Strings(keys)
+	var issues []internal.Issue
+	for _, key := range keys {
+		if known[key] {
+			continue
+		}
+		issue := internal.Issue{Field: key, Severity: internal.SeverityWarning, Message: "unknown setting, it is ignored"}
+		if suggestion := closest(key, names); suggestion != "" {
+			issue.Fix = fmt.Sprintf("did you mean %q?", suggestion)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// closest returns the name nearest to key, or "" when none is close enough
+// to be a likely typo
+func closest(key string, names []string) string {
+	best, bestDistance := "", len(key)/2+1
+	for _, name := range names {
+		if d := editDistance(strings.ToLower(key), name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommandErrorsSkipUsage(t *testing.T) {
+	style := generateCmd.Flags().Lookup("style")
+	t.Cleanup(func() {
+		style.Value.Set(style.DefValue)
+		style.Changed = false
+		rootCmd.SetArgs(nil)
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+	})
+
+	// The issues are already listed and main prints the error, so cobra
+	// adds neither the usage nor a second copy of the error
+	var output bytes.Buffer
+	rootCmd.SetOut(&output)
+	rootCmd.SetErr(&output)
+	rootCmd.SetArgs([]string{"generate", "--style", "holo", "--output", filepath.Join(t.TempDir(), "calc.py")})
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "configuration has 1 error") {
+		t.Fatalf("Execute() = %v, want the configuration error", err)
+	}
+	if output.Len() > 0 {
+		t.Errorf("cobra printed %q", output.String())
+	}
+}
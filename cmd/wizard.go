@@ -0,0 +1,439 @@
+package cmd
+
+import (
+	"bufio"
+	"calculator-generator/internal"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// errWizardBack is returned by a prompt when the user types "back"
+var errWizardBack = errors.New("back")
+
+// wizardStep is a node of the wizard's step graph. Steps are visited in
+// order, skipping those that do not apply to the configuration so far.
+type wizardStep struct {
+	title   string
+	applies func(config internal.CalculatorConfig) bool // nil when the step always applies
+	ask     func(w *wizard) error
+	summary func(config internal.CalculatorConfig) []string
+}
+
+// wizardSummaryKey is the key of the final confirmation question
+const wizardSummaryKey = "summary"
+
+// wizard asks the questions of each step and keeps the configuration built
+// from the answers. Every question has a key, such as "ui.style", that
+// answer files use to replay a session.
+type wizard struct {
+	reader   *bufio.Reader
+	out      io.Writer
+	config   internal.CalculatorConfig
+	steps    []wizardStep
+	validate func(config internal.CalculatorConfig) error
+
+	answers  map[string]string       // replayed answers, nil when reading from reader
+	asked    map[string]bool         // questions asked while replaying
+	recorder *answerRecorder         // nil unless the session is recorded
+	terminal *terminal               // set when checklists can be shown full screen
+	pager    func(text string) error // shows previews; nil writes them to out
+}
+
+// newWizard returns a wizard over every step, starting from the default
+// configuration and validating it like the generate command
+func newWizard(out io.Writer) *wizard {
+	return &wizard{
+		out:    out,
+		config: internal.GetDefaultConfig(),
+		steps:  wizardSteps(),
+		validate: func(config internal.CalculatorConfig) error {
+			return internal.NewGenerator(config).Validate()
+		},
+	}
+}
+
+// wizardOption is one answer of a multiple-choice question
+type wizardOption struct {
+	value       string
+	label       string
+	description string
+	aliases     []string
+}
+
+// applicable reports whether step i applies to the current configuration
+func (w *wizard) applicable(i int) bool {
+	return w.steps[i].applies == nil || w.steps[i].applies(w.config)
+}
+
+// nextStep returns the first applicable step after step i, or len(w.steps)
+// when only the summary is left
+func (w *wizard) nextStep(i int) int {
+	for i++; i < len(w.steps) && !w.applicable(i); i++ {
+	}
+	return i
+}
+
+// run walks the step graph and ends on the summary screen. It returns once
+// the configuration is confirmed and valid.
+func (w *wizard) run() error {
+	var visited []int
+	for i := w.nextStep(-1); ; {
+		if i == len(w.steps) {
+			edit, err := w.showSummary()
+			if errors.Is(err, errWizardBack) && len(visited) > 0 {
+				i, visited = visited[len(visited)-1], visited[:len(visited)-1]
+				continue
+			}
+			if err != nil || edit < 0 {
+				return err
+			}
+			// Edit one section, then come back to the summary
+			if err := w.runStep(edit); err != nil && !errors.Is(err, errWizardBack) {
+				return err
+			}
+			continue
+		}
+
+		err := w.runStep(i)
+		if errors.Is(err, errWizardBack) {
+			if len(visited) == 0 {
+				fmt.Fprintln(w.out, "↩️  This is the first step.")
+				fmt.Fprintln(w.out)
+				continue
+			}
+			i, visited = visited[len(visited)-1], visited[:len(visited)-1]
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		visited = append(visited, i)
+		i = w.nextStep(i)
+	}
+}
+
+// runStep shows a step's heading and asks its questions
+func (w *wizard) runStep(i int) error {
+	title := w.steps[i].title
+	fmt.Fprintln(w.out, title)
+	fmt.Fprintln(w.out, strings.Repeat("=", len([]rune(title))+1))
+	if err := w.steps[i].ask(w); err != nil {
+		fmt.Fprintln(w.out)
+		return err
+	}
+	fmt.Fprintln(w.out)
+	return nil
+}
+
+// showSummary lists the answers of every applicable step and asks whether
+// to generate. It returns the step to edit, or -1 once the user confirms a
+// configuration that passes validation.
+func (w *wizard) showSummary() (int, error) {
+	for {
+		fmt.Fprintln(w.out, "📋 Configuration Summary")
+		fmt.Fprintln(w.out, "========================")
+
+		var sections []int
+		for i, step := range w.steps {
+			if !w.applicable(i) {
+				continue
+			}
+			sections = append(sections, i)
+			fmt.Fprintf(w.out, "%d. %s\n", len(sections), step.title)
+			for _, line := range step.summary(w.config) {
+				fmt.Fprintf(w.out, "     %s\n", line)
+			}
+		}
+		fmt.Fprintln(w.out)
+
+		preview := "'p' to preview the code"
+		if _, err := os.Stat(w.config.OutputFile); err == nil {
+			preview += fmt.Sprintf(", 'd' to diff it against %s", w.config.OutputFile)
+		}
+		answer, err := w.prompt(wizardSummaryKey, "Generate calculator with this configuration? [Y/n], %s, or a section number [1-%d] to edit it: ", preview, len(sections))
+		if err != nil {
+			return 0, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "p", "preview", "d", "diff":
+			w.showPreview(strings.HasPrefix(strings.ToLower(answer), "d"))
+			continue
+		case "", "y", "yes":
+			if w.validate == nil {
+				return -1, nil
+			}
+			if err := w.validate(w.config); err != nil {
+				if w.answers != nil {
+					return 0, fmt.Errorf("answer file: %w", err)
+				}
+				fmt.Fprintf(w.out, "❌ %v\n", err)
+				fmt.Fprintln(w.out, "Edit the section above before generating.")
+				fmt.Fprintln(w.out)
+				continue
+			}
+			return -1, nil
+		case "n", "no", "q", "quit":
+			return 0, fmt.Errorf("generation cancelled by user")
+		}
+
+		if number, err := strconv.Atoi(answer); err == nil && number >= 1 && number <= len(sections) {
+			fmt.Fprintln(w.out)
+			return sections[number-1], nil
+		}
+		fmt.Fprintf(w.out, "❌ Invalid choice. Please enter y, n, p or a number from 1 to %d.\n\n", len(sections))
+	}
+}
+
+// showPreview shows the code the configuration generates, or its diff
+// against the existing output file, without writing anything
+func (w *wizard) showPreview(diff bool) {
+	text, err := previewText(w.config, diff)
+	if err == nil {
+		if w.pager != nil {
+			err = w.pager(text)
+		} else {
+			_, err = io.WriteString(w.out, text)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(w.out, "❌ %v\n", err)
+	}
+	fmt.Fprintln(w.out)
+}
+
+// prompt prints the question with the given key and reads the trimmed
+// answer, or takes it from the answer file when replaying. Typing "back" (or
+// "<") at any prompt returns errWizardBack.
+func (w *wizard) prompt(key, format string, args ...interface{}) (string, error) {
+	fmt.Fprintf(w.out, format, args...)
+	if w.answers != nil {
+		return w.replay(key)
+	}
+
+	line, err := w.reader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return "", fmt.Errorf("input ended before the wizard finished")
+	}
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	answer := strings.TrimSpace(line)
+	if isBackAnswer(answer) {
+		return "", errWizardBack
+	}
+	if w.recorder != nil && key != wizardSummaryKey {
+		w.recorder.record(key, answer)
+	}
+	return answer, nil
+}
+
+// replay answers the question with the given key from the answer file. A
+// question without an answer keeps its current value. Each question is
+// answered at most once, so an answer the wizard rejects ends the session
+// instead of being asked again forever.
+func (w *wizard) replay(key string) (string, error) {
+	answer := w.answers[key]
+	if w.asked[key] {
+		return "", fmt.Errorf("answer file: %q is not a valid answer to %s", answer, key)
+	}
+	if w.asked == nil {
+		w.asked = make(map[string]bool)
+	}
+	w.asked[key] = true
+	fmt.Fprintln(w.out, answer)
+
+	if isBackAnswer(answer) {
+		return "", fmt.Errorf("answer file: %s cannot go back", key)
+	}
+	return answer, nil
+}
+
+// unusedAnswers returns the sorted keys of the answer file that no question
+// asked for, usually misspelt keys or settings of a skipped step
+func (w *wizard) unusedAnswers() []string {
+	var keys []string
+	for key := range w.answers {
+		if !w.asked[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isBackAnswer reports whether an answer asks for the previous step
+func isBackAnswer(answer string) bool {
+	return strings.EqualFold(answer, "back") || answer == "<"
+}
+
+// askText asks for a string, keeping the current value on an empty answer
+func (w *wizard) askText(key, label string, value *string) error {
+	answer, err := w.prompt(key, "%s [%s]: ", label, *value)
+	if err != nil {
+		return err
+	}
+	if answer != "" {
+		*value = answer
+	}
+	return nil
+}
+
+// askOptionalText asks for a string that may be unset; "-" clears it
+func (w *wizard) askOptionalText(key, label string, value *string) error {
+	current := *value
+	if current == "" {
+		current = "none"
+	}
+	answer, err := w.prompt(key, "%s ('-' for none) [%s]: ", label, current)
+	if err != nil {
+		return err
+	}
+	switch answer {
+	case "":
+	case "-":
+		*value = ""
+	default:
+		*value = answer
+	}
+	return nil
+}
+
+// askBool asks a yes/no question, keeping the current value on an empty answer
+func (w *wizard) askBool(key, label string, value *bool) error {
+	for {
+		answer, err := w.prompt(key, "%s? [y/n] (current: %s): ", label, yesNo(*value))
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return nil
+		case "y", "yes", "true":
+			*value = true
+			return nil
+		case "n", "no", "false":
+			*value = false
+			return nil
+		}
+		fmt.Fprintln(w.out, "❌ Please answer y or n.")
+	}
+}
+
+// askInt asks for a whole number between min and max
+func (w *wizard) askInt(key, label string, value *int, min, max int) error {
+	for {
+		answer, err := w.prompt(key, "%s (%d-%d) [%d]: ", label, min, max, *value)
+		if err != nil || answer == "" {
+			return err
+		}
+		if number, err := strconv.Atoi(answer); err == nil && number >= min && number <= max {
+			*value = number
+			return nil
+		}
+		fmt.Fprintf(w.out, "❌ Please enter a whole number from %d to %d.\n", min, max)
+	}
+}
+
+// askFloat asks for a number between min and max
+func (w *wizard) askFloat(key, label string, value *float64, min, max float64) error {
+	for {
+		answer, err := w.prompt(key, "%s (%g-%g) [%g]: ", label, min, max, *value)
+		if err != nil || answer == "" {
+			return err
+		}
+		if number, err := strconv.ParseFloat(answer, 64); err == nil && number >= min && number <= max {
+			*value = number
+			return nil
+		}
+		fmt.Fprintf(w.out, "❌ Please enter a number from %g to %g.\n", min, max)
+	}
+}
+
+// askSize asks for a WIDTHxHEIGHT size; "auto" clears it
+func (w *wizard) askSize(key, label string, width, height *int) error {
+	current := "auto"
+	if *width > 0 && *height > 0 {
+		current = fmt.Sprintf("%dx%d", *width, *height)
+	}
+	for {
+		answer, err := w.prompt(key, "%s as WIDTHxHEIGHT or 'auto' [%s]: ", label, current)
+		if err != nil || answer == "" {
+			return err
+		}
+		if strings.EqualFold(answer, "auto") {
+			*width, *height = 0, 0
+			return nil
+		}
+		parsedWidth, parsedHeight, err := parseWindowSize(answer)
+		if err == nil {
+			*width, *height = parsedWidth, parsedHeight
+			return nil
+		}
+		fmt.Fprintf(w.out, "❌ %v\n", err)
+	}
+}
+
+// askChoice asks for one of the options by number, value or alias, keeping
+// the current value on an empty answer
+func (w *wizard) askChoice(key, label string, options []wizardOption, value *string) error {
+	fmt.Fprintf(w.out, "%s:\n", label)
+	for i, option := range options {
+		if option.description != "" {
+			fmt.Fprintf(w.out, "%d. %s - %s\n", i+1, option.label, option.description)
+		} else {
+			fmt.Fprintf(w.out, "%d. %s\n", i+1, option.label)
+		}
+	}
+
+	current := *value
+	for _, option := range options {
+		if current == "" && option.value == "" {
+			current = option.label
+		}
+	}
+	for {
+		answer, err := w.prompt(key, "Choose [1-%d] (current: %s): ", len(options), current)
+		if err != nil || answer == "" {
+			return err
+		}
+		if option, ok := matchOption(options, answer); ok {
+			*value = option.value
+			return nil
+		}
+		fmt.Fprintf(w.out, "❌ Invalid choice. Please enter a number from 1 to %d.\n", len(options))
+	}
+}
+
+// matchOption finds the option an answer names
+func matchOption(options []wizardOption, answer string) (wizardOption, bool) {
+	if number, err := strconv.Atoi(answer); err == nil && number >= 1 && number <= len(options) {
+		return options[number-1], true
+	}
+	answer = strings.ToLower(answer)
+	for _, option := range options {
+		if answer == strings.ToLower(option.value) || answer == strings.ToLower(option.label) {
+			return option, true
+		}
+		for _, alias := range option.aliases {
+			if answer == alias {
+				return option, true
+			}
+		}
+	}
+	return wizardOption{}, false
+}
+
+// yesNo spells a boolean answer
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,378 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// API service limits applied unless overridden on the generated script's
+// command line
+const (
+	apiDefaultPort         = 8080
+	apiDefaultTimeout      = 5.0
+	apiMaxRequestBytes     = 64 * 1024
+	apiMaxBatchExpressions = 100
+)
+
+// APIGenerator handles the generation of headless HTTP/JSON calculator
+// services built only on the Python standard library
+type APIGenerator struct {
+	config CalculatorConfig
+	core   *Generator
+}
+
+// NewAPIGenerator creates a new API service generator instance
+func NewAPIGenerator(config CalculatorConfig) *APIGenerator {
+	return &APIGenerator{config: config, core: NewGenerator(config)}
+}
+
+// GenerateAPIService creates a headless calculator service
+func (g *APIGenerator) GenerateAPIService() (string, error) {
+	data := g.prepareAPITemplateData()
+	return g.renderAPITemplate(data)
+}
+
+// prepareAPITemplateData prepares data for API service template rendering
+func (g *APIGenerator) prepareAPITemplateData() TemplateData {
+	data := g.core.styleTemplateData([]string{
+		"import argparse",
+		"import inspect",
+		"import multiprocessing",
+		"import pickle",
+		"import threading",
+		"from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer",
+		"from urllib.parse import parse_qs, urlsplit",
+	}, g.generateAPIMainContent())
+	data.Summary = "Headless HTTP/JSON calculator service."
+	return data
+}
+
+// generateAPIMainContent creates the request handler and server entry point
+func (g *APIGenerator) generateAPIMainContent() string {
+	var content strings.Builder
+
+	content.WriteString(fmt.Sprintf(`# Service limits (override with --timeout and --max-request-bytes)
+DEFAULT_TIMEOUT = %.1f
+MAX_REQUEST_BYTES = %d
+MAX_BATCH_EXPRESSIONS = %d
+
+
+`, apiDefaultTimeout, apiMaxRequestBytes, apiMaxBatchExpressions))
+
+	content.WriteString(generateEvaluationWorker() + "\n\n\n" + generateRequestErrorClass() + `
+
+
+class CalculatorAPIHandler(BaseHTTPRequestHandler):
+    """JSON API for a shared calculator session"""
+    calculator = None
+    worker = None
+    # lock guards the session and is held only briefly, so reads never wait
+    # for an evaluation; evaluations runs one evaluation or batch at a time
+    # so ans and _N follow the order requests are answered in
+    lock = threading.Lock()
+    evaluations = threading.Lock()
+    eval_timeout = DEFAULT_TIMEOUT
+    max_request_bytes = MAX_REQUEST_BYTES
+    started = time.time()
+` + generateJSONHandlerMethods() + generateWorkerHandlerMethods() + `
+    def do_GET(self):
+        """Handle read-only endpoints"""
+        url = urlsplit(self.path)
+        try:
+            if url.path == "/health":
+                self.send_json(self.health())
+            elif url.path == "/functions":
+                with self.lock:
+                    self.send_json(self.list_functions())`)
+
+	if g.config.Features.Memory {
+		content.WriteString(`
+            elif url.path == "/memory":
+                with self.lock:
+                    self.send_json(self.memory_registers())`)
+	}
+
+	if g.config.Features.History {
+		content.WriteString(`
+            elif url.path == "/history":
+                with self.lock:
+                    self.send_json(self.history_entries(parse_qs(url.query)))`)
+	}
+
+	content.WriteString(`
+            else:
+                raise RequestError(404, "Not found")
+        except RequestError as e:
+            self.send_json({"error": str(e)}, e.status)
+
+    def do_POST(self):
+        """Handle evaluation endpoints"""
+        url = urlsplit(self.path)
+        try:
+            body = self.read_json()
+            if url.path == "/evaluate":
+                self.send_json(*self.evaluate_request(body))
+            elif url.path == "/batch":
+                self.send_json(self.batch_request(body))
+            else:
+                raise RequestError(404, "Not found")
+        except RequestError as e:
+            self.send_json({"error": str(e)}, e.status)
+`)
+
+	if g.config.Features.Memory || g.config.Features.History {
+		content.WriteString(`
+    def do_PUT(self):
+        """Handle replacement endpoints"""
+        url = urlsplit(self.path)
+        try:
+            body = self.read_json()`)
+	}
+
+	branch := "if"
+	if g.config.Features.History {
+		content.WriteString(`
+            if url.path == "/history":
+                with self.lock:
+                    self.send_json(self.replace_history(body))`)
+		branch = "elif"
+	}
+
+	if g.config.Features.Memory {
+		content.WriteString(`
+            ` + branch + ` url.path == "/memory":
+                registers = body.get("registers")
+                if not isinstance(registers, dict):
+                    raise RequestError(400, 'Expected {"registers": {"NAME": value, ...}}')
+                values = self.memory_values(registers)
+                with self.lock:
+                    self.calculator.memory.clear()
+                    for name, value in values.items():
+                        self.calculator.memory.store(value, name)
+                    self.send_json(self.memory_registers())
+            elif url.path.startswith("/memory/"):
+                name = url.path[len("/memory/"):]
+                if "value" not in body:
+                    raise RequestError(400, 'Expected {"value": number or expression}')
+                values = self.memory_values({name: body["value"]})
+                with self.lock:
+                    self.calculator.memory.store(values[name], name)
+                    self.send_json(self.memory_registers())`)
+	}
+
+	if g.config.Features.Memory || g.config.Features.History {
+		content.WriteString(`
+            else:
+                raise RequestError(404, "Not found")
+        except RequestError as e:
+            self.send_json({"error": str(e)}, e.status)
+`)
+	}
+
+	content.WriteString(`
+    def health(self):
+        """Service status"""
+        return {
+            "status": "ok",
+            "name": ` + pythonString(g.config.ProjectName) + `,
+            "uptime_seconds": round(time.time() - self.started, 3),
+            "timeout_seconds": self.eval_timeout,
+            "max_request_bytes": self.max_request_bytes,
+        }
+
+    def list_functions(self):
+        """Describe the functions and constants available in expressions"""
+        context = self.calculator.build_eval_context()`)
+
+	if g.config.Features.Memory {
+		content.WriteString(`
+        hidden = set(self.calculator.memory.registers) | {"ans"}`)
+	} else {
+		content.WriteString(`
+        hidden = {"ans"}`)
+	}
+
+	content.WriteString(`
+        functions, constants = [], []
+        for name, value in sorted(context.items()):
+            if name.startswith("_") or name in hidden:
+                continue
+            if callable(value):
+                # The signature is that of the callable expressions reach;
+                # wrappers such as the angle-aware trig lambdas have no
+                # docstring and borrow the module-level function's
+                try:
+                    signature = str(inspect.signature(value))
+                except (TypeError, ValueError):
+                    signature = None
+                documented = value if value.__doc__ else globals().get(name, value)
+                doc = (inspect.getdoc(documented) or "").split("\n")[0]
+                functions.append({"name": name, "signature": signature, "doc": doc})
+            elif isinstance(value, (int, float)):
+                constants.append({"name": name, "value": value})
+        return {"functions": functions, "constants": constants}
+
+    def evaluate(self, user_input, deadline):
+        """Evaluate one expression before the deadline; returns the record and HTTP status.
+        The caller holds evaluations."""
+        with self.lock:
+            expression = self.calculator.normalize_expression(user_input)
+        start = time.perf_counter()
+        kind, value = self.run_evaluation(user_input, deadline)
+        duration_ms = (time.perf_counter() - start) * 1000
+
+        if kind == "timeout":
+            error = f"Evaluation timed out after {self.eval_timeout} seconds"
+            return make_record(user_input, expression, None, None, None, error, duration_ms), 504
+        if kind == "error":
+            return make_record(user_input, expression, None, None, None, value, duration_ms), 422
+
+        with self.lock:
+            self.calculator.remember_result(value)
+            result = self.calculator.format_result(value)`)
+
+	if g.config.Features.History {
+		content.WriteString(`
+            self.calculator.history.add_entry(user_input, result)`)
+	}
+
+	content.WriteString(`
+        return make_record(user_input, expression, value, format_localized(result), None, None, duration_ms), 200
+
+    def evaluate_request(self, body):
+        """POST /evaluate {"expression": "..."}"""
+        expression = body.get("expression")
+        if not isinstance(expression, str):
+            raise RequestError(400, 'Expected {"expression": "..."}')
+        deadline = time.monotonic() + self.eval_timeout
+        self.wait_for_evaluations(deadline)
+        try:
+            return self.evaluate(expression, deadline)
+        finally:
+            self.evaluations.release()
+
+    def batch_request(self, body):
+        """POST /batch {"expressions": [...], "stop_on_error": false}
+
+        Expressions are evaluated in order, so later ones can refer to
+        earlier results through ans and _N. The timeout covers the whole batch.
+        """
+        expressions = body.get("expressions")
+        if not isinstance(expressions, list) or not all(isinstance(item, str) for item in expressions):
+            raise RequestError(400, 'Expected {"expressions": ["...", ...]}')
+        if len(expressions) > MAX_BATCH_EXPRESSIONS:
+            raise RequestError(413, f"At most {MAX_BATCH_EXPRESSIONS} expressions per batch")
+        stop_on_error = bool(body.get("stop_on_error", False))
+
+        deadline = time.monotonic() + self.eval_timeout
+        results = []
+        self.wait_for_evaluations(deadline)
+        try:
+            for expression in expressions:
+                record, status = self.evaluate(expression, deadline)
+                results.append(record)
+                if status == 504 or (stop_on_error and record["error"] is not None):
+                    break
+        finally:
+            self.evaluations.release()
+        return {"results": results, "completed": len(results) == len(expressions)}
+`)
+
+	if g.config.Features.Memory {
+		content.WriteString(`
+    def memory_registers(self):
+        """Current memory registers as JSON values"""
+        return {name: to_json_value(value) for name, value in self.calculator.memory.list()}
+
+    def memory_values(self, registers):
+        """Resolve register values, evaluating expressions in the worker"""
+        deadline = time.monotonic() + self.eval_timeout
+        self.wait_for_evaluations(deadline)
+        try:
+            return {name: self.memory_value(name, value, deadline) for name, value in registers.items()}
+        finally:
+            self.evaluations.release()
+
+    def memory_value(self, name, value, deadline):
+        """Validate a register name and resolve its value (a number or an expression)"""
+        if not Memory.is_valid_name(str(name)):
+            raise RequestError(400, f"Invalid register name: {name}")
+        if isinstance(value, str):
+            kind, value = self.run_evaluation(value, deadline)
+            if kind == "timeout":
+                raise RequestError(504, f"Evaluation timed out after {self.eval_timeout} seconds")
+            if kind == "error":
+                raise RequestError(422, value)
+        if isinstance(value, bool) or not isinstance(value, (int, float)):
+            raise RequestError(422, f"Register {name} must hold a real number")
+        return value
+`)
+	}
+
+	if g.config.Features.History {
+		content.WriteString(`
+    def history_entries(self, query):
+        """GET /history?count=N&search=TEXT"""
+        history = self.calculator.history
+        entries = history.search(query["search"][0]) if "search" in query else history.get_history()
+        if "count" in query:
+            try:
+                count = int(query["count"][0])
+            except ValueError:
+                raise RequestError(400, "count must be an integer")
+            entries = entries[-count:] if count > 0 else []
+        return entries
+
+    def replace_history(self, body):
+        """PUT /history {"entries": [{"expression": "...", "result": ...}, ...]}; an empty list clears it"""
+        entries = body.get("entries")
+        if not isinstance(entries, list) or not all(
+            isinstance(entry, dict) and isinstance(entry.get("expression"), str) and "result" in entry
+            for entry in entries
+        ):
+            raise RequestError(400, 'Expected {"entries": [{"expression": "...", "result": ...}, ...]}')
+        history = self.calculator.history
+        history.clear_history()
+        for entry in entries:
+            history.add_entry(entry["expression"], str(entry["result"]))
+        return history.get_history()
+`)
+	}
+
+	content.WriteString(fmt.Sprintf(`
+
+def main():
+    """Start the calculator API service"""
+    parser = argparse.ArgumentParser(description=%s)
+    parser.add_argument("--host", default="127.0.0.1", help="address to listen on")
+    parser.add_argument("--port", type=int, default=%d, help="port to listen on")
+    parser.add_argument("--timeout", type=float, default=DEFAULT_TIMEOUT, help="evaluation timeout per request in seconds")
+    parser.add_argument("--max-request-bytes", type=int, default=MAX_REQUEST_BYTES, help="largest accepted request body")
+    args = parser.parse_args()
+
+    CalculatorAPIHandler.calculator = CalculatorCore()
+    CalculatorAPIHandler.worker = EvaluationWorker()
+    CalculatorAPIHandler.worker.start()
+    CalculatorAPIHandler.eval_timeout = args.timeout
+    CalculatorAPIHandler.max_request_bytes = args.max_request_bytes
+    server = ThreadingHTTPServer((args.host, args.port), CalculatorAPIHandler)
+    server.daemon_threads = True
+    print(f"Calculator API listening on http://{args.host}:{args.port} (Ctrl+C to stop)")
+    try:
+        server.serve_forever()
+    except KeyboardInterrupt:
+        print("\nGoodbye!")
+    finally:
+        server.server_close()
+        CalculatorAPIHandler.worker.stop()
+
+if __name__ == "__main__":
+    main()`, pythonString(g.config.ProjectName+" API service"), apiDefaultPort))
+
+	return content.String()
+}
+
+// renderAPITemplate renders the API service template with the given data
+func (g *APIGenerator) renderAPITemplate(data TemplateData) (string, error) {
+	return renderScript("api_service", data)
+}
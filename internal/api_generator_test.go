@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAPIEvaluationTimeout(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "api"
+	config.Features.History = true
+	config.Features.Trigonometric = true
+	source, err := NewAPIGenerator(config).GenerateAPIService()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A huge power must not outlive the timeout, must not block reads of
+	// the session while it runs, and must leave the service usable after
+	program := `import json, threading, time, urllib.request
+from http.server import ThreadingHTTPServer
+import module
+
+Handler = module.CalculatorAPIHandler
+Handler.calculator = module.CalculatorCore()
+Handler.eval_timeout = 1
+Handler.worker = module.EvaluationWorker()
+Handler.worker.start()
+server = ThreadingHTTPServer(("127.0.0.1", 0), Handler)
+threading.Thread(target=server.serve_forever, daemon=True).start()
+url = "http://127.0.0.1:%d" % server.server_address[1]
+
+def call(method, path, body=None):
+    data = None if body is None else json.dumps(body).encode()
+    request = urllib.request.Request(url + path, data=data, method=method, headers={"Content-Type": "application/json"})
+    try:
+        with urllib.request.urlopen(request, timeout=10) as response:
+            return response.status, json.load(response)
+    except urllib.error.HTTPError as e:
+        return e.code, json.load(e)
+
+outcome = {"socket_timeout": Handler.timeout}
+call("POST", "/evaluate", {"expression": "40+2"})
+slow = {}
+start = time.monotonic()
+worker = threading.Thread(target=lambda: slow.update(result=call("POST", "/evaluate", {"expression": "9**9**9"})))
+worker.start()
+time.sleep(0.2)
+read_start = time.monotonic()
+status, _ = call("GET", "/history")
+outcome["read during evaluation"] = [status, time.monotonic() - read_start < 0.5]
+worker.join()
+outcome["huge power"] = [slow["result"][0], time.monotonic() - start < 3]
+status, record = call("POST", "/evaluate", {"expression": "ans + 2"})
+outcome["after timeout"] = [status, record["value"]]
+
+status, entries = call("PUT", "/history", {"entries": [{"expression": "1+1", "result": 2}]})
+outcome["replace history"] = [status, [(entry["index"], entry["expression"], entry["result"]) for entry in entries]]
+outcome["clear history"] = call("PUT", "/history", {"entries": []})
+outcome["bad history"] = call("PUT", "/history", {"entries": [{"result": 2}]})[0]
+
+functions = {item["name"]: item for item in call("GET", "/functions")[1]["functions"]}
+outcome["sin signature"] = functions["sin"]["signature"]
+outcome["sin documented"] = bool(functions["sin"]["doc"])
+server.shutdown()
+Handler.worker.stop()
+print(json.dumps(outcome))`
+	out, err := runPythonModule(t, source, program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := map[string]any{
+		// The evaluation timeout leaves the socket timeout alone
+		"socket_timeout":         nil,
+		"read during evaluation": []any{200.0, true},
+		"huge power":             []any{504.0, true},
+		"after timeout":          []any{200.0, 44.0},
+		"replace history":        []any{200.0, []any{[]any{1.0, "1+1", "2"}}},
+		"clear history":          []any{200.0, []any{}},
+		"bad history":            400.0,
+		"sin signature":          "(x)",
+		"sin documented":         true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("outcome = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,237 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultPythonVersion is the Python version pins are chosen for when no
+// target is set
+const DefaultPythonVersion = "3.12"
+
+// pythonVersion is a Python minor version such as 3.12
+type pythonVersion struct {
+	major, minor int
+}
+
+// parsePythonVersion parses a version such as "3.12" or "3.12.4"; the patch
+// level does not change which releases apply
+func parsePythonVersion(value string) (pythonVersion, bool) {
+	parts := strings.Split(value, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return pythonVersion{}, false
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return pythonVersion{}, false
+		}
+		numbers[i] = n
+	}
+	return pythonVersion{numbers[0], numbers[1]}, true
+}
+
+func (v pythonVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
+
+func (v pythonVersion) less(other pythonVersion) bool {
+	return v.major < other.major || (v.major == other.major && v.minor < other.minor)
+}
+
+// Python versions the release table covers
+var (
+	oldestPython = pythonVersion{3, 6}
+	newestPython = pythonVersion{3, 13}
+)
+
+// packagePython is the oldest Python the package layout installs on: its
+// pyproject.toml needs setuptools 61, which needs Python 3.7
+var packagePython = pythonVersion{3, 7}
+
+// stylePython is the oldest Python each style's generated code runs on;
+// web and api use http.server.ThreadingHTTPServer, added in 3.7
+var stylePython = map[string]pythonVersion{
+	"web": {3, 7},
+	"api": {3, 7},
+}
+
+// packageRelease is a release of a requirement and the Python versions it
+// has been tested with
+type packageRelease struct {
+	version   string
+	minPython pythonVersion
+	maxPython pythonVersion
+}
+
+// packageReleases is the offline table --pin and --python-version choose
+// from, oldest release first. Each Python version from oldestPython to
+// newestPython has a release of every package.
+var packageReleases = map[string][]packageRelease{
+	"numpy": {
+		{version: "1.19.5", minPython: pythonVersion{3, 6}, maxPython: pythonVersion{3, 9}},
+		{version: "1.21.6", minPython: pythonVersion{3, 7}, maxPython: pythonVersion{3, 10}},
+		{version: "1.24.4", minPython: pythonVersion{3, 8}, maxPython: pythonVersion{3, 11}},
+		{version: "1.26.4", minPython: pythonVersion{3, 9}, maxPython: pythonVersion{3, 12}},
+		{version: "2.1.3", minPython: pythonVersion{3, 10}, maxPython: pythonVersion{3, 13}},
+	},
+	"pandas": {
+		{version: "1.1.5", minPython: pythonVersion{3, 6}, maxPython: pythonVersion{3, 9}},
+		{version: "1.3.5", minPython: pythonVersion{3, 7}, maxPython: pythonVersion{3, 10}},
+		{version: "2.0.3", minPython: pythonVersion{3, 8}, maxPython: pythonVersion{3, 11}},
+		{version: "2.2.3", minPython: pythonVersion{3, 9}, maxPython: pythonVersion{3, 13}},
+	},
+	"scipy": {
+		{version: "1.5.4", minPython: pythonVersion{3, 6}, maxPython: pythonVersion{3, 9}},
+		{version: "1.7.3", minPython: pythonVersion{3, 7}, maxPython: pythonVersion{3, 10}},
+		{version: "1.10.1", minPython: pythonVersion{3, 8}, maxPython: pythonVersion{3, 11}},
+		{version: "1.13.1", minPython: pythonVersion{3, 9}, maxPython: pythonVersion{3, 12}},
+		{version: "1.14.1", minPython: pythonVersion{3, 10}, maxPython: pythonVersion{3, 13}},
+	},
+	"sympy": {
+		{version: "1.9", minPython: pythonVersion{3, 6}, maxPython: pythonVersion{3, 10}},
+		{version: "1.12.1", minPython: pythonVersion{3, 8}, maxPython: pythonVersion{3, 12}},
+		{version: "1.13.3", minPython: pythonVersion{3, 8}, maxPython: pythonVersion{3, 13}},
+	},
+	"plotly": {
+		{version: "5.18.0", minPython: pythonVersion{3, 6}, maxPython: pythonVersion{3, 12}},
+		{version: "5.24.1", minPython: pythonVersion{3, 8}, maxPython: pythonVersion{3, 13}},
+	},
+	"ipython": {
+		{version: "7.16.3", minPython: pythonVersion{3, 6}, maxPython: pythonVersion{3, 9}},
+		{version: "7.34.0", minPython: pythonVersion{3, 7}, maxPython: pythonVersion{3, 11}},
+		{version: "8.12.3", minPython: pythonVersion{3, 8}, maxPython: pythonVersion{3, 11}},
+		{version: "8.18.1", minPython: pythonVersion{3, 9}, maxPython: pythonVersion{3, 12}},
+		{version: "8.29.0", minPython: pythonVersion{3, 10}, maxPython: pythonVersion{3, 13}},
+	},
+}
+
+// lowerBounds are the requirements' minimum versions when no target Python
+// is set
+var lowerBounds = map[string]string{
+	"numpy":   "1.21.0",
+	"pandas":  "1.3.0",
+	"scipy":   "1.7.0",
+	"sympy":   "1.9.0",
+	"plotly":  "5.0.0",
+	"ipython": "7.0.0",
+}
+
+// supports reports whether the release was tested with the Python version
+func (r packageRelease) supports(python pythonVersion) bool {
+	return !python.less(r.minPython) && !r.maxPython.less(python)
+}
+
+// releasesFor returns the oldest and newest releases of a package that
+// support the Python version
+func releasesFor(name string, python pythonVersion) (oldest, newest packageRelease, ok bool) {
+	for _, release := range packageReleases[name] {
+		if !release.supports(python) {
+			continue
+		}
+		if !ok {
+			oldest = release
+		}
+		newest, ok = release, true
+	}
+	return oldest, newest, ok
+}
+
+// targetPython returns the Python version requirements are chosen for and
+// whether one was set
+func targetPython(config CalculatorConfig) (pythonVersion, bool) {
+	if config.Dependencies.PythonVersion == "" {
+		python, _ := parsePythonVersion(DefaultPythonVersion)
+		return python, false
+	}
+	python, ok := parsePythonVersion(config.Dependencies.PythonVersion)
+	return python, ok
+}
+
+// packages returns the Python packages the calculator needs, in
+// requirements.txt order
+func (g *Generator) packages() []string {
+	var packages []string
+	for _, library := range libraryFields(&g.config.Libraries) {
+		if *library.field && library.name != standardLibrary {
+			packages = append(packages, library.name)
+		}
+	}
+	if g.config.Features.Plotting && !g.config.Libraries.UsePlotly {
+		packages = append(packages, "plotly")
+	}
+	if g.config.UI.Style == "notebook" {
+		packages = append(packages, "ipython")
+	}
+	return packages
+}
+
+// pinned returns the requirement lines that pin each package to the newest
+// release for the target Python. They carry no --hash options: the table
+// has no digests and does not pin the packages' own dependencies, both of
+// which pip --require-hashes needs.
+func (g *Generator) pinned() []string {
+	python, _ := targetPython(g.config)
+	var lines []string
+	for _, name := range g.packages() {
+		_, release, ok := releasesFor(name, python)
+		if !ok {
+			lines = append(lines, name+">="+lowerBounds[name])
+			continue
+		}
+		lines = append(lines, name+"=="+release.version)
+	}
+	return lines
+}
+
+// pinHeader is the comment heading files with exact versions
+func (g *Generator) pinHeader() string {
+	python, _ := targetPython(g.config)
+	return fmt.Sprintf("# Exact versions for Python %s from Calculator Generator's release table", python)
+}
+
+// Constraints returns the lines of constraints.txt, or nil when it is not
+// requested or there is nothing to constrain
+func (g *Generator) Constraints() []string {
+	if !g.config.Dependencies.Constraints || len(g.packages()) == 0 {
+		return nil
+	}
+	return append([]string{g.pinHeader()}, g.pinned()...)
+}
+
+// validateDependencies checks the target Python against the generated code
+// and the release table
+func validateDependencies(config CalculatorConfig, add func(severity Severity, field, message, fix string)) {
+	deps := config.Dependencies
+	python, set := targetPython(config)
+	if deps.PythonVersion != "" && !set {
+		add(SeverityError, "dependencies.python_version", fmt.Sprintf("%q is not a Python version such as 3.12", deps.PythonVersion), "use --python-version 3.12")
+		return
+	}
+	if python.less(oldestPython) || newestPython.less(python) {
+		add(SeverityError, "dependencies.python_version", fmt.Sprintf("Python %s is not supported", python),
+			fmt.Sprintf("use a version from %s to %s", oldestPython, newestPython))
+		return
+	}
+	if minimum, ok := stylePython[config.UI.Style]; ok && python.less(minimum) {
+		add(SeverityError, "dependencies.python_version", fmt.Sprintf("the %s style needs Python %s or later", config.UI.Style, minimum),
+			fmt.Sprintf("use --python-version %s", minimum))
+	}
+	if config.Layout == "package" && python.less(packagePython) {
+		add(SeverityError, "dependencies.python_version", fmt.Sprintf("the package layout needs Python %s or later", packagePython),
+			fmt.Sprintf("use --python-version %s", packagePython))
+	}
+
+	generator := NewGenerator(config)
+	for _, name := range generator.packages() {
+		if _, _, ok := releasesFor(name, python); !ok {
+			add(SeverityError, "dependencies.python_version", fmt.Sprintf("no known %s release supports Python %s", name, python),
+				"choose another Python version or turn off the features that need "+name)
+		}
+	}
+	if deps.Pin && deps.Constraints {
+		add(SeverityWarning, "dependencies.constraints", "requirements.txt is already pinned, constraints.txt repeats it", "drop --constraints or --pin")
+	}
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRequirements(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependencies DependencyConfig
+		style        string
+		want         []string
+	}{
+		{
+			name: "lower bounds",
+			want: []string{"numpy>=1.21.0", "sympy>=1.9.0"},
+		},
+		{
+			name:         "lower bounds for a Python version",
+			dependencies: DependencyConfig{PythonVersion: "3.12"},
+			want:         []string{"numpy>=1.26.4", "sympy>=1.12.1"},
+		},
+		{
+			name:         "pinned",
+			dependencies: DependencyConfig{PythonVersion: "3.8.10", Pin: true},
+			style:        "notebook",
+			want: []string{
+				"# Exact versions for Python 3.8 from Calculator Generator's release table",
+				"numpy==1.24.4", "sympy==1.13.3", "ipython==8.12.3",
+			},
+		},
+		{
+			name:         "pinned gui",
+			dependencies: DependencyConfig{Pin: true},
+			style:        "gui",
+			want: []string{
+				"# Exact versions for Python " + DefaultPythonVersion + " from Calculator Generator's release table",
+				"numpy==2.1.3", "sympy==1.13.3", "# tkinter (included with Python)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.Features.Statistical = true
+			config.Features.EquationSolver = true
+			config.Libraries.UseNumpy = true
+			config.Libraries.UseSympy = true
+			config.Dependencies = tt.dependencies
+			if tt.style != "" {
+				config.UI.Style = tt.style
+				config.OutputFile = "calc_magic.py"
+			}
+
+			if got := NewGenerator(config).Requirements(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Requirements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEveryPythonVersionHasReleases(t *testing.T) {
+	for python := oldestPython; !newestPython.less(python); python.minor++ {
+		for name := range packageReleases {
+			if _, _, ok := releasesFor(name, python); !ok {
+				t.Errorf("no %s release for Python %s", name, python)
+			}
+		}
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	tests := []struct {
+		python string
+		style  string
+		layout string
+		valid  bool
+	}{
+		{"3.12", "cli", "script", true},
+		{"3.6", "cli", "script", true},
+		{"3.6", "web", "script", false},
+		{"3.6", "cli", "package", false},
+		{"3.7", "gui", "package", true},
+		{"3.5", "cli", "script", false},
+		{"3.14", "cli", "script", false},
+		{"three", "cli", "script", false},
+	}
+
+	for _, tt := range tests {
+		config := GetDefaultConfig()
+		config.Dependencies.PythonVersion = tt.python
+		config.UI.Style = tt.style
+		config.Layout = tt.layout
+		if err := NewGenerator(config).Validate(); (err == nil) != tt.valid {
+			t.Errorf("Python %s with the %s style and %s layout: Validate() = %v", tt.python, tt.style, tt.layout, err)
+		}
+	}
+}
@@ -12,18 +12,55 @@ import (
 // Generator handles the generation of Python calculator scripts
 type Generator struct {
 	config CalculatorConfig
+	locale Locale
 }
 
 // NewGenerator creates a new calculator generator instance
 func NewGenerator(config CalculatorConfig) *Generator {
-	return &Generator{config: config}
+	return &Generator{config: config, locale: localeFor(config)}
 }
 
-// Generate creates the Python calculator script based on the configuration
+// Generate creates the Python calculator script based on the configuration,
+// or the Python project with the package layout
 func (g *Generator) Generate() error {
+	if g.config.Layout == "package" {
+		if err := NewPackageGenerator(g.config).WritePackage(); err != nil {
+			return fmt.Errorf("package writing failed: %w", err)
+		}
+		return nil
+	}
+
+	content, err := g.Render()
+	if err != nil {
+		return err
+	}
+
+	// Write to file
+	if err := g.writeToFile(content); err != nil {
+		return fmt.Errorf("file writing failed: %w", err)
+	}
+
+	// Write the companion notebook next to the extension module
+	if g.config.UI.Style == "notebook" {
+		if err := NewNotebookGenerator(g.config).WriteNotebook(); err != nil {
+			return fmt.Errorf("notebook writing failed: %w", err)
+		}
+	}
+
+	// Generate requirements.txt
+	if err := g.generateRequirements(); err != nil {
+		return fmt.Errorf("requirements generation failed: %w", err)
+	}
+
+	return nil
+}
+
+// Render validates the configuration and returns the Python script that
+// Generate would write, without writing anything
+func (g *Generator) Render() (string, error) {
 	// Validate configuration
 	if err := g.validateConfig(); err != nil {
-		return fmt.Errorf("configuration validation failed: %w", err)
+		return "", fmt.Errorf("configuration validation failed: %w", err)
 	}
 
 	// Prepare template data
@@ -33,47 +70,59 @@ func (g *Generator) Generate() error {
 	var content string
 	var err error
 
-	if g.config.UI.Style == "gui" {
+	switch g.config.UI.Style {
+	case "gui":
 		// Generate GUI calculator
 		guiGen := NewGUIGenerator(g.config)
 		content, err = guiGen.GenerateGUICalculator()
-	} else {
+	case "web":
+		// Generate browser calculator
+		webGen := NewWebGenerator(g.config)
+		content, err = webGen.GenerateWebCalculator()
+	case "tui":
+		// Generate full-screen terminal calculator
+		tuiGen := NewTUIGenerator(g.config)
+		content, err = tuiGen.GenerateTUICalculator()
+	case "notebook":
+		// Generate IPython extension module
+		notebookGen := NewNotebookGenerator(g.config)
+		content, err = notebookGen.GenerateExtension()
+	case "api":
+		// Generate headless HTTP/JSON service
+		apiGen := NewAPIGenerator(g.config)
+		content, err = apiGen.GenerateAPIService()
+	default:
 		// Generate CLI calculator
 		content, err = g.renderTemplate(data)
 	}
 
 	if err != nil {
-		return fmt.Errorf("template rendering failed: %w", err)
-	}
-
-	// Write to file
-	if err := g.writeToFile(content); err != nil {
-		return fmt.Errorf("file writing failed: %w", err)
+		return "", fmt.Errorf("template rendering failed: %w", err)
 	}
 
-	// Generate requirements.txt
-	if err := g.generateRequirements(); err != nil {
-		return fmt.Errorf("requirements generation failed: %w", err)
-	}
+	return content, nil
+}
 
-	return nil
+// Validate checks the configuration without generating anything
+func (g *Generator) Validate() error {
+	return g.validateConfig()
 }
 
-// validateConfig validates the calculator configuration
+// validateConfig validates the calculator configuration, failing on errors
+// and ignoring warnings
 func (g *Generator) validateConfig() error {
-	if g.config.OutputFile == "" {
-		return ValidationError{Field: "output_file", Message: "output file cannot be empty"}
-	}
-
-	if g.config.ProjectName == "" {
-		return ValidationError{Field: "project_name", Message: "project name cannot be empty"}
-	}
+	return validationError(ValidateConfig(g.config))
+}
 
-	if g.config.UI.Precision < 1 || g.config.UI.Precision > 20 {
-		return ValidationError{Field: "precision", Message: "precision must be between 1 and 20"}
-	}
+// emitsRecords reports whether evaluations are described as EvaluationRecord
+// objects, which JSON output and the HTTP-based styles rely on
+func (g *Generator) emitsRecords() bool {
+	return g.config.UI.OutputFormat == "json" || g.config.UI.Style == "web" || g.config.UI.Style == "api"
+}
 
-	return nil
+// hasREPL reports whether the generated script runs the interactive prompt
+func (g *Generator) hasREPL() bool {
+	return g.config.Interactive && (g.config.UI.Style == "" || g.config.UI.Style == "cli")
 }
 
 // prepareTemplateData prepares data for template rendering
@@ -89,6 +138,23 @@ func (g *Generator) prepareTemplateData() TemplateData {
 		MainContent: mainContent,
 		Version:     "1.0.0",
 		Timestamp:   time.Now().Format("2006-01-02 15:04:05"),
+		Shebang:     true,
+	}
+}
+
+// styleTemplateData prepares the template data of a style built on the
+// shared evaluation engine: the calculator's imports followed by the style's
+// own, the calculator functions, and CalculatorCore before the style's main
+// content
+func (g *Generator) styleTemplateData(imports []string, mainContent string) TemplateData {
+	return TemplateData{
+		Config:      g.config,
+		Imports:     append(g.generateImports(), imports...),
+		Functions:   g.generateFunctions(),
+		MainContent: g.generateCoreContent() + "\n\n" + mainContent,
+		Version:     "1.0.0",
+		Timestamp:   time.Now().Format("2006-01-02 15:04:05"),
+		Shebang:     true,
 	}
 }
 
@@ -99,16 +165,38 @@ func (g *Generator) generateImports() []string {
 	// Standard library imports
 	imports = append(imports, "import sys")
 	imports = append(imports, "import os")
+	imports = append(imports, "import re")
 
 	if g.config.Libraries.UseMath {
 		imports = append(imports, "import math")
 	}
 
-	if g.config.Features.History {
+	if g.hasREPL() {
+		imports = append(imports, "import atexit")
+		imports = append(imports, `try:
+    import readline
+except ImportError:
+    readline = None`)
+	}
+
+	if g.config.Features.History || g.config.Features.Memory || g.emitsRecords() {
 		imports = append(imports, "import json")
+	}
+
+	if g.config.Features.History {
+		imports = append(imports, "import csv")
+		imports = append(imports, "import io")
 		imports = append(imports, "from datetime import datetime")
 	}
 
+	if g.emitsRecords() {
+		imports = append(imports, "import time")
+	}
+
+	if g.hasREPL() && g.config.UI.OutputFormat == "json" {
+		imports = append(imports, "from contextlib import redirect_stdout")
+	}
+
 	// Third-party library imports
 	if g.config.Libraries.UseNumpy {
 		imports = append(imports, "import numpy as np")
@@ -119,8 +207,7 @@ func (g *Generator) generateImports() []string {
 	}
 
 	if g.config.Libraries.UseScipy {
-		imports = append(imports, "import scipy as sp")
-		imports = append(imports, "from scipy import stats, optimize, integrate")
+		imports = append(imports, "from scipy import integrate, optimize")
 	}
 
 	if g.config.Libraries.UseSympy {
@@ -140,51 +227,86 @@ func (g *Generator) generateImports() []string {
 	return imports
 }
 
+// functionGroup is the Python code a scientific feature contributes, or
+// code shared by the whole calculator when feature is empty
+type functionGroup struct {
+	feature   string // configuration name such as "linear_algebra"
+	functions []string
+}
+
 // generateFunctions creates calculator function implementations
 func (g *Generator) generateFunctions() []string {
 	var functions []string
+	for _, group := range g.functionGroups() {
+		functions = append(functions, group.functions...)
+	}
+	return functions
+}
+
+// functionGroups creates the calculator functions grouped by the feature
+// they belong to, in script order
+func (g *Generator) functionGroups() []functionGroup {
+	// Number format of the locale
+	groups := []functionGroup{{"", []string{generateLocaleFunctions(g.locale)}}}
 
 	// Basic arithmetic functions
 	if g.config.Features.BasicArithmetic {
-		functions = append(functions, g.generateBasicArithmetic()...)
+		groups = append(groups, functionGroup{"", g.generateBasicArithmetic()})
 	}
 
 	// Memory functions
 	if g.config.Features.Memory {
-		functions = append(functions, g.generateMemoryFunctions()...)
+		groups = append(groups, functionGroup{"", g.generateMemoryFunctions()})
 	}
 
 	// History functions
 	if g.config.Features.History {
-		functions = append(functions, g.generateHistoryFunctions()...)
+		groups = append(groups, functionGroup{"", g.generateHistoryFunctions()})
 	}
 
 	// Scientific functions
 	if g.config.Features.Trigonometric {
-		functions = append(functions, g.generateTrigonometricFunctions()...)
+		groups = append(groups, functionGroup{"trigonometric", g.generateTrigonometricFunctions()})
 	}
 
 	if g.config.Features.Logarithmic {
-		functions = append(functions, g.generateLogarithmicFunctions()...)
+		groups = append(groups, functionGroup{"logarithmic", g.generateLogarithmicFunctions()})
+	}
+
+	if g.config.Features.Exponential {
+		groups = append(groups, functionGroup{"exponential", g.generateExponentialFunctions()})
 	}
 
 	if g.config.Features.Statistical && g.config.Libraries.UseNumpy {
-		functions = append(functions, g.generateStatisticalFunctions()...)
+		groups = append(groups, functionGroup{"statistical", g.generateStatisticalFunctions()})
 	}
 
 	if g.config.Features.LinearAlgebra && g.config.Libraries.UseNumpy {
-		functions = append(functions, g.generateLinearAlgebraFunctions()...)
+		groups = append(groups, functionGroup{"linear_algebra", g.generateLinearAlgebraFunctions()})
+	}
+
+	if g.config.Features.Calculus && g.config.Libraries.UseScipy {
+		groups = append(groups, functionGroup{"calculus", g.generateCalculusFunctions()})
 	}
 
 	if g.config.Features.Plotting && g.config.Libraries.UsePlotly {
-		functions = append(functions, g.generatePlottingFunctions()...)
+		groups = append(groups, functionGroup{"plotting", g.generatePlottingFunctions()})
 	}
 
 	if g.config.Features.EquationSolver && g.config.Libraries.UseSympy {
-		functions = append(functions, g.generateEquationSolverFunctions()...)
+		groups = append(groups, functionGroup{"equation_solver", g.generateEquationSolverFunctions()})
 	}
 
-	return functions
+	if g.config.Features.DataAnalysis && g.config.Libraries.UsePandas {
+		groups = append(groups, functionGroup{"data_analysis", g.generateDataAnalysisFunctions()})
+	}
+
+	// Structured output helpers
+	if g.emitsRecords() {
+		groups = append(groups, functionGroup{"", g.generateOutputFunctions()})
+	}
+
+	return groups
 }
 
 // generateBasicArithmetic creates basic arithmetic functions
@@ -205,7 +327,7 @@ func (g *Generator) generateBasicArithmetic() []string {
 		`def divide(a, b):
     """Division operation"""
     if b == 0:
-        raise ValueError("Cannot divide by zero")
+        raise ValueError("` + g.locale.T("eval.divide_by_zero") + `")
     return a / b`,
 
 		`def power(a, b):
@@ -215,77 +337,19 @@ func (g *Generator) generateBasicArithmetic() []string {
 		`def modulo(a, b):
     """Modulo operation"""
     if b == 0:
-        raise ValueError("Cannot calculate modulo with zero")
+        raise ValueError("` + g.locale.T("eval.modulo_by_zero") + `")
     return a % b`,
 	}
 }
 
 // generateMemoryFunctions creates memory-related functions
 func (g *Generator) generateMemoryFunctions() []string {
-	return []string{
-		`class Memory:
-    """Calculator memory functionality"""
-    def __init__(self):
-        self.value = 0
-
-    def store(self, value):
-        """Store value in memory"""
-        self.value = value
-        return f"Stored {value} in memory"
-
-    def recall(self):
-        """Recall value from memory"""
-        return self.value
-
-    def clear(self):
-        """Clear memory"""
-        self.value = 0
-        return "Memory cleared"
-
-    def add_to_memory(self, value):
-        """Add value to memory"""
-        self.value += value
-        return f"Added {value} to memory, new value: {self.value}"`,
-	}
+	return []string{generateMemoryClass(g.locale)}
 }
 
 // generateHistoryFunctions creates history-related functions
 func (g *Generator) generateHistoryFunctions() []string {
-	return []string{
-		`class History:
-    """Calculator history functionality"""
-    def __init__(self, max_entries=100):
-        self.entries = []
-        self.max_entries = max_entries
-
-    def add_entry(self, operation, result):
-        """Add calculation to history"""
-        entry = {
-            "timestamp": datetime.now().isoformat(),
-            "operation": operation,
-            "result": result
-        }
-        self.entries.append(entry)
-
-        # Keep only max_entries
-        if len(self.entries) > self.max_entries:
-            self.entries = self.entries[-self.max_entries:]
-
-    def get_history(self, count=10):
-        """Get recent history entries"""
-        return self.entries[-count:]
-
-    def clear_history(self):
-        """Clear calculation history"""
-        self.entries = []
-        return "History cleared"
-
-    def save_to_file(self, filename="calculator_history.json"):
-        """Save history to file"""
-        with open(filename, 'w') as f:
-            json.dump(self.entries, f, indent=2)
-        return f"History saved to {filename}"`,
-	}
+	return []string{generateHistoryClass(g.locale)}
 }
 
 // generateTrigonometricFunctions creates trigonometric functions
@@ -340,7 +404,7 @@ func (g *Generator) generateLogarithmicFunctions() []string {
 		`def log(x, base=10):
     """Logarithm function"""
     if x <= 0:
-        raise ValueError("Logarithm input must be positive")
+        raise ValueError("` + g.locale.T("eval.log_domain") + `")
     if base == math.e:
         return math.log(x)
     return math.log(x, base)`,
@@ -348,23 +412,38 @@ func (g *Generator) generateLogarithmicFunctions() []string {
 		`def ln(x):
     """Natural logarithm"""
     if x <= 0:
-        raise ValueError("Natural logarithm input must be positive")
+        raise ValueError("` + g.locale.T("eval.ln_domain") + `")
     return math.log(x)`,
 
 		`def log10(x):
     """Base-10 logarithm"""
     if x <= 0:
-        raise ValueError("Logarithm input must be positive")
+        raise ValueError("` + g.locale.T("eval.log_domain") + `")
     return math.log10(x)`,
 
 		`def log2(x):
     """Base-2 logarithm"""
     if x <= 0:
-        raise ValueError("Logarithm input must be positive")
+        raise ValueError("` + g.locale.T("eval.log_domain") + `")
     return math.log2(x)`,
 	}
 }
 
+// generateExponentialFunctions creates exponential and root functions
+func (g *Generator) generateExponentialFunctions() []string {
+	return []string{
+		`def exp(x):
+    """Exponential function e**x"""
+    return math.exp(x)`,
+
+		`def root(x, n=2):
+    """n-th root; odd roots of negative numbers are negative"""
+    if x < 0 and n % 2 == 1:
+        return -((-x) ** (1 / n))
+    return x ** (1 / n)`,
+	}
+}
+
 // generateStatisticalFunctions creates statistical functions
 func (g *Generator) generateStatisticalFunctions() []string {
 	return []string{
@@ -433,6 +512,39 @@ func (g *Generator) generatePlottingFunctions() []string {
 	}
 }
 
+// generateCalculusFunctions creates numerical calculus functions. They take
+// the function as a lambda, such as integral(lambda x: x**2, 0, 1).
+func (g *Generator) generateCalculusFunctions() []string {
+	return []string{
+		`def integral(f, a, b):
+    """Definite integral of f from a to b"""
+    value, _ = integrate.quad(f, a, b)
+    return value`,
+
+		`def derivative(f, x, h=1e-6):
+    """Derivative of f at x by central differences"""
+    return (f(x + h) - f(x - h)) / (2 * h)`,
+
+		`def find_root(f, a, b):
+    """Root of f between a and b, where f(a) and f(b) differ in sign"""
+    return optimize.brentq(f, a, b)`,
+	}
+}
+
+// generateDataAnalysisFunctions creates data analysis functions
+func (g *Generator) generateDataAnalysisFunctions() []string {
+	return []string{
+		`def quantile(data, q):
+    """q-quantile of data, such as quantile(data, 0.9)"""
+    return float(pd.Series(data, dtype=float).quantile(q))`,
+
+		`def iqr(data):
+    """Interquartile range of data"""
+    series = pd.Series(data, dtype=float)
+    return float(series.quantile(0.75) - series.quantile(0.25))`,
+	}
+}
+
 // generateEquationSolverFunctions creates equation solver functions
 func (g *Generator) generateEquationSolverFunctions() []string {
 	return []string{
@@ -457,40 +569,112 @@ func (g *Generator) generateEquationSolverFunctions() []string {
 	}
 }
 
-// generateMainContent creates the main calculator interface
-func (g *Generator) generateMainContent() string {
-	var content strings.Builder
-
-	content.WriteString(`class Calculator:
-    """Main calculator class"""
-
-    def __init__(self):
-        self.precision = ` + fmt.Sprintf("%d", g.config.UI.Precision) + `
-        self.angle_unit = "` + g.config.UI.AngleUnit + `"
-`)
-
-	if g.config.Features.Memory {
-		content.WriteString("        self.memory = Memory()\n")
+// generateOutputFunctions creates helpers that build EvaluationRecord objects
+func (g *Generator) generateOutputFunctions() []string {
+	fields := EvaluationRecordFields()
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = `"` + field + `"`
 	}
 
-	if g.config.Features.History {
-		content.WriteString("        self.history = History()\n")
+	return []string{
+		`# Field order follows the EvaluationRecord schema (see: calculator-generator schema)
+RECORD_FIELDS = (` + strings.Join(quoted, ", ") + `)`,
+
+		`def result_type(value):
+    """Classify a result as int, float, complex, matrix or symbolic"""
+    if isinstance(value, bool):
+        return "int"
+    if isinstance(value, int):
+        return "int"
+    if isinstance(value, float):
+        return "float"
+    if isinstance(value, complex):
+        return "complex"
+    if hasattr(value, "tolist") and not isinstance(value.tolist(), (int, float, complex)):
+        return "matrix"
+    if isinstance(value, (list, tuple)):
+        return "matrix"
+    if hasattr(value, "tolist"):
+        return result_type(value.tolist())
+    return "symbolic"`,
+
+		`def to_json_value(value):
+    """Convert a result into a JSON-compatible value"""
+    if isinstance(value, bool):
+        return int(value)
+    if isinstance(value, int):
+        return value
+    if isinstance(value, float):
+        return value if value == value and abs(value) != float("inf") else None
+    if isinstance(value, complex):
+        return {"real": to_json_value(value.real), "imag": to_json_value(value.imag)}
+    if hasattr(value, "tolist"):
+        return to_json_value(value.tolist())
+    if isinstance(value, (list, tuple)):
+        return [to_json_value(item) for item in value]
+    return str(value)`,
+
+		`def make_record(user_input, expression, value, formatted, unit, error, duration_ms):
+    """Build an evaluation record matching RECORD_FIELDS"""
+    failed = error is not None
+    values = (
+        user_input,
+        expression,
+        None if failed else to_json_value(value),
+        None if failed else result_type(value),
+        None if failed else formatted,
+        unit,
+        error,
+        round(duration_ms, 3),
+    )
+    return dict(zip(RECORD_FIELDS, values))`,
 	}
+}
+
+// generateMainContent creates the evaluation engine and the command line
+// interface built on it
+func (g *Generator) generateMainContent() string {
+	return g.generateCoreContent() + "\n\n" + g.generateCLIContent()
+}
+
+// generateCLIContent creates the command line interface built on
+// CalculatorCore: the theme's colour helpers and the Calculator class
+func (g *Generator) generateCLIContent() string {
+	var content strings.Builder
 
+	content.WriteString(g.generateANSITheme())
 	content.WriteString(`
-    def format_result(self, result):
-        """Format calculation result"""
-        if isinstance(result, (int, float)):
-            return round(result, self.precision)
-        return result
+
+class Calculator(CalculatorCore):
+    """Command line calculator"""
 
     def run(self):
         """Run the calculator interface"""
 `)
 
+	// In JSON mode stdout carries one record per line, so decorations are
+	// only shown when a person is at the terminal
+	jsonOutput := g.config.UI.OutputFormat == "json"
+
 	if g.config.UI.ShowBanner {
-		content.WriteString(`        self.show_banner()
+		if jsonOutput {
+			content.WriteString(`        if sys.stdin.isatty():
+            self.show_banner()
+`)
+		} else {
+			content.WriteString(`        self.show_banner()
 `)
+		}
+	}
+
+	// notes prints a command's output, which in JSON mode goes to stderr so
+	// it never mixes with the records
+	notes := func(call string) string {
+		if jsonOutput {
+			return "with redirect_stdout(sys.stderr):\n                        " + call
+		}
+		return call
 	}
 
 	if g.config.Interactive {
@@ -498,60 +682,112 @@ func (g *Generator) generateMainContent() string {
 
     def interactive_mode(self):
         """Interactive calculator mode"""
-        print("Calculator started. Type 'help' for commands, 'quit' to exit.")
+`)
+
+		if jsonOutput {
+			content.WriteString(`        # Only evaluation records are written here, one per line
+        self.records = sys.stdout
+        prompt = paint("calc> ", "operators", prompt=True) if sys.stdin.isatty() else ""
+        if prompt:
+            print("` + g.locale.T("cli.started") + `")
+`)
+		} else {
+			content.WriteString(`        prompt = paint("calc> ", "operators", prompt=True)
+        print("` + g.locale.T("cli.started") + `")
+`)
+		}
+
+		content.WriteString(`        self.setup_readline()
 
         while True:
             try:
-                user_input = input("calc> ").strip()
+                user_input = self.read_input(prompt)
 
-                if user_input.lower() in ['quit', 'exit', 'q']:
+                if not user_input:
+                    continue
+                elif user_input.lower() in ['quit', 'exit', 'q']:
                     break
                 elif user_input.lower() == 'help':
-                    self.show_help()
+                    ` + notes("self.show_help()") + `
                 elif user_input.lower() == 'clear':
-                    os.system('cls' if os.name == 'nt' else 'clear')
 `)
+		if jsonOutput {
+			content.WriteString(`                    if sys.stdout.isatty():
+                        os.system('cls' if os.name == 'nt' else 'clear')
+`)
+		} else {
+			content.WriteString(`                    os.system('cls' if os.name == 'nt' else 'clear')
+`)
+		}
 
 		if g.config.Features.Memory {
 			content.WriteString(`                elif user_input.lower().startswith('mem'):
-                    self.handle_memory_commands(user_input)
+                    ` + notes("self.handle_memory_commands(user_input)") + `
 `)
 		}
 
 		if g.config.Features.History {
 			content.WriteString(`                elif user_input.lower().startswith('hist'):
-                    self.handle_history_commands(user_input)
+                    ` + notes("self.handle_history_commands(user_input)") + `
 `)
 		}
 
 		content.WriteString(`                else:
-                    result = self.evaluate_expression(user_input)
-                    formatted_result = self.format_result(result)
-                    print(f"Result: {formatted_result}")
+                    self.process_expression(user_input)
 `)
 
-		if g.config.Features.History {
-			content.WriteString(`                    self.history.add_entry(user_input, formatted_result)
-`)
+		errorOutput := ""
+		if jsonOutput {
+			errorOutput = ", file=sys.stderr"
 		}
-
 		content.WriteString(`
-            except KeyboardInterrupt:
-                print("\nGoodbye!")
+            except (KeyboardInterrupt, EOFError):
+                if prompt:
+                    print("\n` + g.locale.T("cli.goodbye") + `")
                 break
             except Exception as e:
-                print(f"Error: {e}")
+                print(paint(f"` + g.locale.T("eval.error") + `", "error")` + errorOutput + `)
+
+    def process_expression(self, user_input):
+        """Evaluate an expression, print the outcome and record it"""
+`)
+
+		if jsonOutput {
+			content.WriteString(`        record = self.evaluate_record(user_input)
+        print(json.dumps(record), file=self.records, flush=True)
+`)
+
+			if g.config.Features.History {
+				content.WriteString(`        if record["error"] is None:
+            self.history.add_entry(user_input, self.format_result(self.results[-1]))
+`)
+			}
+		} else {
+			content.WriteString(`        result = self.evaluate_expression(user_input)
+        number = self.remember_result(result)
+        formatted_result = self.format_result(result)
+        print(f"` + g.locale.T("cli.result") + ` {paint(format_localized(formatted_result), 'display')}  [_{number}]")
+`)
+
+			if g.config.Features.History {
+				content.WriteString(`        self.history.add_entry(user_input, formatted_result)
 `)
+			}
+		}
+	}
+
+	if g.config.Interactive {
+		content.WriteString(g.generateReadlineMethods())
 	}
 
 	if g.config.UI.ShowBanner {
 		content.WriteString(`
     def show_banner(self):
         """Display calculator banner"""
-        print("="*50)
-        print(f"  ` + g.config.ProjectName + `")
-        print(f"  ` + g.config.Description + `")
-        print("="*50)
+        print(paint("="*50, "functions"))
+        print(paint(` + pythonString("  "+g.config.ProjectName) + `, "functions"))
+        print(` + pythonString("  "+g.config.Description) + `)
+        print(paint("="*50, "functions"))
 `)
 	}
 
@@ -560,157 +796,530 @@ func (g *Generator) generateMainContent() string {
     def show_help(self):
         """Display help information"""
         help_text = """
-Available commands:
-  Basic operations: +, -, *, /, **, %
-  Functions: sin(), cos(), tan(), log(), ln(), sqrt()
+` + g.locale.T("cli.help.title") + `
+` + g.locale.T("cli.help.operations") + `
+` + g.locale.T("cli.help.functions") + `
+` + g.locale.T("cli.help.results") + `
+` + g.locale.T("cli.help.continue") + `
 `)
 
+		if g.locale.DecimalSeparator != "." {
+			content.WriteString(strings.NewReplacer(
+				"{example}", g.locale.NumberExample(),
+				"{separator}", g.locale.ArgumentSeparator,
+			).Replace(g.locale.T("cli.help.numbers")) + "\n")
+		}
+
 		if g.config.Features.Memory {
-			content.WriteString(`  Memory: mem store <value>, mem recall, mem clear
-`)
+			content.WriteString(g.locale.T("cli.help.memory") + "\n" + g.locale.T("cli.help.memory_more") + "\n")
 		}
 
 		if g.config.Features.History {
-			content.WriteString(`  History: hist show, hist clear, hist save
-`)
+			content.WriteString(g.locale.T("cli.help.history") + "\n" + g.locale.T("cli.help.history_more") + "\n")
+		}
+
+		if g.config.Interactive {
+			content.WriteString(g.locale.T("cli.help.editing") + "\n")
 		}
 
-		content.WriteString(`  Other: help, clear, quit
+		content.WriteString(g.locale.T("cli.help.other") + `
         """
         print(help_text)
 `)
 	}
 
-	content.WriteString(`
-    def evaluate_expression(self, expression):
-        """Evaluate mathematical expression"""
-        # Basic expression evaluation
+	if g.config.Features.Memory {
+		content.WriteString(`
+    def handle_memory_commands(self, command):
+        """Handle memory-related commands"""
+        usage = "` + g.locale.T("cli.memory_usage") + `"
+        parts = command.split()
+        if len(parts) < 2:
+            print(usage)
+            return
+
+        action = {"ms": "store", "mr": "recall", "mc": "clear", "m+": "add", "m-": "sub"}.get(
+            parts[1].lower(), parts[1].lower())
+        args = parts[2:]
         try:
-            # Replace common functions
-            expression = expression.replace('^', '**')
-            `)
+            if action in ("store", "add", "sub"):
+                name, value = self.parse_memory_arguments(args)
+                if action == "store":
+                    print(paint(self.memory.store(value, name), "memory"))
+                elif action == "add":
+                    print(paint(self.memory.add(value, name), "memory"))
+                else:
+                    print(paint(self.memory.subtract(value, name), "memory"))
+            elif action == "recall":
+                name = args[0] if args else Memory.DEFAULT_REGISTER
+                print(paint(f"{name} = {format_localized(self.memory.recall(name))}", "memory"))
+            elif action == "clear":
+                print(paint(self.memory.clear(args[0] if args else None), "memory"))
+            elif action == "list":
+                registers = self.memory.list()
+                if not registers:
+                    print("` + g.locale.T("memory.empty") + `")
+                for name, value in registers:
+                    print(paint(f"  {name} = {format_localized(value)}", "memory"))
+            else:
+                print(usage)
+        except ValueError as e:
+            print(paint(f"` + g.locale.T("cli.memory_error") + `", "error"))
+
+    def parse_memory_arguments(self, args):
+        """Split memory arguments into a register name and a value.
+
+        The value may be any expression; without one, the last result is used.
+        """
+        name = Memory.DEFAULT_REGISTER
+        context = self.build_eval_context()
+        if args and Memory.is_valid_name(args[0]) and (args[0] not in context or args[0] in self.memory.registers):
+            name, args = args[0], args[1:]
+
+        if args:
+            return name, self.evaluate_expression(" ".join(args))
+        if self.results:
+            return name, self.results[-1]
+        raise ValueError("` + g.locale.T("cli.no_value") + `")
+`)
+	}
+
+	if g.config.Features.History {
+		content.WriteString(`
+    def handle_history_commands(self, command):
+        """Handle history-related commands"""
+        usage = "` + g.locale.T("cli.history_usage") + `"
+        parts = command.split()
+        if len(parts) < 2:
+            print(usage)
+            return
+
+        action = parts[1].lower()
+        args = parts[2:]
+        if action == "show":
+            count = 10
+            if args:
+                try:
+                    count = int(args[0])
+                except ValueError:
+                    pass
+            self.print_history(self.history.get_history(count))
+        elif action == "search" and args:
+            matches = self.history.search(" ".join(args))
+            if not matches:
+                print("` + g.locale.T("cli.no_matches") + `")
+            self.print_history(matches)
+        elif action == "rerun" and args:
+            try:
+                entry = self.history.get_entry(int(args[0].lstrip("#")))
+            except ValueError as e:
+                print(paint(f"` + g.locale.T("cli.history_error") + `", "error"))
+                return
+            print(f"> {entry['expression']}")
+            self.process_expression(entry['expression'])
+        elif action == "export":
+            fmt, filename = "json", None
+            while args:
+                arg = args.pop(0)
+                if arg in ("--format", "-f") and args:
+                    fmt = args.pop(0).lower()
+                elif arg.startswith("--format="):
+                    fmt = arg.split("=", 1)[1].lower()
+                else:
+                    filename = arg
+            try:
+                if filename:
+                    print(self.history.save_to_file(filename, fmt))
+                else:
+                    print(self.history.export(fmt), end="")
+            except (ValueError, OSError) as e:
+                print(paint(f"` + g.locale.T("cli.history_error") + `", "error"))
+        elif action == "clear":
+            print(self.history.clear_history())
+        elif action == "save":
+            filename = args[0] if args else "calculator_history.json"
+            print(self.history.save_to_file(filename))
+        else:
+            print(usage)
+
+    def print_history(self, entries):
+        """Print history entries"""
+        for entry in entries:
+            print(f"#{entry['index']} {entry['timestamp']}: {entry['expression']} = {entry['result']}")
+`)
+	}
+
+	return content.String()
+}
+
+// generateCoreContent creates the evaluation engine shared by every
+// calculator interface: settings, memory, history, results and evaluation
+func (g *Generator) generateCoreContent() string {
+	var content strings.Builder
+
+	content.WriteString(`class CalculatorCore:
+    """Evaluation engine shared by every calculator interface"""
+
+    def __init__(self):
+        self.precision = ` + fmt.Sprintf("%d", g.config.UI.Precision) + `
+        self.angle_unit = ` + pythonString(g.config.UI.AngleUnit) + `
+        self.results = []
+`)
+
+	if g.config.Features.Memory {
+		content.WriteString("        self.memory = " + memoryConstructor(g.config) + "\n")
+	}
+
+	if g.config.Features.History {
+		content.WriteString("        self.history = " + historyConstructor(g.config) + "\n")
+	}
+
+	content.WriteString(`
+    def format_result(self, result):
+        """Format calculation result"""
+        if isinstance(result, (int, float)):
+            return round(result, self.precision)
+        return result
+
+    def normalize_expression(self, expression):
+        """Normalize user input into a Python expression"""
+        expression = parse_localized(expression).replace('^', '**').strip()
+
+        # A leading operator continues from the last result ("* 2" -> "ans * 2").
+        # "-" only continues when followed by a space so "-3" stays a number.
+        if self.results and (expression[:1] in ('*', '/', '%', '+') or expression.startswith('- ')):
+            expression = "ans " + expression
+        return expression
+
+    def remember_result(self, result):
+        """Store a result so later expressions can use ans and _1, _2, ..."""
+        self.results.append(result)
+        return len(self.results)
+
+    def build_eval_context(self):
+        """Build the names available inside expressions"""`)
 
 	if g.config.Libraries.UseMath {
 		content.WriteString(`
-            # Add math functions to evaluation context
-            safe_dict = {
-                "__builtins__": {},
-                "abs": abs, "round": round, "min": min, "max": max,
-                "sqrt": math.sqrt, "pi": math.pi, "e": math.e
-            }`)
+        # Add math functions to evaluation context
+        safe_dict = {
+            "__builtins__": {},
+            "abs": abs, "round": round, "min": min, "max": max,
+            "sqrt": math.sqrt, "pi": math.pi, "e": math.e
+        }`)
 
 		// Add trigonometric functions if enabled
 		if g.config.Features.Trigonometric {
 			content.WriteString(`
-            safe_dict.update({
-                "sin": lambda x: sin(x, self.angle_unit),
-                "cos": lambda x: cos(x, self.angle_unit),
-                "tan": lambda x: tan(x, self.angle_unit),
-                "asin": lambda x: asin(x, self.angle_unit),
-                "acos": lambda x: acos(x, self.angle_unit),
-                "atan": lambda x: atan(x, self.angle_unit)
-            })`)
+        safe_dict.update({
+            "sin": lambda x: sin(x, self.angle_unit),
+            "cos": lambda x: cos(x, self.angle_unit),
+            "tan": lambda x: tan(x, self.angle_unit),
+            "asin": lambda x: asin(x, self.angle_unit),
+            "acos": lambda x: acos(x, self.angle_unit),
+            "atan": lambda x: atan(x, self.angle_unit)
+        })`)
 		}
 
 		// Add logarithmic functions if enabled
 		if g.config.Features.Logarithmic {
 			content.WriteString(`
-            safe_dict.update({
-                "log": log, "ln": ln, "log10": log10, "log2": log2
-            })`)
+        safe_dict.update({
+            "log": log, "ln": ln, "log10": log10, "log2": log2
+        })`)
+		}
+
+		if g.config.Features.Exponential {
+			content.WriteString(`
+        safe_dict.update({"exp": exp, "root": root})`)
 		}
 	} else {
 		content.WriteString(`
-            safe_dict = {
-                "__builtins__": {},
-                "abs": abs, "round": round, "min": min, "max": max
-            }`)
+        safe_dict = {
+            "__builtins__": {},
+            "abs": abs, "round": round, "min": min, "max": max
+        }`)
 	}
 
 	if g.config.Libraries.UseNumpy {
 		content.WriteString(`
-            safe_dict.update({
-                "np": np, "array": np.array, "mean": mean,
-                "median": median, "std": std
-            })`)
+        safe_dict.update({"np": np, "array": np.array})`)
+
+		if g.config.Features.Statistical {
+			content.WriteString(`
+        safe_dict.update({"mean": mean, "median": median, "std": std})`)
+		}
+
+		if g.config.Features.LinearAlgebra {
+			content.WriteString(`
+        safe_dict.update({
+            "matrix_multiply": matrix_multiply, "matrix_inverse": matrix_inverse,
+            "matrix_determinant": matrix_determinant, "eigenvalues": eigenvalues
+        })`)
+		}
+
+		if g.config.Features.Plotting && g.config.Libraries.UsePlotly {
+			content.WriteString(`
+        safe_dict.update({"plot_function": plot_function, "plot_data": plot_data})`)
+		}
+	}
+
+	if g.config.Features.Calculus && g.config.Libraries.UseScipy {
+		content.WriteString(`
+        safe_dict.update({"integral": integral, "derivative": derivative, "find_root": find_root})`)
+	}
+
+	if g.config.Features.DataAnalysis && g.config.Libraries.UsePandas {
+		content.WriteString(`
+        safe_dict.update({"quantile": quantile, "iqr": iqr})`)
+	}
+
+	if g.config.Features.EquationSolver && g.config.Libraries.UseSympy {
+		content.WriteString(`
+        safe_dict.update({
+            "solve_equation": solve_equation, "differentiate": differentiate,
+            "integrate_symbolic": integrate_symbolic
+        })`)
 	}
 
 	content.WriteString(`
 
-            return eval(expression, safe_dict)
-        except Exception as e:
-            raise ValueError(f"Invalid expression: {e}")
 `)
 
 	if g.config.Features.Memory {
 		content.WriteString(`
-    def handle_memory_commands(self, command):
-        """Handle memory-related commands"""
-        parts = command.split()
-        if len(parts) < 2:
-            print("Memory commands: mem store <value>, mem recall, mem clear")
-            return
+        # Memory registers are usable by name but never shadow functions
+        for name, value in self.memory.registers.items():
+            safe_dict.setdefault(name, value)
+`)
+	}
 
-        action = parts[1].lower()
-        if action == "store" and len(parts) > 2:
-            try:
-                value = float(parts[2])
-                print(self.memory.store(value))
-            except ValueError:
-                print("Invalid value for memory storage")
-        elif action == "recall":
-            print(f"Memory: {self.memory.recall()}")
-        elif action == "clear":
-            print(self.memory.clear())
-        else:
-            print("Unknown memory command")
+	content.WriteString(`
+        # Previous results: ans is the latest, _1, _2, ... are numbered
+        if self.results:
+            safe_dict["ans"] = self.results[-1]
+        for number, value in enumerate(self.results, start=1):
+            safe_dict[f"_{number}"] = value
+        return safe_dict
+
+    def evaluate_expression(self, expression):
+        """Evaluate mathematical expression"""
+        try:
+            return eval(self.normalize_expression(expression), self.build_eval_context())
+        except ZeroDivisionError:
+            raise ValueError("` + g.locale.T("eval.divide_by_zero") + `")
+        except Exception as e:
+            raise ValueError(explain_arguments(expression, e) or f"` + g.locale.T("eval.invalid") + `")
+`)
+
+	if g.emitsRecords() {
+		content.WriteString(`
+    def evaluate_record(self, user_input):
+        """Evaluate input and describe the outcome as an evaluation record"""
+        expression = self.normalize_expression(user_input)
+        start = time.perf_counter()
+        value, formatted, error = None, None, None
+        try:
+            value = self.evaluate_expression(user_input)
+            self.remember_result(value)
+            formatted = format_localized(self.format_result(value))
+        except Exception as e:
+            error = str(e)
+        duration_ms = (time.perf_counter() - start) * 1000
+        return make_record(user_input, expression, value, formatted, None, error, duration_ms)
 `)
 	}
 
+	return content.String()
+}
+
+// generateANSITheme creates the colour helpers the command line calculator
+// uses to apply the theme. Buttons have no counterpart in a terminal, so the
+// operator, function and memory roles use their button colour as a text
+// accent, and results are shown as a small display in the display colours.
+func (g *Generator) generateANSITheme() string {
+	theme := g.config.UI.ResolveTheme()
+	colors := theme.Colors
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("# ANSI colours from the %q theme\nTHEME_ANSI = {\n", theme.Name))
+	content.WriteString(fmt.Sprintf("    \"display\": \"%s;%s\",\n", ansiColor(colors.Display.Foreground, false), ansiColor(colors.Display.Background, true)))
+	content.WriteString(fmt.Sprintf("    \"operators\": \"%s\",\n", ansiColor(colors.Operators.Background, false)))
+	content.WriteString(fmt.Sprintf("    \"functions\": \"%s\",\n", ansiColor(colors.Functions.Background, false)))
+	content.WriteString(fmt.Sprintf("    \"memory\": \"%s\",\n", ansiColor(colors.Memory.Background, false)))
+	content.WriteString(fmt.Sprintf("    \"error\": \"%s\",\n", ansiColor(colors.Error.Foreground, false)))
+	content.WriteString(`}
+
+# Colour is only used on a terminal and can be turned off with NO_COLOR
+USE_COLOR = sys.stdout.isatty() and "NO_COLOR" not in os.environ
+
+
+def paint(text, role, prompt=False):
+    """Wrap text in the theme colour for role"""
+    if not USE_COLOR:
+        return text
+    start, end = f"\033[{THEME_ANSI[role]}m", "\033[0m"
+    if prompt:
+        # Mark the escape codes as zero-width so readline keeps the cursor in place
+        start, end = f"\001{start}\002", f"\001{end}\002"
+    return f"{start}{text}{end}"`)
+
+	return content.String()
+}
+
+// generateReadlineMethods creates line editing, completion and continuation
+// support for the interactive prompt. Everything degrades to plain input()
+// when the readline module is unavailable (for example on Windows).
+func (g *Generator) generateReadlineMethods() string {
+	commands := []string{"help", "clear", "quit", "exit"}
+	if g.config.Features.Memory {
+		commands = append(commands, "mem")
+	}
 	if g.config.Features.History {
-		content.WriteString(`
-    def handle_history_commands(self, command):
-        """Handle history-related commands"""
-        parts = command.split()
-        if len(parts) < 2:
-            print("History commands: hist show, hist clear, hist save")
+		commands = append(commands, "hist")
+	}
+
+	return `
+    COMMANDS = ["` + strings.Join(commands, `", "`) + `"]
+
+    def setup_readline(self):
+        """Enable persistent history, tab completion and reverse search"""
+        if readline is None:
             return
 
-        action = parts[1].lower()
-        if action == "show":
-            count = 10
-            if len(parts) > 2:
-                try:
-                    count = int(parts[2])
-                except ValueError:
-                    pass
-            history = self.history.get_history(count)
-            for entry in history:
-                print(f"{entry['timestamp']}: {entry['operation']} = {entry['result']}")
-        elif action == "clear":
-            print(self.history.clear_history())
-        elif action == "save":
-            filename = "calculator_history.json"
-            if len(parts) > 2:
-                filename = parts[2]
-            print(self.history.save_to_file(filename))
+        self.readline_history_file = os.path.expanduser("~/.` + projectSlug(g.config.ProjectName) + `_history")
+        try:
+            readline.read_history_file(self.readline_history_file)
+        except OSError:
+            pass
+        readline.set_history_length(1000)
+        atexit.register(self.save_readline_history)
+
+        readline.set_completer(self.complete)
+        readline.set_completer_delims(" \t\n()[]{},+-*/%^=<>")
+        if "libedit" in (readline.__doc__ or ""):
+            readline.parse_and_bind("bind ^I rl_complete")
+            readline.parse_and_bind("bind ^R em-inc-search-prev")
         else:
-            print("Unknown history command")
-`)
+            readline.parse_and_bind("tab: complete")
+
+    def save_readline_history(self):
+        """Persist the prompt history"""
+        try:
+            readline.write_history_file(self.readline_history_file)
+        except OSError:
+            pass
+
+    def completion_candidates(self):
+        """Names offered by tab completion"""
+        names = [name for name in self.build_eval_context() if not name.startswith("__")]
+        return sorted(set(names + self.COMMANDS))
+
+    def complete(self, text, state):
+        """Readline completer for functions, variables and commands"""
+        if state == 0:
+            self.completion_matches = [name for name in self.completion_candidates() if name.startswith(text)]
+        if state < len(self.completion_matches):
+            return self.completion_matches[state]
+        return None
+
+    def read_input(self, prompt):
+        """Read a line, asking for continuation lines while brackets are open.
+        An empty continuation line or Ctrl+C abandons the expression."""
+        line = input(prompt)
+        while self.open_brackets(line) > 0:
+            try:
+                more = input("...> " if prompt else "")
+            except KeyboardInterrupt:
+                print(file=sys.stderr)
+                return ""
+            if not more.strip():
+                return ""
+            line += " " + more
+        return line.strip()
+
+    def open_brackets(self, text):
+        """Count brackets that have been opened but not closed"""
+        depth = 0
+        for char in text:
+            if char in "([{":
+                depth += 1
+            elif char in ")]}":
+                depth -= 1
+        return depth
+`
+}
+
+// projectSlug converts a project name into a lowercase identifier suitable
+// for file names and Python identifiers
+func projectSlug(name string) string {
+	var slug strings.Builder
+	lastUnderscore := true
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			slug.WriteRune(r)
+			lastUnderscore = false
+		} else if !lastUnderscore {
+			slug.WriteRune('_')
+			lastUnderscore = true
+		}
 	}
 
-	return content.String()
+	result := strings.TrimSuffix(slug.String(), "_")
+	if result == "" {
+		return "calculator"
+	}
+	return result
+}
+
+// pythonKeywords are the reserved words that cannot name a Python module or
+// variable. Soft keywords such as match and type can.
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// isPythonIdentifier reports whether name can be used as a Python module or
+// variable name
+func isPythonIdentifier(name string) bool {
+	if name == "" || pythonKeywords[name] {
+		return false
+	}
+	for i, r := range name {
+		letter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+		digit := r >= '0' && r <= '9'
+		if !letter && !(digit && i > 0) {
+			return false
+		}
+	}
+	return true
 }
 
 // renderTemplate renders the calculator template with the given data
 func (g *Generator) renderTemplate(data TemplateData) (string, error) {
-	tmpl := `#!/usr/bin/env python3
-"""
-{{.Config.ProjectName}}
-{{.Config.Description}}
+	data.MainContent += `
 
+if __name__ == "__main__":
+    calculator = Calculator()
+    calculator.run()`
+	return renderScript("calculator", data)
+}
+
+// scriptTemplate lays out every generated module: a docstring naming the
+// project, then the imports, the functions and the main content
+const scriptTemplate = `{{if .Shebang}}#!/usr/bin/env python3
+{{end}}"""
+{{docstring .Config.ProjectName}}
+{{docstring .Config.Description}}
+{{with .Summary}}
+{{.}}
+{{end}}
 Generated by Calculator Generator
-Author: {{.Config.Author}}
+Author: {{docstring .Config.Author}}
 Version: {{.Version}}
 Generated: {{.Timestamp}}
 """
@@ -724,13 +1333,11 @@ Generated: {{.Timestamp}}
 {{end}}
 
 {{.MainContent}}
-
-if __name__ == "__main__":
-    calculator = Calculator()
-    calculator.run()
 `
 
-	t, err := template.New("calculator").Parse(tmpl)
+// renderScript renders data with the module layout shared by every style
+func renderScript(name string, data TemplateData) (string, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(scriptTemplate)
 	if err != nil {
 		return "", err
 	}
@@ -757,28 +1364,37 @@ func (g *Generator) writeToFile(content string) error {
 	return os.WriteFile(g.config.OutputFile, []byte(content), 0755)
 }
 
-// generateRequirements creates a requirements.txt file based on enabled libraries
-func (g *Generator) generateRequirements() error {
-	var requirements []string
-
-	if g.config.Libraries.UseNumpy {
-		requirements = append(requirements, "numpy>=1.21.0")
-	}
-
-	if g.config.Libraries.UsePandas {
-		requirements = append(requirements, "pandas>=1.3.0")
-	}
-
-	if g.config.Libraries.UseScipy {
-		requirements = append(requirements, "scipy>=1.7.0")
-	}
+// installSizes are rough installed sizes in MB of each requirement, with the
+// packages it pulls in, on a 64-bit Linux. They only feed size estimates.
+var installSizes = map[string]int{
+	"numpy":   40,
+	"pandas":  70,
+	"scipy":   110,
+	"sympy":   70,
+	"plotly":  50,
+	"ipython": 15,
+}
 
-	if g.config.Libraries.UseSympy {
-		requirements = append(requirements, "sympy>=1.9.0")
-	}
+// Requirements returns the lines of requirements.txt for the enabled
+// libraries, or nil when the calculator only needs the standard library.
+// Packages are pinned with Dependencies.Pin; otherwise they get lower
+// bounds, the oldest known release for the target Python when one is set.
+func (g *Generator) Requirements() []string {
+	var requirements []string
 
-	if g.config.Libraries.UsePlotly || g.config.Features.Plotting {
-		requirements = append(requirements, "plotly>=5.0.0")
+	if g.config.Dependencies.Pin {
+		if len(g.packages()) > 0 {
+			requirements = append([]string{g.pinHeader()}, g.pinned()...)
+		}
+	} else {
+		python, set := targetPython(g.config)
+		for _, name := range g.packages() {
+			minimum := lowerBounds[name]
+			if oldest, _, ok := releasesFor(name, python); set && ok {
+				minimum = oldest.version
+			}
+			requirements = append(requirements, name+">="+minimum)
+		}
 	}
 
 	// Add tkinter note for GUI calculators
@@ -786,6 +1402,23 @@ func (g *Generator) generateRequirements() error {
 		requirements = append(requirements, "# tkinter (included with Python)")
 	}
 
+	return requirements
+}
+
+// EstimatedInstallSize returns the rough disk space in MB that installing
+// the requirements takes
+func (g *Generator) EstimatedInstallSize() int {
+	size := 0
+	for _, name := range g.packages() {
+		size += installSizes[name]
+	}
+	return size
+}
+
+// generateRequirements creates a requirements.txt file based on enabled
+// libraries, and constraints.txt when it is requested
+func (g *Generator) generateRequirements() error {
+	requirements := g.Requirements()
 	if len(requirements) == 0 {
 		return nil // No requirements file needed
 	}
@@ -795,5 +1428,13 @@ func (g *Generator) generateRequirements() error {
 	requirementsPath := filepath.Join(dir, "requirements.txt")
 
 	content := strings.Join(requirements, "\n") + "\n"
-	return os.WriteFile(requirementsPath, []byte(content), 0644)
+	if err := os.WriteFile(requirementsPath, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	if constraints := g.Constraints(); len(constraints) > 0 {
+		content := strings.Join(constraints, "\n") + "\n"
+		return os.WriteFile(filepath.Join(dir, "constraints.txt"), []byte(content), 0644)
+	}
+	return nil
 }
@@ -0,0 +1,353 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// runScript runs a generated script with stdin and returns its stdout. HOME
+// is a temporary directory so persistent memory and history stay out of the
+// user's files.
+func runScript(t *testing.T, source, stdin string) string {
+	t.Helper()
+	cmd := exec.Command(python(t), "-c", source)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Env = append(os.Environ(), "HOME="+t.TempDir())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("script failed: %v: %s", err, stderr.String())
+	}
+	return string(out)
+}
+
+func TestJSONOutputOnlyWritesRecords(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Features.Memory = true
+	config.Features.History = true
+	config.UI.OutputFormat = "json"
+
+	source, err := NewGenerator(config).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdin := strings.Join([]string{
+		"2+3", "mem store 4", "mem recall", "mem list", "mem", "hist show",
+		"hist export --format csv", "hist rerun 1", "hist", "help", "clear",
+		"1/0", "nope(", ")",
+	}, "\n") + "\n"
+	out := runScript(t, source, stdin)
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	for _, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Errorf("stdout line is not a JSON record: %q", line)
+		}
+	}
+	if len(lines) != 4 {
+		t.Errorf("got %d records, want 4 (2+3, its rerun, 1/0, nope( )):\n%s", len(lines), out)
+	}
+}
+
+func TestContinuationLinesCanBeAbandoned(t *testing.T) {
+	source, err := NewGenerator(GetDefaultConfig()).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An empty continuation line drops the open expression and the
+	// calculator goes on reading
+	out := runScript(t, source, "foo(\n\n1+1\n(2+\n3)\nquit\n")
+	for _, want := range []string{"Result: 2", "Result: 5"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+
+	// Ctrl+C at a continuation prompt returns to the main prompt
+	program := `import json, sys
+namespace = {"__name__": "calc"}
+exec(sys.stdin.read(), namespace)
+lines = iter(["max(1,", KeyboardInterrupt, "2+2"])
+def fake_input(prompt=""):
+    line = next(lines)
+    if line is KeyboardInterrupt:
+        raise KeyboardInterrupt
+    return line
+namespace["input"] = fake_input
+calculator = namespace["Calculator"]()
+print(json.dumps([calculator.read_input("calc> "), calculator.read_input("calc> ")]))`
+	got, err := runPython(t, program, []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(got) != `["", "2+2"]` {
+		t.Errorf("read_input after Ctrl+C = %s, want [\"\", \"2+2\"]", got)
+	}
+}
+
+func TestExponentialFunctions(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Features.Exponential = true
+	source, err := NewGenerator(config).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program := `import json, sys
+namespace = {"__name__": "calc"}
+exec(sys.stdin.read(), namespace)
+calculator = namespace["CalculatorCore"]()
+print(json.dumps([calculator.evaluate_expression(e) for e in ("exp(0)", "root(16)", "root(-27, 3)")]))`
+	out, err := runPython(t, program, []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out) != "[1.0, 4.0, -3.0]" {
+		t.Errorf("exp and root gave %s", out)
+	}
+}
+
+func TestReadlineSetup(t *testing.T) {
+	config := GetDefaultConfig()
+	config.ProjectName = "My Calc"
+	config.Features.Trigonometric = true
+	config.Features.Memory = true
+	source, err := NewGenerator(config).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A stand-in readline records what the calculator asks of it
+	program := `import json, os, sys
+namespace = {"__name__": "calc"}
+exec(sys.stdin.read(), namespace)
+calls = []
+class Readline:
+    __doc__ = "GNU readline"
+    def __getattr__(self, name):
+        return lambda *args: calls.append([name] + [a if isinstance(a, (str, int)) else "<fn>" for a in args])
+namespace["readline"] = Readline()
+namespace["atexit"].register = lambda fn: calls.append(["atexit", fn.__name__])
+calculator = namespace["Calculator"]()
+calculator.setup_readline()
+calculator.memory.store(3, "rate")
+calculator.remember_result(7)
+def completions(text):
+    matches, state = [], 0
+    while True:
+        match = calculator.complete(text, state)
+        if match is None:
+            return matches
+        matches.append(match)
+        state += 1
+print(json.dumps({
+    "calls": [c if c[0] != "read_history_file" else [c[0], os.path.basename(c[1])] for c in calls],
+    "s": completions("s"), "r": completions("r"), "m": completions("m"), "a": completions("a"), "_": completions("_"),
+}))`
+	out, err := runPython(t, program, []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := map[string]any{
+		"calls": []any{
+			[]any{"read_history_file", ".my_calc_history"},
+			[]any{"set_history_length", 1000.0},
+			[]any{"atexit", "save_readline_history"},
+			[]any{"set_completer", "<fn>"},
+			[]any{"set_completer_delims", " \t\n()[]{},+-*/%^=<>"},
+			[]any{"parse_and_bind", "tab: complete"},
+		},
+		// Functions, memory registers, numbered results and commands
+		"s": []any{"sin", "sqrt"},
+		"r": []any{"rate", "round"},
+		"m": []any{"max", "mem", "min"},
+		"a": []any{"abs", "acos", "ans", "asin", "atan"},
+		"_": []any{"_1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readline setup and completion = %v\nwant %v", got, want)
+	}
+}
+
+func TestResultReferences(t *testing.T) {
+	source, err := NewGenerator(GetDefaultConfig()).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdin := strings.Join([]string{
+		"* 2",    // nothing to continue from yet
+		"6*7",    // _1
+		"ans+1",  // _2
+		"* 2",    // continues from ans
+		"1/0",    // errors are not numbered
+		"_1 - 1", // numbered reference
+		"- 2",    // a spaced minus continues
+		"-3",     // a negative number does not
+		"_9",
+	}, "\n") + "\n"
+	out := runScript(t, source, stdin)
+
+	var got []string
+	for _, line := range strings.Split(out, "calc> ")[1:] {
+		if line = strings.TrimSpace(line); line != "" && !strings.Contains(line, "Goodbye") {
+			got = append(got, line)
+		}
+	}
+	// Python words the syntax error of a bare "* 2" differently by version
+	if len(got) > 0 && strings.HasPrefix(got[0], "Error: Invalid expression") {
+		got[0] = "Error"
+	}
+	want := []string{
+		"Error",
+		"Result: 42  [_1]",
+		"Result: 43  [_2]",
+		"Result: 86  [_3]",
+		"Error: Cannot divide by zero",
+		"Result: 41  [_4]",
+		"Result: 39  [_5]",
+		"Result: -3  [_6]",
+		"Error: Invalid expression: name '_9' is not defined",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("session printed %q\nwant %q", got, want)
+	}
+}
+
+func TestMemoryRegisters(t *testing.T) {
+	memoryFile := filepath.Join(t.TempDir(), "memory.json")
+	config := GetDefaultConfig()
+	config.Features.Memory = true
+	config.Storage.PersistMemory = true
+	config.Storage.MemoryFile = memoryFile
+	source, err := NewGenerator(config).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdin := strings.Join([]string{
+		"mem store a 3.2", "mem ms 5", "mem m+ 2", "mem m- 1", "mem mr", "mem recall a",
+		"a * 2", // registers are names in expressions
+		"mem store c sqrt(9)",
+		"mem store b", // no value stores the last result
+		"mem recall nope",
+	}, "\n") + "\n"
+	out := runScript(t, source, stdin)
+	for _, want := range []string{
+		"Stored 3.2 in a", "Stored 5 in M", "M = 7", "M = 6", "a = 3.2",
+		"Result: 6.4  [_1]", "Stored 3.0 in c", "Stored 6.4 in b", "Register nope is empty",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+
+	data, err := os.ReadFile(memoryFile)
+	if err != nil {
+		t.Fatalf("memory was not saved: %v", err)
+	}
+	var saved map[string]float64
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("memory file holds %s", data)
+	}
+	if want := map[string]float64{"a": 3.2, "b": 6.4, "c": 3, "M": 6}; !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved registers = %v, want %v", saved, want)
+	}
+
+	// The next session starts with the saved registers, whole numbers still
+	// whole, and clearing empties the file
+	out = runScript(t, source, "mem list\na + M\nmem mc\n")
+	for _, want := range []string{"  M = 6\n  a = 3.2\n  b = 6.4\n  c = 3.0", "Result: 9.2  [_1]", "Memory cleared"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("restored session is missing %q:\n%s", want, out)
+		}
+	}
+	if out := runScript(t, source, "mem list\n"); !strings.Contains(out, "Memory is empty") {
+		t.Errorf("memory survived mem mc:\n%s", out)
+	}
+}
+
+func TestHistoryCommands(t *testing.T) {
+	dir := t.TempDir()
+	historyFile := filepath.Join(dir, "history.json")
+	config := GetDefaultConfig()
+	config.Features.History = true
+	config.Storage.PersistHistory = true
+	config.Storage.HistoryFile = historyFile
+	config.Storage.HistoryMaxEntries = 3
+	source, err := NewGenerator(config).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries written before the shared schema load alongside current ones
+	legacy := `[{"operation": "9*9", "result": 81, "timestamp": "12:00:00"}]`
+	if err := os.WriteFile(historyFile, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdin := strings.Join([]string{
+		"1+1", "2+2", "3+3", "hist show", "hist search 3", "hist rerun 3",
+		"hist export --format csv", "hist export --format md", "hist rerun 1",
+		"hist export --format xml",
+	}, "\n") + "\n"
+	out := regexp.MustCompile(`\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d`).ReplaceAllString(runScript(t, source, stdin), "TIME")
+	for _, want := range []string{
+		// The legacy entry is #1 and drops out once the size is reached
+		"#2 TIME: 1+1 = 2\n#3 TIME: 2+2 = 4\n#4 TIME: 3+3 = 6\n",
+		"calc> #4 TIME: 3+3 = 6\n",
+		"calc> > 2+2\nResult: 4  [_4]\n",
+		"index,timestamp,expression,result\r\n3,TIME,2+2,4\r\n4,TIME,3+3,6\r\n5,TIME,2+2,4\r\n",
+		"| # | Timestamp | Expression | Result |\n|---|---|---|---|\n| 3 | TIME | 2+2 | 4 |\n| 4 | TIME | 3+3 | 6 |\n| 5 | TIME | 2+2 | 4 |\n",
+		"No history entry #1",
+		"xml",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+
+	// The next session starts from the saved entries
+	out = runScript(t, source, "hist show\nhist clear\n")
+	if !strings.Contains(out, "#3 ") || !strings.Contains(out, "#5 ") || strings.Contains(out, "#2 ") {
+		t.Errorf("restored history:\n%s", out)
+	}
+	data, err := os.ReadFile(historyFile)
+	if err != nil || strings.TrimSpace(string(data)) != "[]" {
+		t.Errorf("history file after hist clear = %s (%v)", data, err)
+	}
+}
+
+func TestHistorySchemaIsShared(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Features.History = true
+	locale, _ := LookupLocale(config.UI.Locale)
+	historyClass := generateHistoryClass(locale)
+
+	cli, err := NewGenerator(config).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(cli, historyClass) {
+		t.Error("the CLI does not use the shared History class")
+	}
+	if !strings.Contains(generateGUI(t, config), historyClass) {
+		t.Error("the GUI does not use the shared History class")
+	}
+}
@@ -3,180 +3,73 @@ package internal
 import (
 	"fmt"
 	"strings"
-	"text/template"
 )
 
 // GUIGenerator handles the generation of GUI-based Python calculator applications
 type GUIGenerator struct {
 	config CalculatorConfig
+	locale Locale
+	core   *Generator
 }
 
 // NewGUIGenerator creates a new GUI calculator generator instance
 func NewGUIGenerator(config CalculatorConfig) *GUIGenerator {
-	return &GUIGenerator{config: config}
+	return &GUIGenerator{config: config, locale: localeFor(config), core: NewGenerator(config)}
 }
 
 // GenerateGUICalculator creates a Tkinter-based desktop calculator
 func (g *GUIGenerator) GenerateGUICalculator() (string, error) {
+	if _, _, _, err := compileKeypad(g.config); err != nil {
+		return "", err
+	}
+
 	data := g.prepareGUITemplateData()
 	return g.renderGUITemplate(data)
 }
 
 // prepareGUITemplateData prepares data for GUI template rendering
 func (g *GUIGenerator) prepareGUITemplateData() TemplateData {
-	imports := g.generateGUIImports()
-	functions := g.generateGUIFunctions()
-	mainContent := g.generateGUIMainContent()
-
-	return TemplateData{
-		Config:      g.config,
-		Imports:     imports,
-		Functions:   functions,
-		MainContent: mainContent,
-		Version:     "1.0.0",
-	}
+	return g.core.styleTemplateData(g.generateGUIImports(), g.generateGUIMainContent())
 }
 
-// generateGUIImports creates the list of Python imports for GUI calculator
+// generateGUIImports creates the Python imports the GUI needs beyond the
+// calculator's own
 func (g *GUIGenerator) generateGUIImports() []string {
-	var imports []string
-
-	// Standard library imports for GUI
-	imports = append(imports, "import tkinter as tk")
-	imports = append(imports, "from tkinter import ttk, messagebox, simpledialog")
-	imports = append(imports, "import math")
-	imports = append(imports, "import sys")
-	imports = append(imports, "import os")
-
-	if g.config.Features.History {
-		imports = append(imports, "import json")
-		imports = append(imports, "from datetime import datetime")
-	}
-
-	// Third-party library imports
-	if g.config.Libraries.UseNumpy {
-		imports = append(imports, "import numpy as np")
-	}
-
-	if g.config.Libraries.UsePandas {
-		imports = append(imports, "import pandas as pd")
-	}
-
-	if g.config.Libraries.UseScipy {
-		imports = append(imports, "import scipy as sp")
-		imports = append(imports, "from scipy import stats, optimize, integrate")
-	}
-
-	if g.config.Libraries.UseSympy {
-		imports = append(imports, "import sympy as sym")
-		imports = append(imports, "from sympy import symbols, solve, diff, integrate as sym_integrate")
-	}
-
-	if g.config.Libraries.UsePlotly || g.config.Features.Plotting {
-		imports = append(imports, "import plotly.graph_objects as go")
-		imports = append(imports, "import plotly.express as px")
+	imports := []string{
+		"import tkinter as tk",
+		"from tkinter import ttk, messagebox, simpledialog, filedialog",
+		"import tkinter.font as tkfont",
 	}
 
-	if g.config.Features.ComplexNumbers {
-		imports = append(imports, "import cmath")
+	// The keypad names sqrt, pi, e and exp come from math
+	if !g.config.Libraries.UseMath {
+		imports = append(imports, "import math")
 	}
 
 	return imports
 }
 
-// generateGUIFunctions creates calculator function implementations for GUI
-func (g *GUIGenerator) generateGUIFunctions() []string {
-	var functions []string
-
-	// Add all the mathematical functions from the original generator
-	if g.config.Features.BasicArithmetic {
-		functions = append(functions, g.generateBasicArithmeticFunctions()...)
-	}
-
-	if g.config.Features.Trigonometric {
-		functions = append(functions, g.generateTrigonometricFunctions()...)
-	}
-
-	if g.config.Features.Logarithmic {
-		functions = append(functions, g.generateLogarithmicFunctions()...)
+// keypadNames returns the Python dict entries for the names the keypads
+// insert that the shared evaluation context does not have
+func (g *GUIGenerator) keypadNames() string {
+	names := `"sqrt": math.sqrt, "pi": math.pi, "e": math.e, "exp": math.exp, "pow": pow`
+	if g.config.Features.Programming {
+		names += `,
+            "hex": hex, "bin": bin, "oct": oct, "int": int`
 	}
-
 	if g.config.Features.Statistical && g.config.Libraries.UseNumpy {
-		functions = append(functions, g.generateStatisticalFunctions()...)
-	}
-
-	if g.config.Features.Memory {
-		functions = append(functions, g.generateMemoryClass())
-	}
-
-	if g.config.Features.History {
-		functions = append(functions, g.generateHistoryClass())
-	}
-
-	return functions
-}
-
-// generateBasicArithmeticFunctions creates basic arithmetic functions for GUI
-func (g *GUIGenerator) generateBasicArithmeticFunctions() []string {
-	return []string{
-		`def safe_eval(expression):
-    """Safely evaluate mathematical expressions"""
-    try:
-        # Replace common symbols
-        expression = expression.replace('^', '**')
-        expression = expression.replace('×', '*')
-        expression = expression.replace('÷', '/')
-
-        # Create safe evaluation context
-        safe_dict = {
-            "__builtins__": {},
-            "abs": abs, "round": round, "min": min, "max": max,
-            "sqrt": math.sqrt, "pi": math.pi, "e": math.e,
-            "sin": math.sin, "cos": math.cos, "tan": math.tan,
-            "asin": math.asin, "acos": math.acos, "atan": math.atan,
-            "log": math.log10, "ln": math.log, "log10": math.log10,
-            "exp": math.exp, "pow": pow
-        }
-
-        return eval(expression, safe_dict)
-    except Exception as e:
-        raise ValueError(f"Invalid expression: {str(e)}")`,
-	}
-}
-
-// generateTrigonometricFunctions creates trigonometric functions for GUI
-func (g *GUIGenerator) generateTrigonometricFunctions() []string {
-	return []string{
-		`def deg_to_rad(degrees):
-    """Convert degrees to radians"""
-    return math.radians(degrees)
-
-def rad_to_deg(radians):
-    """Convert radians to degrees"""
-    return math.degrees(radians)`,
-	}
-}
-
-// generateLogarithmicFunctions creates logarithmic functions for GUI
-func (g *GUIGenerator) generateLogarithmicFunctions() []string {
-	return []string{
-		`def safe_log(x, base=10):
-    """Safe logarithm function"""
-    if x <= 0:
-        raise ValueError("Logarithm input must be positive")
-    if base == math.e:
-        return math.log(x)
-    return math.log(x, base)`,
+		names += `,
+            "var": variance`
 	}
+	return names
 }
 
-// generateStatisticalFunctions creates statistical functions for GUI
-func (g *GUIGenerator) generateStatisticalFunctions() []string {
-	return []string{
-		`def calculate_stats(data_str):
-    """Calculate statistics from comma-separated data"""
+// generateStatsFunction creates the helper behind the statistics dialog
+func (g *GUIGenerator) generateStatsFunction() string {
+	return `def calculate_stats(data_str):
+    """Calculate statistics from data separated by the argument separator"""
     try:
-        data = [float(x.strip()) for x in data_str.split(',')]
+        data = [float(parse_localized(x.strip())) for x in data_str.split(ARGUMENT_SEPARATOR)]
         return {
             'mean': np.mean(data),
             'median': np.median(data),
@@ -186,116 +79,85 @@ func (g *GUIGenerator) generateStatisticalFunctions() []string {
             'max': np.max(data)
         }
     except Exception as e:
-        raise ValueError(f"Invalid data format: {str(e)}")`,
-	}
-}
-
-// generateMemoryClass creates memory functionality for GUI
-func (g *GUIGenerator) generateMemoryClass() string {
-	return `class MemoryManager:
-    """Handles calculator memory operations"""
-    def __init__(self):
-        self.memory = 0
-
-    def store(self, value):
-        """Store value in memory"""
-        self.memory = float(value)
-
-    def recall(self):
-        """Recall value from memory"""
-        return self.memory
-
-    def clear(self):
-        """Clear memory"""
-        self.memory = 0
-
-    def add(self, value):
-        """Add value to memory"""
-        self.memory += float(value)
-
-    def subtract(self, value):
-        """Subtract value from memory"""
-        self.memory -= float(value)`
-}
-
-// generateHistoryClass creates history functionality for GUI
-func (g *GUIGenerator) generateHistoryClass() string {
-	return `class HistoryManager:
-    """Handles calculation history"""
-    def __init__(self, max_entries=100):
-        self.history = []
-        self.max_entries = max_entries
-
-    def add_entry(self, expression, result):
-        """Add calculation to history"""
-        entry = {
-            'timestamp': datetime.now().strftime('%H:%M:%S'),
-            'expression': expression,
-            'result': str(result)
-        }
-        self.history.append(entry)
-
-        # Keep only max_entries
-        if len(self.history) > self.max_entries:
-            self.history = self.history[-self.max_entries:]
-
-    def get_history(self):
-        """Get all history entries"""
-        return self.history
-
-    def clear(self):
-        """Clear history"""
-        self.history = []
-
-    def save_to_file(self, filename):
-        """Save history to file"""
-        with open(filename, 'w') as f:
-            json.dump(self.history, f, indent=2)`
+        raise ValueError(f"` + g.locale.T("gui.invalid_data") + `")`
 }
 
 // generateGUIMainContent creates the main GUI calculator class
 func (g *GUIGenerator) generateGUIMainContent() string {
 	var content strings.Builder
 
+	content.WriteString(g.generateThemeConstants() + "\n\n")
+	content.WriteString(g.generateWindowConstants() + "\n\n")
+	content.WriteString(g.generateShortcutConstants() + "\n\n")
+	content.WriteString(g.generateTabOrder() + "\n\n")
+
+	if g.config.Features.Statistical && g.config.Libraries.UseNumpy {
+		content.WriteString(g.generateStatsFunction() + "\n\n\n")
+	}
+
 	// Start of Calculator class
-	content.WriteString(`class CalculatorGUI:
+	content.WriteString(`class CalculatorGUI(CalculatorCore):
     """Main GUI Calculator Application"""
 
     def __init__(self):
+        super().__init__()
+        enable_dpi_awareness()
         self.root = tk.Tk()
-        self.root.title("` + g.config.ProjectName + `")
-        self.root.geometry("400x600")
-        self.root.resizable(False, False)
+        self.root.title(` + pythonString(g.config.ProjectName) + `)
+        self.configure_window()
+        self.create_fonts()
 
         # Calculator state
         self.display_var = tk.StringVar()
         self.display_var.set("0")
         self.current_expression = ""
+        self.cursor = 0
+        self.undo_stack = []
+        self.redo_stack = []
         self.result_shown = False
-        self.angle_unit = "` + g.config.UI.AngleUnit + `"
-        self.precision = ` + fmt.Sprintf("%d", g.config.UI.Precision) + `
-
-`)
-
-	// Add memory and history if enabled
-	if g.config.Features.Memory {
-		content.WriteString("        self.memory = MemoryManager()\n")
-	}
+        self.high_contrast = False
 
-	if g.config.Features.History {
-		content.WriteString("        self.history = HistoryManager()\n")
-	}
-
-	// Continue with GUI setup
-	content.WriteString(`
         # Setup GUI
         self.create_widgets()
         self.setup_layout()
         self.setup_bindings()
+        self.setup_traversal()
 
         # Apply theme
         self.apply_theme()
 
+    def configure_window(self):
+        """Size the window for the display's DPI"""
+        # Tk reports pixels per inch; sizes are given for a 96 DPI display
+        self.dpi_scale = max(self.root.winfo_fpixels('1i') / 96, 1.0)
+        width, height = (round(size * self.dpi_scale) for size in WINDOW_SIZE)
+        min_width, min_height = (round(size * self.dpi_scale) for size in MIN_WINDOW_SIZE)
+
+        self.root.geometry(f"{width}x{height}")
+        self.root.minsize(min_width, min_height)
+        self.root.resizable(WINDOW_RESIZABLE, WINDOW_RESIZABLE)
+
+    def create_fonts(self):
+        """Create named fonts so every widget follows a change of font size"""
+        self.zoom_level = 0
+        self.fonts = {}
+        for role, (family, size, *weight) in THEME_FONTS.items():
+            self.fonts[role] = tkfont.Font(
+                root=self.root,
+                family=family,
+                size=round(size * FONT_SCALE),
+                weight=weight[0] if weight else 'normal'
+            )
+
+    def zoom(self, step):
+        """Make text larger (1) or smaller (-1), or restore its size (0)"""
+        self.zoom_level = max(-3, min(self.zoom_level + step, 8)) if step else 0
+        factor = FONT_SCALE * 1.15 ** self.zoom_level
+        for role, (family, size, *weight) in THEME_FONTS.items():
+            self.fonts[role].configure(size=max(6, round(size * factor)))
+        percent = round(factor * 100)
+        self.announce(f"` + g.locale.T("gui.text_size") + `")
+
     def create_widgets(self):
         """Create all GUI widgets"""
         # Main frame
@@ -307,7 +169,9 @@ func (g *GUIGenerator) generateGUIMainContent() string {
             self.display_frame,
             textvariable=self.display_var,
             justify='right',
-            state='readonly'
+            state='readonly',
+            style='Display.TEntry',
+            font=self.fonts['display']
         )
 
         # Expression display
@@ -324,77 +188,13 @@ func (g *GUIGenerator) generateGUIMainContent() string {
         self.create_buttons()
 `)
 
-	// Add menu if advanced features are enabled
-	if g.config.Features.Memory || g.config.Features.History || g.config.Features.Statistical {
-		content.WriteString(`
+	content.WriteString(`
         # Create menu
         self.create_menu()
 `)
-	}
 
 	// Add button creation method
-	content.WriteString(`
-    def create_buttons(self):
-        """Create calculator buttons"""
-        # Button configuration
-        button_config = {
-            'width': 5
-        }
-
-        # Number buttons (0-9)
-        self.num_buttons = {}
-        for i in range(10):
-            self.num_buttons[i] = ttk.Button(
-                self.button_frame,
-                text=str(i),
-                command=lambda n=i: self.append_number(str(n)),
-                **button_config
-            )
-
-        # Operator buttons
-        self.op_buttons = {
-            '+': ttk.Button(self.button_frame, text='+', command=lambda: self.append_operator('+'), **button_config),
-            '-': ttk.Button(self.button_frame, text='-', command=lambda: self.append_operator('-'), **button_config),
-            '*': ttk.Button(self.button_frame, text='×', command=lambda: self.append_operator('*'), **button_config),
-            '/': ttk.Button(self.button_frame, text='÷', command=lambda: self.append_operator('/'), **button_config),
-            '=': ttk.Button(self.button_frame, text='=', command=self.calculate, **button_config),
-            '.': ttk.Button(self.button_frame, text='.', command=lambda: self.append_number('.'), **button_config),
-            'C': ttk.Button(self.button_frame, text='C', command=self.clear, **button_config),
-            'CE': ttk.Button(self.button_frame, text='CE', command=self.clear_entry, **button_config),
-        }
-
-        # Advanced function buttons`)
-
-	// Add scientific function buttons if enabled
-	if g.config.Features.Trigonometric {
-		content.WriteString(`
-        if True:  # Trigonometric functions
-            self.trig_buttons = {
-                'sin': ttk.Button(self.button_frame, text='sin', command=lambda: self.append_function('sin'), **button_config),
-                'cos': ttk.Button(self.button_frame, text='cos', command=lambda: self.append_function('cos'), **button_config),
-                'tan': ttk.Button(self.button_frame, text='tan', command=lambda: self.append_function('tan'), **button_config),
-            }`)
-	}
-
-	if g.config.Features.Logarithmic {
-		content.WriteString(`
-        if True:  # Logarithmic functions
-            self.log_buttons = {
-                'log': ttk.Button(self.button_frame, text='log', command=lambda: self.append_function('log'), **button_config),
-                'ln': ttk.Button(self.button_frame, text='ln', command=lambda: self.append_function('ln'), **button_config),
-            }`)
-	}
-
-	// Add memory buttons if enabled
-	if g.config.Features.Memory {
-		content.WriteString(`
-        if True:  # Memory functions
-            self.mem_buttons = {
-                'MS': ttk.Button(self.button_frame, text='MS', command=self.memory_store, **button_config),
-                'MR': ttk.Button(self.button_frame, text='MR', command=self.memory_recall, **button_config),
-                'MC': ttk.Button(self.button_frame, text='MC', command=self.memory_clear, **button_config),
-            }`)
-	}
+	content.WriteString(g.generateKeypadButtons())
 
 	// Add layout setup
 	content.WriteString(`
@@ -413,100 +213,150 @@ func (g *GUIGenerator) generateGUIMainContent() string {
 
         # Layout buttons in grid`)
 
-	// Different layouts for basic vs scientific
-	if g.config.Type == ScientificCalculator {
-		content.WriteString(g.generateScientificLayout())
-	} else {
-		content.WriteString(g.generateBasicLayout())
-	}
+	// Compiled keypad layout
+	content.WriteString(g.generateKeypadLayout())
 
 	// Add event handling methods
 	content.WriteString(`
 
     def setup_bindings(self):
         """Setup keyboard bindings"""
+        # Digits and operators are typed; everything else comes from the keymap
         self.root.bind('<Key>', self.on_key_press)
-        self.root.focus_set()
-
+` + g.generateKeyBindings() + `
     def on_key_press(self, event):
-        """Handle keyboard input"""
+        """Type digits, operators and the decimal point"""
         key = event.char
         if key.isdigit():
             self.append_number(key)
-        elif key in '+-*/':
+        elif key and key in '+-*/':
             self.append_operator(key)
-        elif key == '.':
+        elif key in ('.', DECIMAL_SEPARATOR):
             self.append_number('.')
-        elif key == '\r' or key == '=':
-            self.calculate()
-        elif key.lower() == 'c':
-            self.clear()
-        elif event.keysym == 'BackSpace':
-            self.backspace()
 
-    def append_number(self, number):
-        """Add number to current expression"""
+    def setup_traversal(self):
+        """Tab moves from the display through the keypad row by row"""
+        self.display.configure(takefocus=True)
+        for button_id in TAB_ORDER:
+            button = self.buttons[button_id]
+            button.configure(takefocus=True)
+            # Tk tabs through siblings in stacking order
+            button.lift()
+        self.display.focus_set()
+
+    def set_expression(self, expression, cursor=None, record=True):
+        """Replace the expression, remembering the previous state for undo"""
+        if record and expression != self.current_expression:
+            self.undo_stack.append((self.current_expression, self.cursor))
+            del self.undo_stack[:-100]
+            self.redo_stack.clear()
+
+        self.current_expression = expression
+        if cursor is None:
+            cursor = len(expression)
+        self.cursor = max(0, min(cursor, len(expression)))
+        self.update_display()
+
+    def insert_text(self, text):
+        """Insert text at the cursor"""
         if self.result_shown:
-            self.current_expression = ""
             self.result_shown = False
 
-        if number == '.' and '.' in self.current_expression.split()[-1]:
-            return  # Don't allow multiple decimal points
+        before = self.current_expression[:self.cursor]
+        after = self.current_expression[self.cursor:]
+        self.set_expression(before + text + after, self.cursor + len(text))
 
-        self.current_expression += number
-        self.update_display()
+    def type_text(self, text):
+        """Insert text, starting a new expression over a shown result. Undo
+        goes straight back to the result."""
+        if self.result_shown:
+            self.result_shown = False
+            self.set_expression(text)
+            return
+
+        self.insert_text(text)
+
+    def current_number(self):
+        """Digits and decimal separator immediately before the cursor"""
+        return re.search(r'[0-9' + re.escape(DECIMAL_SEPARATOR) + r']*$', self.current_expression[:self.cursor]).group(0)
+
+    def append_number(self, number):
+        """Add number to current expression"""
+        if number == '.':
+            number = DECIMAL_SEPARATOR
+            if not self.result_shown and number in self.current_number():
+                return  # Don't allow multiple decimal points
+
+        self.type_text(number)
 
     def append_operator(self, operator):
         """Add operator to current expression"""
         if self.result_shown:
             self.result_shown = False
+            self.cursor = len(self.current_expression)
 
-        if self.current_expression and self.current_expression[-1] in '+-*/':
-            self.current_expression = self.current_expression[:-1]
+        # Replace an operator directly before the cursor
+        before = self.current_expression[:self.cursor]
+        if before and before[-1] in '+-*/':
+            after = self.current_expression[self.cursor:]
+            self.set_expression(before[:-1] + operator + after, self.cursor)
+            return
 
-        self.current_expression += operator
-        self.update_display()
+        self.insert_text(operator)
+
+    def append_reference(self, name):
+        """Add a reference to a previous result (e.g. ans)"""
+        if not self.results:
+            return
+
+        self.type_text(name)
 
     def append_function(self, function):
         """Add function to current expression"""
-        if self.result_shown:
-            self.current_expression = ""
-            self.result_shown = False
+        self.type_text(function + "(")
+
+    def append_symbol(self, symbol):
+        """Add a constant, bracket or other symbol to current expression"""
+        self.type_text(symbol)
 
-        self.current_expression += function + "("
+    def move_cursor(self, offset):
+        """Move the cursor left or right within the expression"""
+        self.move_cursor_to(self.cursor + offset)
+
+    def move_cursor_to(self, position):
+        """Place the cursor at a position within the expression"""
+        self.result_shown = False
+        self.cursor = max(0, min(position, len(self.current_expression)))
         self.update_display()
 
+    def undo(self):
+        """Undo the last edit"""
+        if self.undo_stack:
+            self.redo_stack.append((self.current_expression, self.cursor))
+            expression, cursor = self.undo_stack.pop()
+            self.result_shown = False
+            self.set_expression(expression, cursor, record=False)
+
+    def redo(self):
+        """Redo the last undone edit"""
+        if self.redo_stack:
+            self.undo_stack.append((self.current_expression, self.cursor))
+            expression, cursor = self.redo_stack.pop()
+            self.result_shown = False
+            self.set_expression(expression, cursor, record=False)
+
     def calculate(self):
         """Perform calculation"""
         try:
             if not self.current_expression:
                 return
 
-            # Handle angle units for trig functions
-            expression = self.current_expression`)
-
-	// Add angle unit handling for trigonometric functions
-	if g.config.Features.Trigonometric {
-		content.WriteString(`
-            if self.angle_unit == "degrees":
-                # Convert degrees to radians for trig functions
-                import re
-                trig_functions = ['sin', 'cos', 'tan']
-                for func in trig_functions:
-                    pattern = f'{func}\\(([^)]+)\\)'
-                    def replace_trig(match):
-                        angle = match.group(1)
-                        return f'{func}(math.radians({angle}))'
-                    expression = re.sub(pattern, replace_trig, expression)`)
-	}
-
-	content.WriteString(`
-
-            result = safe_eval(expression)
+            result = self.evaluate_expression(self.current_expression)
+            self.remember_result(result)
             formatted_result = self.format_result(result)
 
             # Update display
-            self.display_var.set(str(formatted_result))
+            self.display_var.set(format_localized(formatted_result))
             self.expr_var.set(f"{self.current_expression} =")
 
             # Add to history`)
@@ -519,13 +369,29 @@ func (g *GUIGenerator) generateGUIMainContent() string {
 	content.WriteString(`
 
             # Set up for next calculation
-            self.current_expression = str(formatted_result)
+            self.set_expression(format_localized(formatted_result))
+            self.display_var.set(format_localized(formatted_result))
             self.result_shown = True
+            self.announce(f"{self.expr_var.get()} {format_localized(formatted_result)}")
 
         except Exception as e:
-            self.display_var.set("Error")
+            # Keep the expression so it can be corrected in place
+            self.display_var.set("` + g.locale.T("gui.error") + `")
+            self.display.configure(style='Error.TEntry')
             self.expr_var.set(str(e))
-            self.current_expression = ""
+            self.announce(f"` + g.locale.T("eval.error") + `")
+
+    def normalize_expression(self, expression):
+        """Normalize the typed expression, which may hold pasted × and ÷"""
+        return super().normalize_expression(expression.replace('×', '*').replace('÷', '/'))
+
+    def build_eval_context(self):
+        """Add the names the keypads use to the shared evaluation names"""
+        context = super().build_eval_context()
+        context.update({
+            ` + g.keypadNames() + `
+        })
+        return context
 
     def format_result(self, result):
         """Format calculation result"""
@@ -538,85 +404,174 @@ func (g *GUIGenerator) generateGUIMainContent() string {
 
     def clear(self):
         """Clear everything"""
-        self.current_expression = ""
-        self.display_var.set("0")
+        self.set_expression("")
         self.expr_var.set("")
         self.result_shown = False
 
     def clear_entry(self):
         """Clear current entry"""
-        self.current_expression = ""
-        self.display_var.set("0")
-        self.update_display()
+        self.set_expression("")
 
     def backspace(self):
-        """Remove last character"""
-        if self.current_expression:
-            self.current_expression = self.current_expression[:-1]
-            self.update_display()
+        """Remove the character before the cursor"""
+        if self.cursor > 0:
+            self.result_shown = False
+            expression = self.current_expression
+            self.set_expression(expression[:self.cursor - 1] + expression[self.cursor:], self.cursor - 1)
+
+    def delete_forward(self):
+        """Remove the character after the cursor"""
+        if self.cursor < len(self.current_expression):
+            self.result_shown = False
+            expression = self.current_expression
+            self.set_expression(expression[:self.cursor] + expression[self.cursor + 1:], self.cursor)
 
     def update_display(self):
-        """Update the display"""
-        if self.current_expression:
-            self.display_var.set(self.current_expression)
+        """Update the display, marking the cursor when it is not at the end"""
+        self.display.configure(style='Display.TEntry')
+        expression = self.current_expression
+        if not expression:
+            self.display_var.set("0")
+        elif self.cursor < len(expression):
+            self.display_var.set(expression[:self.cursor] + "▏" + expression[self.cursor:])
         else:
-            self.display_var.set("0")`)
+            self.display_var.set(expression)`)
 
 	// Add memory methods if enabled
 	if g.config.Features.Memory {
 		content.WriteString(`
 
+    def current_value(self):
+        """Value on the display, evaluating a pending expression"""
+        if self.result_shown and self.results:
+            return self.results[-1]
+        return self.evaluate_expression(self.current_expression or "0")
+
+    def memory_operation(self, operation, name=Memory.DEFAULT_REGISTER):
+        """Apply a memory operation to the displayed value"""
+        try:
+            message = operation(self.current_value(), name)
+            self.expr_var.set(message)
+        except ValueError as e:
+            messagebox.showerror("` + g.locale.T("gui.error") + `", str(e))
+
     def memory_store(self):
-        """Store current value in memory"""
+        """Store current value in memory (MS)"""
+        self.memory_operation(self.memory.store)
+
+    def memory_add(self):
+        """Add current value to memory (M+)"""
+        self.memory_operation(self.memory.add)
+
+    def memory_subtract(self):
+        """Subtract current value from memory (M-)"""
+        self.memory_operation(self.memory.subtract)
+
+    def memory_recall(self, name=Memory.DEFAULT_REGISTER):
+        """Recall value from memory (MR)"""
         try:
-            current_value = float(self.display_var.get())
-            self.memory.store(current_value)
-            messagebox.showinfo("Memory", f"Stored {current_value} in memory")
-        except ValueError:
-            messagebox.showerror("Error", "Invalid value to store")
-
-    def memory_recall(self):
-        """Recall value from memory"""
-        value = self.memory.recall()
-        self.current_expression = str(value)
-        self.display_var.set(str(value))
-        self.result_shown = True
+            value = self.memory.recall(name)
+        except ValueError as e:
+            messagebox.showerror("` + g.locale.T("gui.error") + `", str(e))
+            return
+        self.type_text(format_localized(value))
 
     def memory_clear(self):
-        """Clear memory"""
-        self.memory.clear()
-        messagebox.showinfo("Memory", "Memory cleared")`)
+        """Clear memory (MC)"""
+        self.expr_var.set(self.memory.clear())
+
+    def ask_register(self, title):
+        """Ask for a memory register name"""
+        name = simpledialog.askstring(title, "` + g.locale.T("gui.register_name") + `")
+        if name and not Memory.is_valid_name(name.strip()):
+            messagebox.showerror("` + g.locale.T("gui.error") + `", f"` + g.locale.T("memory.invalid_name") + `")
+            return None
+        return name.strip() if name else None
+
+    def memory_store_named(self):
+        """Store current value in a named register"""
+        name = self.ask_register("` + g.locale.T("gui.store_register") + `")
+        if name:
+            self.memory_operation(self.memory.store, name)
+
+    def memory_recall_named(self):
+        """Recall a named register"""
+        name = self.ask_register("` + g.locale.T("gui.recall_register") + `")
+        if name:
+            self.memory_recall(name)
+
+    def show_memory(self):
+        """Show all memory registers"""
+        registers = self.memory.list()
+        if not registers:
+            messagebox.showinfo("` + g.locale.T("gui.memory") + `", "` + g.locale.T("memory.empty") + `")
+            return
+        messagebox.showinfo("` + g.locale.T("gui.memory") + `", "\n".join(f"{name} = {format_localized(value)}" for name, value in registers))`)
 	}
 
-	// Add menu creation if advanced features enabled
-	if g.config.Features.Memory || g.config.Features.History || g.config.Features.Statistical {
-		content.WriteString(`
+	// Add menu creation; the Tools menu only when advanced features are enabled
+	content.WriteString(`
 
     def create_menu(self):
         """Create application menu"""
         menubar = tk.Menu(self.root)
-        self.root.config(menu=menubar)
+        self.root.config(menu=menubar)`)
+
+	if g.config.Features.Memory || g.config.Features.History || g.config.Features.Statistical {
+		content.WriteString(`
 
         # Tools menu
         tools_menu = tk.Menu(menubar, tearoff=0)
-        menubar.add_cascade(label="Tools", menu=tools_menu)`)
+        menubar.add_cascade(label="` + g.locale.T("gui.tools") + `", menu=tools_menu)`)
+
+		if g.config.Features.Memory {
+			content.WriteString(`
+
+        # Memory menu
+        memory_menu = tk.Menu(menubar, tearoff=0)
+        menubar.add_cascade(label="` + g.locale.T("gui.memory") + `", menu=memory_menu)
+        memory_menu.add_command(label="` + g.locale.T("gui.store_register") + `...", command=self.memory_store_named)
+        memory_menu.add_command(label="` + g.locale.T("gui.recall_register") + `...", command=self.memory_recall_named)
+        memory_menu.add_command(label="` + g.locale.T("gui.show_registers") + `", command=self.show_memory)
+        memory_menu.add_separator()
+        memory_menu.add_command(label="` + g.locale.T("gui.clear_all") + `", command=self.memory_clear)`)
+		}
 
 		if g.config.Features.Statistical {
 			content.WriteString(`
-        tools_menu.add_command(label="Statistics Calculator", command=self.show_stats_dialog)`)
+        tools_menu.add_command(label="` + g.locale.T("gui.statistics_menu") + `", command=self.show_stats_dialog)`)
 		}
 
 		if g.config.Features.History {
 			content.WriteString(`
-        tools_menu.add_command(label="Show History", command=self.show_history)
-        tools_menu.add_command(label="Clear History", command=self.clear_history)`)
+        tools_menu.add_command(label="` + g.locale.T("gui.show_history") + `", command=self.show_history)
+        tools_menu.add_command(label="` + g.locale.T("gui.search_history") + `...", command=self.search_history)
+        tools_menu.add_command(label="` + g.locale.T("gui.export_history") + `...", command=self.export_history)
+        tools_menu.add_command(label="` + g.locale.T("gui.clear_history") + `", command=self.clear_history)`)
 		}
 
-		content.WriteString(`
-        tools_menu.add_separator()
-        tools_menu.add_command(label="About", command=self.show_about)`)
 	}
 
+	content.WriteString(`
+
+        # View menu
+        self.high_contrast_var = tk.BooleanVar(value=False)
+        view_menu = tk.Menu(menubar, tearoff=0)
+        menubar.add_cascade(label="` + g.locale.T("gui.view") + `", menu=view_menu)
+        view_menu.add_command(label="` + g.locale.T("gui.larger_text") + `", command=lambda: self.zoom(1)` + g.menuAccelerator("zoom_in") + `)
+        view_menu.add_command(label="` + g.locale.T("gui.smaller_text") + `", command=lambda: self.zoom(-1)` + g.menuAccelerator("zoom_out") + `)
+        view_menu.add_command(label="` + g.locale.T("gui.normal_text") + `", command=lambda: self.zoom(0)` + g.menuAccelerator("zoom_reset") + `)
+        view_menu.add_separator()
+        view_menu.add_checkbutton(label="` + g.locale.T("gui.high_contrast") + `", variable=self.high_contrast_var,
+                                  command=self.toggle_high_contrast` + g.menuAccelerator("toggle_high_contrast") + `)
+
+        # Help menu
+        help_menu = tk.Menu(menubar, tearoff=0)
+        menubar.add_cascade(label="` + g.locale.T("gui.help") + `", menu=help_menu)
+        help_menu.add_command(label="` + g.locale.T("gui.shortcuts") + `", command=self.show_shortcuts` + g.menuAccelerator("shortcuts") + `)
+        help_menu.add_separator()
+        help_menu.add_command(label="` + g.locale.T("gui.about") + `", command=self.show_about)`)
+
 	// Add statistical dialog if enabled
 	if g.config.Features.Statistical {
 		content.WriteString(`
@@ -624,89 +579,190 @@ func (g *GUIGenerator) generateGUIMainContent() string {
     def show_stats_dialog(self):
         """Show statistics calculator dialog"""
         data_str = simpledialog.askstring(
-            "Statistics",
-            "Enter comma-separated numbers:"
+            "` + g.locale.T("gui.statistics") + `",
+            "` + g.locale.T("gui.statistics_prompt") + `"
         )
         if data_str:
             try:
                 stats = calculate_stats(data_str)
                 result = "\\n".join([f"{k.title()}: {v}" for k, v in stats.items()])
-                messagebox.showinfo("Statistics Results", result)
+                messagebox.showinfo("` + g.locale.T("gui.statistics_results") + `", result)
             except Exception as e:
-                messagebox.showerror("Error", str(e))`)
+                messagebox.showerror("` + g.locale.T("gui.error") + `", str(e))`)
 	}
 
 	// Add history methods if enabled
 	if g.config.Features.History {
 		content.WriteString(`
 
-    def show_history(self):
+    def show_history(self, entries=None, title="` + g.locale.T("gui.history_title") + `"):
         """Show calculation history"""
-        history = self.history.get_history()
+        history = self.history.get_history() if entries is None else entries
         if not history:
-            messagebox.showinfo("History", "No calculations in history")
+            messagebox.showinfo("` + g.locale.T("gui.history") + `", "` + g.locale.T("gui.no_history") + `")
             return
 
         # Create history window
         hist_window = tk.Toplevel(self.root)
-        hist_window.title("Calculation History")
+        hist_window.title(title)
         hist_window.geometry("400x300")
 
-        # Create text widget with scrollbar
-        text_frame = ttk.Frame(hist_window)
-        text_frame.pack(fill='both', expand=True, padding=10)
+        # Create list with scrollbar; selecting an entry restores it for editing
+        list_frame = ttk.Frame(hist_window, padding=10)
+        list_frame.pack(fill='both', expand=True)
 
-        text_widget = tk.Text(text_frame, wrap='word')
-        scrollbar = ttk.Scrollbar(text_frame, orient='vertical', command=text_widget.yview)
-        text_widget.configure(yscrollcommand=scrollbar.set)
+        listbox = tk.Listbox(list_frame, activestyle='dotbox')
+        scrollbar = ttk.Scrollbar(list_frame, orient='vertical', command=listbox.yview)
+        listbox.configure(yscrollcommand=scrollbar.set)
 
         # Add history entries
         for entry in history:
-            text_widget.insert('end', f"{entry['timestamp']}: {entry['expression']} = {entry['result']}\\n")
+            listbox.insert('end', f"#{entry['index']} {entry['timestamp']}: {entry['expression']} = {entry['result']}")
+
+        def restore(event):
+            selection = listbox.curselection()
+            if selection:
+                self.restore_history_entry(history[selection[0]])
 
-        text_widget.config(state='disabled')
-        text_widget.pack(side='left', fill='both', expand=True)
+        listbox.bind('<<ListboxSelect>>', restore)
+        listbox.pack(side='left', fill='both', expand=True)
         scrollbar.pack(side='right', fill='y')
 
+    def restore_history_entry(self, entry):
+        """Load a history entry's expression for editing"""
+        self.result_shown = False
+        self.set_expression(entry['expression'])
+        self.expr_var.set(f"#{entry['index']} = {entry['result']}")
+
+    def search_history(self):
+        """Search history for an expression or result"""
+        text = simpledialog.askstring("` + g.locale.T("gui.search_history") + `", "` + g.locale.T("gui.find_text") + `")
+        if text:
+            matches = self.history.search(text)
+            if not matches:
+                messagebox.showinfo("` + g.locale.T("gui.history") + `", f"` + g.locale.T("gui.no_matches") + `")
+                return
+            self.show_history(matches, f"` + g.locale.T("gui.history_matching") + `")
+
+    def export_history(self):
+        """Export history as JSON, CSV or Markdown"""
+        filename = filedialog.asksaveasfilename(
+            title="` + g.locale.T("gui.export_history") + `",
+            defaultextension=".json",
+            filetypes=[("JSON", "*.json"), ("CSV", "*.csv"), ("Markdown", "*.md")]
+        )
+        if filename:
+            try:
+                messagebox.showinfo("` + g.locale.T("gui.history") + `", self.history.save_to_file(filename))
+            except (OSError, ValueError) as e:
+                messagebox.showerror("` + g.locale.T("gui.error") + `", str(e))
+
     def clear_history(self):
         """Clear calculation history"""
-        self.history.clear()
-        messagebox.showinfo("History", "History cleared")`)
+        self.history.clear_history()
+        messagebox.showinfo("` + g.locale.T("gui.history") + `", "` + g.locale.T("history.cleared") + `")`)
 	}
 
-	// Add theme and about methods
+	// Add keyboard shortcut help and the angle unit toggle
 	content.WriteString(`
 
-    def apply_theme(self):
-        """Apply visual theme"""
-        # Configure styles based on theme setting
-        style = ttk.Style()`)
+    def show_shortcuts(self):
+        """Show the keyboard shortcuts window"""
+        window = tk.Toplevel(self.root)
+        window.title("` + g.locale.T("gui.shortcuts") + `")
+        window.transient(self.root)
+
+        tree = ttk.Treeview(window, columns=('keys', 'action'), show='headings', height=len(SHORTCUTS))
+        tree.heading('keys', text="` + g.locale.T("gui.shortcut") + `")
+        tree.heading('action', text="` + g.locale.T("gui.action") + `")
+        tree.column('keys', width=160)
+        tree.column('action', width=220)
+        for keys, action in SHORTCUTS:
+            tree.insert('', 'end', values=(keys, action))
+        tree.pack(fill='both', expand=True, padx=10, pady=10)
+
+        ttk.Button(window, text="` + g.locale.T("gui.close") + `", command=window.destroy).pack(pady=(0, 10))
+        window.bind('<Escape>', lambda event: window.destroy())
+        window.focus_set()`)
 
-	if g.config.UI.Theme == "dark" {
-		content.WriteString(`
-        # Dark theme
-        self.root.configure(bg='#2b2b2b')
-        style.configure('TFrame', background='#2b2b2b')
-        style.configure('TButton', background='#404040', foreground='white')
-        style.configure('TLabel', background='#2b2b2b', foreground='white')`)
-	} else {
+	if g.config.Features.Trigonometric {
 		content.WriteString(`
-        # Light theme (default)
-        style.configure('TButton', padding=5)`)
+
+    def toggle_angle_unit(self):
+        """Switch trigonometric functions between degrees and radians"""
+        self.angle_unit = "radians" if self.angle_unit == "degrees" else "degrees"
+        unit = self.angle_unit
+        self.expr_var.set(f"` + g.locale.T("gui.angle_unit") + `")`)
 	}
 
+	// Add theme and about methods
 	content.WriteString(`
 
-    def show_about(self):
-        """Show about dialog"""
-        about_text = f"""` + g.config.ProjectName + `
-Version: 1.0.0
-Author: ` + g.config.Author + `
+    def apply_theme(self):
+        """Apply the theme, or the high contrast colours, as ttk styles"""
+        style = ttk.Style()
+        # The clam theme honours custom colours on every platform
+        if 'clam' in style.theme_names():
+            style.theme_use('clam')
+
+        colors = HIGH_CONTRAST_COLORS if self.high_contrast else THEME_COLORS
+        window_fg, window_bg = colors['window']
+        self.root.configure(bg=window_bg)
+        self.main_frame.configure(padding=THEME_PADDING['window'])
+        style.configure('TFrame', background=window_bg)
+        style.configure('TLabel', background=window_bg, foreground=window_fg)
+
+        # Keyboard focus is drawn in the window's text colour, which contrasts
+        # with the window background in every theme
+        for name, role in (('Display', 'display'), ('Error', 'error')):
+            fg, bg = colors[role]
+            style.configure(f'{name}.TEntry', foreground=fg, fieldbackground=bg)
+            style.map(f'{name}.TEntry', foreground=[('readonly', fg)], fieldbackground=[('readonly', bg)],
+                      bordercolor=[('focus', window_fg)], lightcolor=[('focus', window_fg)])
+
+        for name, role in (('Digit', 'digits'), ('Operator', 'operators'),
+                           ('Function', 'functions'), ('Memory', 'memory')):
+            fg, bg = colors[role]
+            style.configure(f'{name}.TButton', foreground=fg, background=bg,
+                            font=self.fonts['buttons'], padding=THEME_PADDING['button'],
+                            focuscolor=window_fg, focusthickness=2)
+            style.map(f'{name}.TButton', foreground=[('active', fg)], background=[('active', bg)],
+                      bordercolor=[('focus', window_fg)])
+
+    def toggle_high_contrast(self):
+        """Switch between the theme and high contrast colours"""
+        self.high_contrast = not self.high_contrast
+        self.high_contrast_var.set(self.high_contrast)
+        self.apply_theme()
+        self.announce("` + g.locale.T("gui.high_contrast_on") + `" if self.high_contrast else "` + g.locale.T("gui.high_contrast_off") + `")
 
-` + g.config.Description + `
+    def announce(self, message):
+        """Write a message for screen readers when announce mode is on"""
+        if ANNOUNCE is None:
+            return
+        if ANNOUNCE == "stdout":
+            print(message, flush=True)
+            return
+        try:
+            with open(os.path.expanduser(ANNOUNCE), 'a', encoding='utf-8') as log:
+                log.write(message + "\n")
+        except OSError:
+            pass`)
 
-Generated by Calculator Generator"""
-        messagebox.showinfo("About", about_text)
+	content.WriteString(`
+
+    def show_about(self):
+        """Show about dialog"""
+        about_text = "\n".join([
+            ` + pythonString(g.config.ProjectName) + `,
+            "` + g.locale.T("gui.version") + `: 1.0.0",
+            ` + pythonString(g.locale.T("gui.author")+": "+g.config.Author) + `,
+            "",
+            ` + pythonString(g.config.Description) + `,
+            "",
+            "Generated by Calculator Generator",
+        ])
+        messagebox.showinfo("` + g.locale.T("gui.about") + `", about_text)
 
     def run(self):
         """Start the calculator application"""
@@ -723,195 +779,221 @@ if __name__ == "__main__":
 	return content.String()
 }
 
-// generateBasicLayout creates button layout for basic calculator
-func (g *GUIGenerator) generateBasicLayout() string {
-	layout := `
-
-        # Basic calculator layout (4x5 grid)
-        row = 0
+// generateThemeConstants emits the resolved theme as Python constants
+func (g *GUIGenerator) generateThemeConstants() string {
+	theme := g.config.UI.ResolveTheme()
+	if g.config.UI.DisplayFont != "" {
+		theme.Fonts.Display.Family = g.config.UI.DisplayFont
+	}
 
-        # Memory buttons (if enabled)`
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("# Colours (foreground, background), fonts and padding from the %q theme\nTHEME_COLORS = {\n", theme.Name))
+	for _, role := range theme.roles() {
+		content.WriteString(fmt.Sprintf("    %q: (%q, %q),\n", role.name, role.color.Foreground, role.color.Background))
+	}
+	content.WriteString("}\nTHEME_FONTS = {\n")
+	content.WriteString(fmt.Sprintf("    \"display\": %s,\n    \"buttons\": %s,\n", tkFont(theme.Fonts.Display), tkFont(theme.Fonts.Buttons)))
+	content.WriteString("}\n")
+	content.WriteString(fmt.Sprintf("THEME_PADDING = {\"window\": %d, \"button\": %d}\n\n", theme.Padding.Window, theme.Padding.Button))
 
-	if g.config.Features.Memory {
-		layout += `
-        if hasattr(self, 'mem_buttons'):
-            col = 0
-            for text, button in self.mem_buttons.items():
-                button.grid(row=row, column=col, padx=2, pady=2, sticky='nsew')
-                col += 1
-            row += 1`
+	// View > High Contrast switches to these colours at runtime
+	highContrast, _ := BuiltinTheme("high-contrast")
+	content.WriteString("# Colours used in high contrast mode\nHIGH_CONTRAST_COLORS = {\n")
+	for _, role := range highContrast.roles() {
+		content.WriteString(fmt.Sprintf("    %q: (%q, %q),\n", role.name, role.color.Foreground, role.color.Background))
 	}
+	content.WriteString("}")
 
-	layout += `
-
-        # Clear buttons
-        self.op_buttons['CE'].grid(row=row, column=0, padx=2, pady=2, sticky='nsew')
-        self.op_buttons['C'].grid(row=row, column=1, padx=2, pady=2, sticky='nsew')
-        row += 1
-
-        # Number and operator layout
-        # Row 1: 7, 8, 9, /
-        self.num_buttons[7].grid(row=row, column=0, padx=2, pady=2, sticky='nsew')
-        self.num_buttons[8].grid(row=row, column=1, padx=2, pady=2, sticky='nsew')
-        self.num_buttons[9].grid(row=row, column=2, padx=2, pady=2, sticky='nsew')
-        self.op_buttons['/'].grid(row=row, column=3, padx=2, pady=2, sticky='nsew')
-        row += 1
-
-        # Row 2: 4, 5, 6, *
-        self.num_buttons[4].grid(row=row, column=0, padx=2, pady=2, sticky='nsew')
-        self.num_buttons[5].grid(row=row, column=1, padx=2, pady=2, sticky='nsew')
-        self.num_buttons[6].grid(row=row, column=2, padx=2, pady=2, sticky='nsew')
-        self.op_buttons['*'].grid(row=row, column=3, padx=2, pady=2, sticky='nsew')
-        row += 1
-
-        # Row 3: 1, 2, 3, -
-        self.num_buttons[1].grid(row=row, column=0, padx=2, pady=2, sticky='nsew')
-        self.num_buttons[2].grid(row=row, column=1, padx=2, pady=2, sticky='nsew')
-        self.num_buttons[3].grid(row=row, column=2, padx=2, pady=2, sticky='nsew')
-        self.op_buttons['-'].grid(row=row, column=3, padx=2, pady=2, sticky='nsew')
-        row += 1
-
-        # Row 4: 0, ., =, +
-        self.num_buttons[0].grid(row=row, column=0, columnspan=2, padx=2, pady=2, sticky='nsew')
-        self.op_buttons['.'].grid(row=row, column=2, padx=2, pady=2, sticky='nsew')
-        self.op_buttons['+'].grid(row=row, column=3, padx=2, pady=2, sticky='nsew')
-        row += 1
-
-        # Equals button
-        self.op_buttons['='].grid(row=row, column=0, columnspan=4, padx=2, pady=2, sticky='nsew')
-
-        # Configure grid weights
-        for i in range(4):
-            self.button_frame.columnconfigure(i, weight=1)
-        for i in range(row + 1):
-            self.button_frame.rowconfigure(i, weight=1)`
-
-	return layout
+	return content.String()
 }
 
-// generateScientificLayout creates button layout for scientific calculator
-func (g *GUIGenerator) generateScientificLayout() string {
-	layout := `
+// windowSize returns the configured window and minimum sizes, deriving
+// sizes left at zero from the keypad's columns and rows
+func (g *GUIGenerator) windowSize() (width, height, minWidth, minHeight int) {
+	ui := g.config.UI
+	layout, _, rows, _ := compileKeypad(g.config)
 
-        # Scientific calculator layout (6x8 grid)
-        row = 0
+	width, height = ui.WindowWidth, ui.WindowHeight
+	if width == 0 {
+		width = max(320, layout.Columns*72+40)
+	}
+	if height == 0 {
+		height = rows*52 + 150
+	}
 
-        # Memory buttons (if enabled)`
+	minWidth, minHeight = ui.MinWindowWidth, ui.MinWindowHeight
+	if minWidth == 0 {
+		minWidth = min(width, layout.Columns*48+40)
+	}
+	if minHeight == 0 {
+		minHeight = min(height, rows*36+120)
+	}
+	return width, height, minWidth, minHeight
+}
 
-	if g.config.Features.Memory {
-		layout += `
-        if hasattr(self, 'mem_buttons'):
-            col = 0
-            for text, button in self.mem_buttons.items():
-                button.grid(row=row, column=col, padx=1, pady=1, sticky='nsew')
-                col += 1
-            row += 1`
+// generateWindowConstants emits the window geometry and font scaling, and the
+// helper that keeps Windows from bitmap-scaling the window on HiDPI displays
+func (g *GUIGenerator) generateWindowConstants() string {
+	width, height, minWidth, minHeight := g.windowSize()
+	fontScale := g.config.UI.FontScale
+	if fontScale == 0 {
+		fontScale = 1
+	}
+	resizable := "False"
+	if g.config.UI.Resizable {
+		resizable = "True"
 	}
 
-	// Add scientific function buttons
-	if g.config.Features.Trigonometric {
-		layout += `
-
-        # Trigonometric functions
-        if hasattr(self, 'trig_buttons'):
-            col = 0
-            for text, button in self.trig_buttons.items():
-                button.grid(row=row, column=col, padx=1, pady=1, sticky='nsew')
-                col += 1
-            row += 1`
+	announce := "None"
+	if g.config.UI.Announce != "" {
+		announce = pythonString(g.config.UI.Announce)
 	}
 
-	if g.config.Features.Logarithmic {
-		layout += `
+	return fmt.Sprintf(`# Window size in pixels at 96 DPI, scaled up on denser displays
+WINDOW_SIZE = (%d, %d)
+MIN_WINDOW_SIZE = (%d, %d)
+WINDOW_RESIZABLE = %s
+FONT_SCALE = %g
+
+# Announce mode for screen readers: None, "stdout" or a log file path
+ANNOUNCE = %s
+
+
+def enable_dpi_awareness():
+    """Ask Windows for real pixels so text stays sharp on HiDPI displays"""
+    if sys.platform == 'win32':
+        try:
+            import ctypes
+            ctypes.windll.shcore.SetProcessDpiAwareness(1)
+        except (AttributeError, OSError):
+            pass`, width, height, minWidth, minHeight, resizable, fontScale, announce)
+}
+
+// generateShortcutConstants emits the shortcuts help table, listing each
+// action with every shortcut bound to it
+func (g *GUIGenerator) generateShortcutConstants() string {
+	bindings, _ := resolveKeymap(g.config)
 
-        # Logarithmic functions
-        if hasattr(self, 'log_buttons'):
-            col = 3
-            for text, button in self.log_buttons.items():
-                button.grid(row=row-1, column=col, padx=1, pady=1, sticky='nsew')
-                col += 1`
+	var content strings.Builder
+	content.WriteString("# Keyboard shortcuts listed in the help window (shortcuts, action)\nSHORTCUTS = [\n")
+	for _, action := range keymapActions() {
+		var keys []string
+		for _, binding := range bindings {
+			if binding.action.name == action.name {
+				keys = append(keys, binding.keys.String())
+			}
+		}
+		if len(keys) > 0 {
+			content.WriteString(fmt.Sprintf("    (%q, %q),\n", strings.Join(keys, ", "), g.locale.actionDescription(action)))
+		}
 	}
+	content.WriteString("]")
 
-	layout += `
-
-        # Clear buttons
-        self.op_buttons['CE'].grid(row=row, column=0, padx=1, pady=1, sticky='nsew')
-        self.op_buttons['C'].grid(row=row, column=1, padx=1, pady=1, sticky='nsew')
-        row += 1
-
-        # Standard calculator layout
-        # Row: 7, 8, 9, /
-        self.num_buttons[7].grid(row=row, column=0, padx=1, pady=1, sticky='nsew')
-        self.num_buttons[8].grid(row=row, column=1, padx=1, pady=1, sticky='nsew')
-        self.num_buttons[9].grid(row=row, column=2, padx=1, pady=1, sticky='nsew')
-        self.op_buttons['/'].grid(row=row, column=3, padx=1, pady=1, sticky='nsew')
-        row += 1
-
-        # Row: 4, 5, 6, *
-        self.num_buttons[4].grid(row=row, column=0, padx=1, pady=1, sticky='nsew')
-        self.num_buttons[5].grid(row=row, column=1, padx=1, pady=1, sticky='nsew')
-        self.num_buttons[6].grid(row=row, column=2, padx=1, pady=1, sticky='nsew')
-        self.op_buttons['*'].grid(row=row, column=3, padx=1, pady=1, sticky='nsew')
-        row += 1
-
-        # Row: 1, 2, 3, -
-        self.num_buttons[1].grid(row=row, column=0, padx=1, pady=1, sticky='nsew')
-        self.num_buttons[2].grid(row=row, column=1, padx=1, pady=1, sticky='nsew')
-        self.num_buttons[3].grid(row=row, column=2, padx=1, pady=1, sticky='nsew')
-        self.op_buttons['-'].grid(row=row, column=3, padx=1, pady=1, sticky='nsew')
-        row += 1
-
-        # Row: 0, ., =, +
-        self.num_buttons[0].grid(row=row, column=0, columnspan=2, padx=1, pady=1, sticky='nsew')
-        self.op_buttons['.'].grid(row=row, column=2, padx=1, pady=1, sticky='nsew')
-        self.op_buttons['+'].grid(row=row, column=3, padx=1, pady=1, sticky='nsew')
-        row += 1
-
-        # Equals button
-        self.op_buttons['='].grid(row=row, column=0, columnspan=4, padx=1, pady=1, sticky='nsew')
-
-        # Configure grid weights
-        for i in range(6):
-            self.button_frame.columnconfigure(i, weight=1)
-        for i in range(row + 1):
-            self.button_frame.rowconfigure(i, weight=1)`
-
-	return layout
+	return content.String()
 }
 
-// renderGUITemplate renders the GUI calculator template with the given data
-func (g *GUIGenerator) renderGUITemplate(data TemplateData) (string, error) {
-	tmpl := `#!/usr/bin/env python3
-"""
-{{.Config.ProjectName}}
-{{.Config.Description}}
+// generateTabOrder emits the keyboard focus order of the keypad buttons,
+// row by row and left to right
+func (g *GUIGenerator) generateTabOrder() string {
+	_, cells, _, _ := compileKeypad(g.config)
 
-Generated by Calculator Generator
-Author: {{.Config.Author}}
-Version: {{.Version}}
-Generated: {{.Timestamp}}
-"""
+	ids := make([]string, len(cells))
+	for i, cell := range cells {
+		ids[i] = fmt.Sprintf("'%s'", cell.ID)
+	}
+	return "# Keyboard focus order of the keypad buttons\nTAB_ORDER = [" + strings.Join(ids, ", ") + "]"
+}
 
-{{range .Imports}}{{.}}
-{{end}}
+// generateKeyBindings emits one Tkinter binding per shortcut in the keymap
+func (g *GUIGenerator) generateKeyBindings() string {
+	bindings, _ := resolveKeymap(g.config)
 
-{{range .Functions}}
-{{.}}
+	var content strings.Builder
+	for _, binding := range bindings {
+		for _, sequence := range binding.keys.sequences() {
+			content.WriteString(fmt.Sprintf("        self.root.bind('%s', lambda event: %s)  # %s\n", sequence, binding.action.handler, binding.keys))
+		}
+	}
+	return content.String()
+}
+
+// menuAccelerator returns the accelerator argument showing the first
+// shortcut bound to action, or nothing when it has none
+func (g *GUIGenerator) menuAccelerator(action string) string {
+	bindings, _ := resolveKeymap(g.config)
+	for _, binding := range bindings {
+		if binding.action.name == action {
+			return fmt.Sprintf(", accelerator=%q", binding.keys.String())
+		}
+	}
+	return ""
+}
 
-{{end}}
+// generateKeypadButtons creates the buttons placed by the keypad layout
+func (g *GUIGenerator) generateKeypadButtons() string {
+	layout, cells, _, _ := compileKeypad(g.config)
+	catalogue := keypadCatalogue()
 
-{{.MainContent}}
-`
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf(`
+    def create_buttons(self):
+        """Create the buttons of the %q keypad"""
+        # Button configuration; each button's ttk style follows its role
+        button_config = {
+            'width': 5
+        }
 
-	t, err := template.New("gui_calculator").Parse(tmpl)
-	if err != nil {
-		return "", err
+        self.buttons = {}
+        for button_id, text, command, style in (
+`, layout.Name))
+
+	for _, cell := range cells {
+		button := catalogue[cell.ID]
+		// The decimal point and argument separator are labelled for the locale
+		switch cell.ID {
+		case ".":
+			button.label = g.locale.DecimalSeparator
+		case ",":
+			button.label = g.locale.ArgumentSeparator
+		}
+		content.WriteString(fmt.Sprintf("            ('%s', '%s', %s, '%s'),\n", cell.ID, button.label, button.command, button.style))
 	}
 
-	var result strings.Builder
-	if err := t.Execute(&result, data); err != nil {
-		return "", err
+	content.WriteString(`        ):
+            self.buttons[button_id] = ttk.Button(self.button_frame, text=text, command=command, style=style, **button_config)`)
+
+	return content.String()
+}
+
+// generateKeypadLayout compiles the keypad layout into grid calls
+func (g *GUIGenerator) generateKeypadLayout() string {
+	layout, cells, rows, _ := compileKeypad(g.config)
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("\n\n        # %q keypad (%d columns x %d rows)\n", layout.Name, layout.Columns, rows))
+	for _, cell := range cells {
+		spans := ""
+		if cell.RowSpan > 1 {
+			spans += fmt.Sprintf(", rowspan=%d", cell.RowSpan)
+		}
+		if cell.ColumnSpan > 1 {
+			spans += fmt.Sprintf(", columnspan=%d", cell.ColumnSpan)
+		}
+		content.WriteString(fmt.Sprintf("        self.buttons['%s'].grid(row=%d, column=%d%s, padx=2, pady=2, sticky='nsew')\n", cell.ID, cell.Row, cell.Column, spans))
 	}
 
-	return result.String(), nil
+	content.WriteString(fmt.Sprintf(`
+        # Configure grid weights so the keypad grows with the window,
+        # keeping columns and rows equally sized
+        for i in range(%d):
+            self.button_frame.columnconfigure(i, weight=1, uniform='column')
+        for i in range(%d):
+            self.button_frame.rowconfigure(i, weight=1, uniform='row')`, layout.Columns, rows))
+
+	return content.String()
+}
+
+// renderGUITemplate renders the GUI calculator template with the given data
+func (g *GUIGenerator) renderGUITemplate(data TemplateData) (string, error) {
+	return renderScript("gui_calculator", data)
 }
@@ -0,0 +1,388 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// generateGUI returns the GUI calculator generated for config
+func generateGUI(t *testing.T, config CalculatorConfig) string {
+	t.Helper()
+	config.UI.Style = "gui"
+	source, err := NewGUIGenerator(config).GenerateGUICalculator()
+	if err != nil {
+		t.Fatalf("GenerateGUICalculator: %v", err)
+	}
+	return source
+}
+
+func TestGUITraversalSetup(t *testing.T) {
+	source := generateGUI(t, GetDefaultConfig())
+
+	for _, want := range []string{
+		"def setup_traversal(self):",
+		"self.display.configure(takefocus=True)",
+		"for button_id in TAB_ORDER:",
+		"button.configure(takefocus=True)",
+		"button.lift()",
+		"self.display.focus_set()",
+		"focuscolor=window_fg, focusthickness=2",
+		"bordercolor=[('focus', window_fg)]",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("generated GUI is missing %q", want)
+		}
+	}
+
+	// Traversal is set up once the buttons and bindings exist, before the theme
+	order := []string{"self.create_widgets()", "self.setup_bindings()", "self.setup_traversal()", "self.apply_theme()"}
+	last := -1
+	for _, call := range order {
+		index := strings.Index(source, call)
+		if index < last {
+			t.Errorf("%s is called out of order; want %s", call, strings.Join(order, ", "))
+		}
+		last = index
+	}
+}
+
+func TestGUITabOrderFollowsKeypad(t *testing.T) {
+	tests := []struct {
+		name   string
+		keypad *KeypadLayout
+		want   string
+	}{
+		{
+			name: "basic keypad",
+			want: "TAB_ORDER = ['left', 'right', 'undo', 'redo', 'ans', 'CE', 'C', 'back', '(', ')', " +
+				"'7', '8', '9', '/', '%', '4', '5', '6', '*', '^', '1', '2', '3', '-', '=', '0', '.', '+']",
+		},
+		{
+			name:   "spanning buttons",
+			keypad: &KeypadLayout{Name: "spans", Columns: 3, Rows: []string{"7 8 =*1x2", "0*2"}},
+			want:   "TAB_ORDER = ['7', '8', '=', '0']",
+		},
+		{
+			name: "explicit placement",
+			keypad: &KeypadLayout{Name: "placed", Columns: 2, Rows: []string{"_ 8"}, Buttons: []KeypadCell{
+				{ID: "7", Row: 1, Column: 0},
+				{ID: "C", Row: 0, Column: 0},
+			}},
+			want: "TAB_ORDER = ['C', '8', '7']",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.UI.CustomKeypad = tt.keypad
+			if source := generateGUI(t, config); !strings.Contains(source, tt.want) {
+				t.Errorf("generated GUI is missing %q", tt.want)
+			}
+		})
+	}
+}
+
+func TestGUIAccessibilityShortcuts(t *testing.T) {
+	source := generateGUI(t, GetDefaultConfig())
+
+	for _, want := range []string{
+		"self.root.bind('<Control-plus>', lambda event: self.zoom(1))",
+		"self.root.bind('<Control-equal>', lambda event: self.zoom(1))",
+		"self.root.bind('<Control-minus>', lambda event: self.zoom(-1))",
+		"self.root.bind('<Control-Key-0>', lambda event: self.zoom(0))",
+		"self.root.bind('<Control-Shift-Key-H>', lambda event: self.toggle_high_contrast())",
+		`view_menu.add_checkbutton(label="High Contrast"`,
+		"HIGH_CONTRAST_COLORS = {",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("generated GUI is missing %q", want)
+		}
+	}
+}
+
+func TestGUIAnnounceMode(t *testing.T) {
+	tests := []struct {
+		announce string
+		want     string
+	}{
+		{"", "ANNOUNCE = None"},
+		{"stdout", `ANNOUNCE = "stdout"`},
+		{"~/calculator.log", `ANNOUNCE = "~/calculator.log"`},
+	}
+
+	for _, tt := range tests {
+		config := GetDefaultConfig()
+		config.UI.Announce = tt.announce
+		if source := generateGUI(t, config); !strings.Contains(source, tt.want) {
+			t.Errorf("announce %q: generated GUI is missing %q", tt.announce, tt.want)
+		}
+	}
+}
+
+func TestGUIContinuesFromLastResult(t *testing.T) {
+	source := generateGUI(t, GetDefaultConfig())
+
+	// The GUI class is loaded without opening a window; normalize_expression
+	// only needs the previous results
+	program := `import json, sys
+try:
+    import tkinter
+except ImportError:
+    sys.exit(print("no tkinter"))
+namespace = {"__name__": "calculator_gui"}
+exec(sys.stdin.read(), namespace)
+gui = namespace["CalculatorGUI"].__new__(namespace["CalculatorGUI"])
+normalized = {}
+for results in ([], [5]):
+    gui.results = results
+    for expression in ("-3", "- 3", "*2", "+1", "^2", "(-3)"):
+        normalized[f"{len(results)} {expression}"] = gui.normalize_expression(expression)
+print(json.dumps(normalized))`
+	out, err := runPython(t, program, []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out == "no tkinter\n" {
+		t.Skip("python3 has no tkinter")
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := map[string]string{
+		"0 -3": "-3", "0 - 3": "- 3", "0 *2": "*2", "0 +1": "+1", "0 ^2": "**2", "0 (-3)": "(-3)",
+		// A negative number is typed as it is; an operator continues
+		"1 -3": "-3", "1 - 3": "ans - 3", "1 *2": "ans *2", "1 +1": "ans +1", "1 ^2": "ans **2", "1 (-3)": "(-3)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalize_expression = %v, want %v", got, want)
+	}
+}
+
+func TestGUIEditsInPlace(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Features.History = true
+	config.Storage.PersistHistory = false
+	source := generateGUI(t, config)
+
+	for _, want := range []string{
+		"self.root.bind('<Left>', lambda event: self.move_cursor(-1))",
+		"self.root.bind('<Right>', lambda event: self.move_cursor(1))",
+		"self.root.bind('<Control-Key-z>', lambda event: self.undo())",
+		"self.root.bind('<Control-Key-y>', lambda event: self.redo())",
+		"listbox.bind('<<ListboxSelect>>', restore)",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("generated GUI is missing %q", want)
+		}
+	}
+
+	// The editing methods run against stand-in widgets, without a window
+	program := `import json, sys
+try:
+    import tkinter
+except ImportError:
+    sys.exit(print("no tkinter"))
+namespace = {"__name__": "calculator_gui"}
+exec(sys.stdin.read(), namespace)
+class Var:
+    value = ""
+    def set(self, value):
+        self.value = value
+    def get(self):
+        return self.value
+class Widget:
+    def configure(self, **options):
+        pass
+GUI = namespace["CalculatorGUI"]
+gui = GUI.__new__(GUI)
+namespace["CalculatorCore"].__init__(gui)
+gui.display, gui.display_var, gui.expr_var = Widget(), Var(), Var()
+gui.current_expression, gui.cursor, gui.undo_stack, gui.redo_stack, gui.result_shown = "", 0, [], [], False
+gui.announce = lambda text: None
+steps = []
+def step(name, action):
+    action()
+    steps.append([name, gui.display_var.get()])
+step("type", lambda: [gui.append_number(d) for d in "123"])
+step("left", lambda: gui.move_cursor(-1))
+step("insert", lambda: gui.append_operator("+"))
+step("replace operator", lambda: gui.append_operator("-"))
+step("backspace", gui.backspace)
+step("undo", gui.undo)
+step("undo", gui.undo)
+step("redo", gui.redo)
+step("home", lambda: gui.move_cursor_to(0))
+step("delete", gui.delete_forward)
+step("calculate", gui.calculate)
+step("type after result", lambda: gui.append_number("4"))
+step("point", lambda: gui.append_number("."))
+step("undo", gui.undo)
+step("undo", gui.undo)
+step("restore", lambda: gui.restore_history_entry(gui.history.get_entry(1)))
+steps.append(["expr", gui.expr_var.get()])
+print(json.dumps(steps))`
+	out, err := runPython(t, program, []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out == "no tkinter\n" {
+		t.Skip("python3 has no tkinter")
+	}
+
+	var got [][2]string
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := [][2]string{
+		{"type", "123"},
+		{"left", "12▏3"},
+		{"insert", "12+▏3"},
+		{"replace operator", "12-▏3"},
+		{"backspace", "12▏3"},
+		{"undo", "12-▏3"},
+		{"undo", "12+▏3"},
+		{"redo", "12-▏3"},
+		{"home", "▏12-3"},
+		{"delete", "▏2-3"},
+		{"calculate", "-1"},
+		{"type after result", "4"},
+		{"point", "4."},
+		{"undo", "4"},
+		{"undo", "-1"},
+		{"restore", "2-3"},
+		{"expr", "#1 = -1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("editing steps = %v\nwant %v", got, want)
+	}
+}
+
+func TestGUIWindowGeometry(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(config *CalculatorConfig)
+		want   string
+	}{
+		{
+			name: "fits the keypad",
+			want: "WINDOW_SIZE = (400, 462)\nMIN_WINDOW_SIZE = (280, 336)\nWINDOW_RESIZABLE = True\nFONT_SCALE = 1\n",
+		},
+		{
+			name: "scientific keypad",
+			config: func(config *CalculatorConfig) {
+				config.Features.Trigonometric = true
+				config.Features.Logarithmic = true
+				config.UI.Keypad = "scientific"
+			},
+			want: "WINDOW_SIZE = (400, 566)\nMIN_WINDOW_SIZE = (280, 408)\n",
+		},
+		{
+			name: "configured",
+			config: func(config *CalculatorConfig) {
+				ui := &config.UI
+				ui.WindowWidth, ui.WindowHeight = 640, 800
+				ui.MinWindowWidth, ui.MinWindowHeight = 320, 400
+				ui.Resizable = false
+				ui.FontScale = 1.25
+			},
+			want: "WINDOW_SIZE = (640, 800)\nMIN_WINDOW_SIZE = (320, 400)\nWINDOW_RESIZABLE = False\nFONT_SCALE = 1.25\n",
+		},
+		{
+			name: "minimum never exceeds a small window",
+			config: func(config *CalculatorConfig) {
+				config.UI.WindowWidth, config.UI.WindowHeight = 200, 300
+			},
+			want: "WINDOW_SIZE = (200, 300)\nMIN_WINDOW_SIZE = (200, 300)\n",
+		},
+		{
+			name:   "display font",
+			config: func(config *CalculatorConfig) { config.UI.DisplayFont = "Menlo" },
+			want:   "    \"display\": (\"Menlo\", 22, \"bold\"),\n    \"buttons\": (\"Helvetica\", 12),\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			if tt.config != nil {
+				tt.config(&config)
+			}
+			if source := generateGUI(t, config); !strings.Contains(source, tt.want) {
+				t.Errorf("generated GUI is missing\n%s", tt.want)
+			}
+		})
+	}
+}
+
+func TestGUIScalesWithDPI(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.WindowWidth, config.UI.WindowHeight = 400, 500
+	config.UI.MinWindowWidth, config.UI.MinWindowHeight = 300, 350
+	config.UI.FontScale = 1.5
+	source := generateGUI(t, config)
+
+	// A stand-in root reports a 144 DPI display; fonts record their sizes
+	program := `import json, sys
+try:
+    import tkinter
+except ImportError:
+    sys.exit(print("no tkinter"))
+namespace = {"__name__": "calculator_gui"}
+exec(sys.stdin.read(), namespace)
+calls = []
+class Root:
+    def winfo_fpixels(self, distance):
+        return 144.0
+    def __getattr__(self, name):
+        return lambda *args: calls.append([name, *args])
+class Font:
+    def __init__(self, root=None, **options):
+        self.options = options
+    def configure(self, **options):
+        self.options.update(options)
+namespace["tkfont"].Font = Font
+GUI = namespace["CalculatorGUI"]
+gui = GUI.__new__(GUI)
+gui.root = Root()
+gui.announce = lambda text: None
+gui.configure_window()
+gui.create_fonts()
+sizes = {"start": [gui.fonts["display"].options["size"], gui.fonts["buttons"].options["size"]]}
+gui.zoom(1)
+sizes["zoom in"] = [gui.fonts["display"].options["size"], gui.fonts["buttons"].options["size"]]
+gui.zoom(0)
+sizes["reset"] = [gui.fonts["display"].options["size"], gui.fonts["buttons"].options["size"]]
+print(json.dumps({"calls": calls, "sizes": sizes}))`
+	out, err := runPython(t, program, []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out == "no tkinter\n" {
+		t.Skip("python3 has no tkinter")
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := map[string]any{
+		"calls": []any{
+			[]any{"geometry", "600x750"},
+			[]any{"minsize", 450.0, 525.0},
+			[]any{"resizable", true, true},
+		},
+		"sizes": map[string]any{
+			"start":   []any{33.0, 18.0},
+			"zoom in": []any{38.0, 21.0},
+			"reset":   []any{33.0, 18.0},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("window and fonts = %v\nwant %v", got, want)
+	}
+}
@@ -0,0 +1,321 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyBinding binds a keyboard shortcut such as "Ctrl+M" or "F2" to a GUI action
+type KeyBinding struct {
+	Keys   string `json:"keys" yaml:"keys"`
+	Action string `json:"action" yaml:"action"`
+}
+
+// keymapAction describes something a shortcut can do in the GUI
+type keymapAction struct {
+	name        string
+	description string
+	handler     string // Python statement run by the binding
+	feature     string // feature the action needs, empty when always available
+}
+
+// keymapActions returns every action shortcuts can be bound to, in the order
+// the shortcuts help window lists them
+func keymapActions() []keymapAction {
+	return []keymapAction{
+		{"calculate", "Calculate", "self.calculate()", ""},
+		{"clear", "Clear everything", "self.clear()", ""},
+		{"clear_entry", "Clear entry", "self.clear_entry()", ""},
+		{"backspace", "Delete before cursor", "self.backspace()", ""},
+		{"delete_forward", "Delete after cursor", "self.delete_forward()", ""},
+		{"cursor_left", "Move cursor left", "self.move_cursor(-1)", ""},
+		{"cursor_right", "Move cursor right", "self.move_cursor(1)", ""},
+		{"cursor_home", "Move cursor to start", "self.move_cursor_to(0)", ""},
+		{"cursor_end", "Move cursor to end", "self.move_cursor_to(len(self.current_expression))", ""},
+		{"undo", "Undo", "self.undo()", ""},
+		{"redo", "Redo", "self.redo()", ""},
+		{"insert_answer", "Insert last answer", "self.append_reference('ans')", ""},
+		{"memory_store", "Memory store", "self.memory_store()", "memory"},
+		{"memory_recall", "Memory recall", "self.memory_recall()", "memory"},
+		{"memory_add", "Memory add", "self.memory_add()", "memory"},
+		{"memory_subtract", "Memory subtract", "self.memory_subtract()", "memory"},
+		{"memory_clear", "Memory clear", "self.memory_clear()", "memory"},
+		{"show_memory", "Show memory registers", "self.show_memory()", "memory"},
+		{"toggle_angle_unit", "Toggle degrees/radians", "self.toggle_angle_unit()", "trigonometric"},
+		{"show_history", "Show history", "self.show_history()", "history"},
+		{"search_history", "Search history", "self.search_history()", "history"},
+		{"statistics", "Statistics calculator", "self.show_stats_dialog()", "statistical"},
+		{"zoom_in", "Larger text", "self.zoom(1)", ""},
+		{"zoom_out", "Smaller text", "self.zoom(-1)", ""},
+		{"zoom_reset", "Normal text size", "self.zoom(0)", ""},
+		{"toggle_high_contrast", "Toggle high contrast", "self.toggle_high_contrast()", ""},
+		{"shortcuts", "Keyboard shortcuts", "self.show_shortcuts()", ""},
+		{"quit", "Quit", "self.root.destroy()", ""},
+	}
+}
+
+// DefaultKeymap returns the shortcuts every GUI calculator starts with
+func DefaultKeymap() []KeyBinding {
+	return []KeyBinding{
+		{"Enter", "calculate"},
+		{"=", "calculate"},
+		{"Esc", "clear"},
+		{"Ctrl+L", "clear"},
+		{"Backspace", "backspace"},
+		{"Delete", "delete_forward"},
+		{"Left", "cursor_left"},
+		{"Right", "cursor_right"},
+		{"Home", "cursor_home"},
+		{"End", "cursor_end"},
+		{"Ctrl+Z", "undo"},
+		{"Ctrl+Y", "redo"},
+		{"Ctrl+Shift+Z", "redo"},
+		{"Ctrl+A", "insert_answer"},
+		{"Ctrl+M", "memory_store"},
+		{"Ctrl+R", "memory_recall"},
+		{"Ctrl+Shift+M", "memory_clear"},
+		{"F2", "toggle_angle_unit"},
+		{"Ctrl+H", "show_history"},
+		{"Ctrl+F", "search_history"},
+		{"Ctrl+Plus", "zoom_in"},
+		{"Ctrl+=", "zoom_in"},
+		{"Ctrl+Minus", "zoom_out"},
+		{"Ctrl+0", "zoom_reset"},
+		{"Ctrl+Shift+H", "toggle_high_contrast"},
+		{"F1", "shortcuts"},
+		{"Ctrl+Q", "quit"},
+	}
+}
+
+// keyAliases maps accepted key names, in lower case, to their canonical names
+var keyAliases = map[string]string{
+	"esc": "Esc", "escape": "Esc",
+	"enter": "Enter", "return": "Enter",
+	"backspace": "Backspace", "delete": "Delete", "del": "Delete",
+	"home": "Home", "end": "End", "pageup": "PageUp", "pagedown": "PageDown",
+	"left": "Left", "right": "Right", "up": "Up", "down": "Down",
+	"tab": "Tab", "space": "Space",
+	"plus": "Plus", "minus": "Minus", "equal": "=", "=": "=",
+}
+
+// namedKeysyms maps canonical key names to Tk keysyms
+var namedKeysyms = map[string]string{
+	"Esc": "Escape", "Enter": "Return", "Backspace": "BackSpace", "Delete": "Delete",
+	"Home": "Home", "End": "End", "PageUp": "Prior", "PageDown": "Next",
+	"Left": "Left", "Right": "Right", "Up": "Up", "Down": "Down",
+	"Tab": "Tab", "Space": "space", "Plus": "plus", "Minus": "minus", "=": "equal",
+}
+
+// typedKeysyms are the keys the GUI types into the expression; binding them
+// without Ctrl or Alt would stop them from being typed
+var typedKeysyms = map[string]bool{
+	"0": true, "1": true, "2": true, "3": true, "4": true, "5": true, "6": true, "7": true, "8": true, "9": true,
+	"plus": true, "minus": true,
+}
+
+// hexDigitKeysyms are the letters the programmer keypad types as hex digits
+var hexDigitKeysyms = map[string]bool{
+	"a": true, "b": true, "c": true, "d": true, "e": true, "f": true,
+	"A": true, "B": true, "C": true, "D": true, "E": true, "F": true,
+}
+
+// typesIntoExpression reports whether the calculator types the key itself
+// when it is pressed without Ctrl or Alt
+func typesIntoExpression(config CalculatorConfig, keys parsedKeys) bool {
+	if keys.ctrl || keys.alt {
+		return false
+	}
+	return typedKeysyms[keys.keysym] || config.Features.Programming && hexDigitKeysyms[keys.keysym]
+}
+
+// parsedKeys is a shortcut split into its parts
+type parsedKeys struct {
+	ctrl, alt, shift bool
+	name             string // canonical key name, e.g. "Z", "F2", "Esc"
+	keysym           string // Tk keysym, e.g. "z", "F2", "Escape"
+}
+
+// parseKeys parses a shortcut written as modifiers and a key joined by "+"
+func parseKeys(keys string) (parsedKeys, error) {
+	var parsed parsedKeys
+	parts := strings.Split(strings.TrimSpace(keys), "+")
+	key := strings.TrimSpace(parts[len(parts)-1])
+
+	for _, part := range parts[:len(parts)-1] {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "ctrl", "control":
+			parsed.ctrl = true
+		case "alt":
+			parsed.alt = true
+		case "shift":
+			parsed.shift = true
+		default:
+			return parsed, fmt.Errorf("unknown modifier %q in %q (use Ctrl, Alt or Shift)", part, keys)
+		}
+	}
+
+	lower := strings.ToLower(key)
+	switch {
+	case key == "":
+		return parsed, fmt.Errorf("%q has no key", keys)
+	case len(key) == 1 && (key[0] >= 'a' && key[0] <= 'z' || key[0] >= 'A' && key[0] <= 'Z'):
+		parsed.name = strings.ToUpper(key)
+		parsed.keysym = lower
+		if parsed.shift {
+			parsed.keysym = parsed.name
+		}
+	case len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+		parsed.name, parsed.keysym = key, key
+	case len(lower) >= 2 && lower[0] == 'f' && isFunctionKeyNumber(lower[1:]):
+		parsed.name = strings.ToUpper(lower)
+		parsed.keysym = parsed.name
+	case keyAliases[lower] != "":
+		parsed.name = keyAliases[lower]
+		parsed.keysym = namedKeysyms[parsed.name]
+	default:
+		return parsed, fmt.Errorf("unknown key %q in %q", key, keys)
+	}
+	return parsed, nil
+}
+
+// isFunctionKeyNumber reports whether digits name a function key F1-F12
+func isFunctionKeyNumber(digits string) bool {
+	var n int
+	if _, err := fmt.Sscanf(digits, "%d", &n); err != nil || fmt.Sprint(n) != digits {
+		return false
+	}
+	return n >= 1 && n <= 12
+}
+
+// String returns the canonical spelling, so "ctrl+shift+z" and
+// "Shift+Ctrl+Z" are recognised as the same shortcut
+func (k parsedKeys) String() string {
+	var parts []string
+	if k.ctrl {
+		parts = append(parts, "Ctrl")
+	}
+	if k.alt {
+		parts = append(parts, "Alt")
+	}
+	if k.shift {
+		parts = append(parts, "Shift")
+	}
+	return strings.Join(append(parts, k.name), "+")
+}
+
+// sequences returns the Tk event sequences for the shortcut. A letter
+// without modifiers is also bound in upper case so it works with Caps Lock.
+func (k parsedKeys) sequences() []string {
+	sequences := []string{k.sequence()}
+	if !k.ctrl && !k.alt && !k.shift && len(k.keysym) == 1 && k.keysym != k.name {
+		upper := k
+		upper.keysym = k.name
+		sequences = append(sequences, upper.sequence())
+	}
+	return sequences
+}
+
+// sequence returns the Tk event sequence for the shortcut
+func (k parsedKeys) sequence() string {
+	var parts []string
+	if k.ctrl {
+		parts = append(parts, "Control")
+	}
+	if k.alt {
+		parts = append(parts, "Alt")
+	}
+	if k.shift {
+		parts = append(parts, "Shift")
+	}
+	// Tk reads a bare digit as a mouse button, and a bare character needs
+	// the Key- prefix to be a key press
+	if len(k.keysym) == 1 {
+		parts = append(parts, "Key")
+	}
+	return "<" + strings.Join(append(parts, k.keysym), "-") + ">"
+}
+
+// resolvedBinding is a validated shortcut ready to be emitted
+type resolvedBinding struct {
+	keys   parsedKeys
+	action keymapAction
+}
+
+// resolveKeymap merges the configured bindings over the defaults and
+// validates the result. A configured binding replaces the default shortcuts
+// of its action and any default using the same keys. Defaults for actions
+// whose feature is disabled are dropped; configuring one is an error, as is
+// binding the same keys twice or binding a key the calculator types.
+func resolveKeymap(config CalculatorConfig) ([]resolvedBinding, error) {
+	actions := make(map[string]keymapAction)
+	for _, action := range keymapActions() {
+		actions[action.name] = action
+	}
+
+	var custom []resolvedBinding
+	rebound := make(map[string]bool)
+	taken := make(map[string]string)
+	for i, binding := range config.UI.Keymap {
+		field := fmt.Sprintf("keymap[%d]", i)
+		action, ok := actions[binding.Action]
+		if !ok {
+			return nil, ValidationError{Field: field, Message: fmt.Sprintf("unknown action %q", binding.Action)}
+		}
+		if !uiFeatureEnabled(config, action.feature) {
+			return nil, ValidationError{Field: field, Message: fmt.Sprintf("action %q needs the %s feature", action.name, action.feature)}
+		}
+
+		keys, err := parseKeys(binding.Keys)
+		if err != nil {
+			return nil, ValidationError{Field: field, Message: err.Error()}
+		}
+		if typesIntoExpression(config, keys) {
+			return nil, ValidationError{Field: field, Message: fmt.Sprintf("%s types into the expression; add Ctrl or Alt", keys)}
+		}
+		if other, conflict := taken[keys.String()]; conflict {
+			return nil, ValidationError{Field: field, Message: fmt.Sprintf("%s is bound to both %s and %s", keys, other, action.name)}
+		}
+
+		taken[keys.String()] = action.name
+		rebound[action.name] = true
+		custom = append(custom, resolvedBinding{keys, action})
+	}
+
+	var bindings []resolvedBinding
+	for _, binding := range DefaultKeymap() {
+		action := actions[binding.Action]
+		keys, _ := parseKeys(binding.Keys)
+		if rebound[action.name] || taken[keys.String()] != "" || !uiFeatureEnabled(config, action.feature) {
+			continue
+		}
+		bindings = append(bindings, resolvedBinding{keys, action})
+	}
+	return append(bindings, custom...), nil
+}
+
+// ValidateKeymap checks the configured bindings against the actions and
+// features of the configuration, as validation does
+func ValidateKeymap(config CalculatorConfig) error {
+	_, err := resolveKeymap(config)
+	return err
+}
+
+// ParseKeyBinding parses "KEYS=ACTION", such as "Ctrl+M=memory_store" or
+// "==calculate"
+func ParseKeyBinding(value string) (KeyBinding, error) {
+	split := strings.LastIndex(value, "=")
+	if split <= 0 || split == len(value)-1 {
+		return KeyBinding{}, fmt.Errorf("%q is not a binding such as Ctrl+M=memory_store", value)
+	}
+	return KeyBinding{Keys: value[:split], Action: value[split+1:]}, nil
+}
+
+// KeymapActionNames lists the actions shortcuts can be bound to, with their
+// descriptions
+func KeymapActionNames() [][2]string {
+	var names [][2]string
+	for _, action := range keymapActions() {
+		names = append(names, [2]string{action.name, action.description})
+	}
+	return names
+}
@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultKeymapLeavesTypedKeys(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "gui"
+	config.Features.Memory = true
+	config.Features.History = true
+	config.Features.Trigonometric = true
+	config.Features.Programming = true
+
+	for _, binding := range DefaultKeymap() {
+		keys, err := parseKeys(binding.Keys)
+		if err != nil {
+			t.Fatalf("default %s: %v", binding.Keys, err)
+		}
+		if typesIntoExpression(config, keys) {
+			t.Errorf("default %s=%s takes a key the programmer keypad types", binding.Keys, binding.Action)
+		}
+	}
+	if err := ValidateKeymap(config); err != nil {
+		t.Errorf("ValidateKeymap() = %v", err)
+	}
+
+	source, err := NewGUIGenerator(config).GenerateGUICalculator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sequence := range []string{"<Key-c>", "<Key-C>"} {
+		if strings.Contains(source, sequence) {
+			t.Errorf("the GUI binds %s, which is the hex digit C", sequence)
+		}
+	}
+	if !strings.Contains(source, "<Control-Key-l>") {
+		t.Error("the GUI does not bind Ctrl+L to clear")
+	}
+}
+
+func TestValidateKeymap(t *testing.T) {
+	tests := []struct {
+		name        string
+		programming bool
+		binding     KeyBinding
+		want        string // part of the error, empty for none
+	}{
+		{"letter", false, KeyBinding{"C", "clear"}, ""},
+		{"hex digit", true, KeyBinding{"C", "clear"}, "types into the expression"},
+		{"hex digit with Ctrl", true, KeyBinding{"Ctrl+C", "clear"}, ""},
+		{"other letter", true, KeyBinding{"G", "clear"}, ""},
+		{"digit", false, KeyBinding{"7", "clear"}, "types into the expression"},
+		{"unknown action", false, KeyBinding{"F9", "explode"}, `unknown action "explode"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.UI.Style = "gui"
+			config.Features.Programming = tt.programming
+			config.UI.Keymap = []KeyBinding{tt.binding}
+			err := ValidateKeymap(config)
+			if tt.want == "" && err != nil {
+				t.Errorf("ValidateKeymap() = %v", err)
+			}
+			if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Errorf("ValidateKeymap() = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		keys      string
+		name      string
+		sequences []string
+	}{
+		{"Ctrl+M", "Ctrl+M", []string{"<Control-Key-m>"}},
+		{"ctrl+shift+z", "Ctrl+Shift+Z", []string{"<Control-Shift-Key-Z>"}},
+		{"g", "G", []string{"<Key-g>", "<Key-G>"}},
+		{"escape", "Esc", []string{"<Escape>"}},
+		{"Alt+Return", "Alt+Enter", []string{"<Alt-Return>"}},
+		{"Ctrl+Plus", "Ctrl+Plus", []string{"<Control-plus>"}},
+		{"Ctrl+=", "Ctrl+=", []string{"<Control-equal>"}},
+		{"Ctrl+0", "Ctrl+0", []string{"<Control-Key-0>"}},
+		{"F12", "F12", []string{"<F12>"}},
+	}
+
+	for _, tt := range tests {
+		keys, err := parseKeys(tt.keys)
+		if err != nil {
+			t.Errorf("parseKeys(%q) = %v", tt.keys, err)
+			continue
+		}
+		if keys.String() != tt.name || !reflect.DeepEqual(keys.sequences(), tt.sequences) {
+			t.Errorf("parseKeys(%q) = %s %v, want %s %v", tt.keys, keys, keys.sequences(), tt.name, tt.sequences)
+		}
+	}
+
+	for _, keys := range []string{"", "Ctrl+", "Hyper+X", "F13", "Ctrl+Banana"} {
+		if _, err := parseKeys(keys); err == nil {
+			t.Errorf("parseKeys(%q) accepted an invalid shortcut", keys)
+		}
+	}
+}
+
+func TestGUIRendersKeymap(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "gui"
+	config.Features.Memory = true
+	config.UI.Keymap = []KeyBinding{
+		{"F5", "calculate"},
+		{"Ctrl+L", "memory_recall"},
+		{"Alt+M", "memory_store"},
+	}
+	source := generateGUI(t, config)
+
+	for _, want := range []string{
+		// A configured action keeps only its configured keys; the other
+		// defaults of a key taken by a configured binding are dropped
+		"    (\"F5\", \"Calculate\"),\n",
+		"    (\"Esc\", \"Clear everything\"),\n",
+		"    (\"Ctrl+L\", \"Memory recall\"),\n",
+		"    (\"Alt+M\", \"Memory store\"),\n",
+		"        self.root.bind('<F5>', lambda event: self.calculate())  # F5\n",
+		"        self.root.bind('<Control-Key-l>', lambda event: self.memory_recall())  # Ctrl+L\n",
+		"        self.root.bind('<Alt-Key-m>', lambda event: self.memory_store())  # Alt+M\n",
+		"        self.root.bind('<F1>', lambda event: self.show_shortcuts())  # F1\n",
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("GUI is missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{
+		"lambda event: self.clear())  # Ctrl+L",
+		"<Control-Key-m>",
+		"lambda event: self.calculate())  # Enter",
+		"Toggle degrees/radians",
+		"self.show_history())",
+	} {
+		if strings.Contains(source, unwanted) {
+			t.Errorf("GUI has %q, which the keymap replaced or whose feature is off", unwanted)
+		}
+	}
+}
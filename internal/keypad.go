@@ -0,0 +1,394 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KeypadLayout is a declarative GUI keypad. Rows are written as button ids
+// separated by spaces and are placed left to right, skipping cells covered by
+// buttons spanning down from earlier rows:
+//
+//	columns: 4
+//	rows:
+//	  - "7 8 9 /"
+//	  - "0*2 . ="     # 0 is two columns wide
+//	  - "_ _ C*2x1"   # _ leaves a cell empty, WxH spans columns and rows
+//
+// Buttons can also be placed explicitly by row and column.
+type KeypadLayout struct {
+	Name    string       `json:"name" yaml:"name"`
+	Columns int          `json:"columns" yaml:"columns"`
+	Rows    []string     `json:"rows,omitempty" yaml:"rows"`
+	Buttons []KeypadCell `json:"buttons,omitempty" yaml:"buttons"`
+}
+
+// KeypadCell places one button on the keypad grid
+type KeypadCell struct {
+	ID         string `json:"id" yaml:"id"`
+	Row        int    `json:"row" yaml:"row"`
+	Column     int    `json:"column" yaml:"column"`
+	RowSpan    int    `json:"rowspan,omitempty" yaml:"rowspan"`
+	ColumnSpan int    `json:"columnspan,omitempty" yaml:"columnspan"`
+}
+
+// keypadButton describes a button that layouts can place
+type keypadButton struct {
+	label   string
+	command string // Python callable run when the button is pressed
+	style   string // ttk style, one per theme role
+	feature string // feature the button needs, empty when always available
+}
+
+// keypadCatalogue returns every button a keypad layout may use, by id
+func keypadCatalogue() map[string]keypadButton {
+	buttons := map[string]keypadButton{
+		"=":     {"=", "self.calculate", "Operator.TButton", ""},
+		"C":     {"C", "self.clear", "Operator.TButton", ""},
+		"CE":    {"CE", "self.clear_entry", "Operator.TButton", ""},
+		"back":  {"⌫", "self.backspace", "Operator.TButton", ""},
+		"ans":   {"Ans", "lambda: self.append_reference('ans')", "Operator.TButton", ""},
+		"left":  {"◀", "lambda: self.move_cursor(-1)", "Operator.TButton", ""},
+		"right": {"▶", "lambda: self.move_cursor(1)", "Operator.TButton", ""},
+		"undo":  {"↶", "self.undo", "Operator.TButton", ""},
+		"redo":  {"↷", "self.redo", "Operator.TButton", ""},
+
+		"MS": {"MS", "self.memory_store", "Memory.TButton", "memory"},
+		"MR": {"MR", "self.memory_recall", "Memory.TButton", "memory"},
+		"MC": {"MC", "self.memory_clear", "Memory.TButton", "memory"},
+		"M+": {"M+", "self.memory_add", "Memory.TButton", "memory"},
+		"M-": {"M-", "self.memory_subtract", "Memory.TButton", "memory"},
+
+		"stats": {"Stats", "self.show_stats_dialog", "Function.TButton", "statistical"},
+	}
+
+	for _, digit := range strings.Split("0 1 2 3 4 5 6 7 8 9 .", " ") {
+		buttons[digit] = keypadButton{digit, fmt.Sprintf("lambda: self.append_number('%s')", digit), "Digit.TButton", ""}
+	}
+
+	operators := map[string]string{"+": "+", "-": "-", "*": "×", "/": "÷", "%": "%", "^": "^"}
+	for id, label := range operators {
+		buttons[id] = keypadButton{label, fmt.Sprintf("lambda: self.append_operator('%s')", id), "Operator.TButton", ""}
+	}
+
+	symbols := map[string]string{"(": "", ")": "", "pi": "", "e": "", "[": "statistical", "]": "statistical", ",": "statistical"}
+	for id, feature := range symbols {
+		label := id
+		if id == "pi" {
+			label = "π"
+		}
+		command := fmt.Sprintf("lambda: self.append_symbol('%s')", id)
+		if id == "," {
+			// Function arguments are separated in the locale's notation
+			command = "lambda: self.append_symbol(ARGUMENT_SEPARATOR)"
+		}
+		buttons[id] = keypadButton{label, command, "Operator.TButton", feature}
+	}
+
+	functions := map[string]string{
+		"sqrt": "", "exp": "",
+		"sin": "trigonometric", "cos": "trigonometric", "tan": "trigonometric",
+		"asin": "trigonometric", "acos": "trigonometric", "atan": "trigonometric",
+		"log": "logarithmic", "ln": "logarithmic",
+		"mean": "statistical", "median": "statistical", "std": "statistical", "var": "statistical",
+		"hex": "programming", "bin": "programming", "oct": "programming", "int": "programming",
+	}
+	for id, feature := range functions {
+		buttons[id] = keypadButton{id, fmt.Sprintf("lambda: self.append_function('%s')", id), "Function.TButton", feature}
+	}
+
+	// Hex digits and prefixes; C already clears, so hex C is "hexC"
+	for _, id := range strings.Split("A B D E F 0x 0b", " ") {
+		buttons[id] = keypadButton{id, fmt.Sprintf("lambda: self.append_symbol('%s')", id), "Digit.TButton", "programming"}
+	}
+	buttons["hexC"] = keypadButton{"C", "lambda: self.append_symbol('C')", "Digit.TButton", "programming"}
+	for _, id := range strings.Split("& | ~ << >> //", " ") {
+		buttons[id] = keypadButton{id, fmt.Sprintf("lambda: self.append_operator('%s')", id), "Operator.TButton", "programming"}
+	}
+
+	return buttons
+}
+
+// uiFeatureEnabled reports whether GUI buttons and shortcuts needing feature
+// can be used
+func uiFeatureEnabled(config CalculatorConfig, feature string) bool {
+	switch feature {
+	case "":
+		return true
+	case "memory":
+		return config.Features.Memory
+	case "history":
+		return config.Features.History
+	case "trigonometric":
+		return config.Features.Trigonometric
+	case "logarithmic":
+		return config.Features.Logarithmic
+	case "statistical":
+		return config.Features.Statistical && config.Libraries.UseNumpy
+	case "programming":
+		return config.Features.Programming
+	}
+	return false
+}
+
+// KeypadNames lists the built-in keypad layouts
+func KeypadNames() []string {
+	return []string{"basic", "scientific", "programmer", "statistics"}
+}
+
+// BuiltinKeypad returns the built-in keypad layout with the given name
+func BuiltinKeypad(name string) (KeypadLayout, bool) {
+	switch name {
+	case "basic":
+		return KeypadLayout{Name: "basic", Columns: 5, Rows: []string{
+			"MS MR MC M+ M-",
+			"left right undo redo ans",
+			"CE C back ( )",
+			"7 8 9 / %",
+			"4 5 6 * ^",
+			"1 2 3 - =*1x2",
+			"0*2 . +",
+		}}, true
+	case "scientific":
+		return KeypadLayout{Name: "scientific", Columns: 5, Rows: []string{
+			"MS MR MC M+ M-",
+			"sin cos tan pi sqrt",
+			"asin acos atan log ln",
+			"left right undo redo ans",
+			"CE C back ( )",
+			"7 8 9 / %",
+			"4 5 6 * ^",
+			"1 2 3 - =*1x2",
+			"0*2 . +",
+		}}, true
+	case "programmer":
+		return KeypadLayout{Name: "programmer", Columns: 5, Rows: []string{
+			"MS MR MC M+ M-",
+			"hex bin oct int ans",
+			"A B hexC 0x 0b",
+			"D E F << >>",
+			"& | ~ // %",
+			"CE C back ( )",
+			"7 8 9 / left",
+			"4 5 6 * right",
+			"1 2 3 - =*1x2",
+			"0*2 . +",
+		}}, true
+	case "statistics":
+		return KeypadLayout{Name: "statistics", Columns: 5, Rows: []string{
+			"MS MR MC M+ M-",
+			"mean median std var stats",
+			"[ ] , sqrt ans",
+			"CE C back ( )",
+			"7 8 9 / %",
+			"4 5 6 * ^",
+			"1 2 3 - =*1x2",
+			"0*2 . +",
+		}}, true
+	}
+	return KeypadLayout{}, false
+}
+
+// LoadKeypadFile reads a YAML keypad layout
+func LoadKeypadFile(path string) (KeypadLayout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return KeypadLayout{}, fmt.Errorf("reading keypad file: %w", err)
+	}
+
+	var layout KeypadLayout
+	if err := yaml.Unmarshal(data, &layout); err != nil {
+		return KeypadLayout{}, fmt.Errorf("parsing keypad file %s: %w", path, err)
+	}
+	if layout.Name == "" {
+		layout.Name = "custom"
+	}
+	return layout, nil
+}
+
+// ResolveKeypad returns the keypad the GUI uses: the custom layout if one was
+// loaded, otherwise the named built-in, otherwise the layout matching the
+// calculator type. The flag reports whether the layout is built in.
+func ResolveKeypad(config CalculatorConfig) (KeypadLayout, bool) {
+	if config.UI.CustomKeypad != nil {
+		return *config.UI.CustomKeypad, false
+	}
+	name := config.UI.Keypad
+	if name == "" {
+		name = string(config.Type)
+	}
+	if layout, ok := BuiltinKeypad(name); ok {
+		return layout, true
+	}
+	layout, _ := BuiltinKeypad("basic")
+	return layout, true
+}
+
+// withoutUnavailable drops buttons whose feature is disabled from a built-in
+// layout, closing the gaps they leave and removing rows that become empty
+func (l KeypadLayout) withoutUnavailable(config CalculatorConfig) KeypadLayout {
+	catalogue := keypadCatalogue()
+	filtered := KeypadLayout{Name: l.Name, Columns: l.Columns, Buttons: l.Buttons}
+
+	for _, row := range l.Rows {
+		var tokens []string
+		for _, token := range strings.Fields(row) {
+			id, _, _ := splitKeypadToken(token)
+			if button, ok := catalogue[id]; ok && !uiFeatureEnabled(config, button.feature) {
+				continue
+			}
+			tokens = append(tokens, token)
+		}
+		if len(tokens) > 0 {
+			filtered.Rows = append(filtered.Rows, strings.Join(tokens, " "))
+		}
+	}
+	return filtered
+}
+
+// droppedButtons returns the ids of the layout's row buttons that
+// withoutUnavailable drops, by the disabled feature they need, and the
+// features in the order they first appear
+func (l KeypadLayout) droppedButtons(config CalculatorConfig) ([]string, map[string][]string) {
+	catalogue := keypadCatalogue()
+	var features []string
+	dropped := make(map[string][]string)
+
+	for _, row := range l.Rows {
+		for _, token := range strings.Fields(row) {
+			id, _, _ := splitKeypadToken(token)
+			button, ok := catalogue[id]
+			if !ok || uiFeatureEnabled(config, button.feature) {
+				continue
+			}
+			if dropped[button.feature] == nil {
+				features = append(features, button.feature)
+			}
+			dropped[button.feature] = append(dropped[button.feature], id)
+		}
+	}
+	return features, dropped
+}
+
+var keypadTokenPattern = regexp.MustCompile(`^(.+?)\*(\d+)(?:x(\d+))?$`)
+
+// splitKeypadToken splits "id*WxH" into the id and its column and row spans
+func splitKeypadToken(token string) (id string, columnSpan, rowSpan int) {
+	match := keypadTokenPattern.FindStringSubmatch(token)
+	if match == nil {
+		return token, 1, 1
+	}
+	columnSpan, _ = strconv.Atoi(match[2])
+	rowSpan = 1
+	if match[3] != "" {
+		rowSpan, _ = strconv.Atoi(match[3])
+	}
+	return match[1], columnSpan, rowSpan
+}
+
+// Compile places every button on the grid and validates the result: ids must
+// exist and be enabled, buttons must fit within the columns, may only appear
+// once and must not overlap. It returns the cells and the number of rows.
+func (l KeypadLayout) Compile(config CalculatorConfig) ([]KeypadCell, int, error) {
+	if l.Columns < 1 || l.Columns > 12 {
+		return nil, 0, ValidationError{Field: "keypad.columns", Message: "columns must be between 1 and 12"}
+	}
+
+	occupied := make(map[[2]int]string)
+	seen := make(map[string]bool)
+	catalogue := keypadCatalogue()
+	var cells []KeypadCell
+	rows := 0
+
+	place := func(field string, cell KeypadCell) error {
+		if cell.RowSpan == 0 {
+			cell.RowSpan = 1
+		}
+		if cell.ColumnSpan == 0 {
+			cell.ColumnSpan = 1
+		}
+
+		button, ok := catalogue[cell.ID]
+		switch {
+		case !ok:
+			return ValidationError{Field: field, Message: fmt.Sprintf("unknown button %q", cell.ID)}
+		case !uiFeatureEnabled(config, button.feature):
+			return ValidationError{Field: field, Message: fmt.Sprintf("button %q needs the %s feature", cell.ID, button.feature)}
+		case seen[cell.ID]:
+			return ValidationError{Field: field, Message: fmt.Sprintf("button %q is placed more than once", cell.ID)}
+		case cell.Row < 0 || cell.Column < 0 || cell.RowSpan < 1 || cell.ColumnSpan < 1:
+			return ValidationError{Field: field, Message: fmt.Sprintf("button %q has a negative position or an empty span", cell.ID)}
+		case cell.Column+cell.ColumnSpan > l.Columns:
+			return ValidationError{Field: field, Message: fmt.Sprintf("button %q extends past column %d", cell.ID, l.Columns)}
+		}
+
+		for r := cell.Row; r < cell.Row+cell.RowSpan; r++ {
+			for c := cell.Column; c < cell.Column+cell.ColumnSpan; c++ {
+				if other, taken := occupied[[2]int{r, c}]; taken {
+					return ValidationError{Field: field, Message: fmt.Sprintf("button %q overlaps %q at row %d, column %d", cell.ID, other, r, c)}
+				}
+				occupied[[2]int{r, c}] = cell.ID
+			}
+		}
+
+		seen[cell.ID] = true
+		cells = append(cells, cell)
+		if cell.Row+cell.RowSpan > rows {
+			rows = cell.Row + cell.RowSpan
+		}
+		return nil
+	}
+
+	for r, row := range l.Rows {
+		column := 0
+		for _, token := range strings.Fields(row) {
+			field := fmt.Sprintf("keypad.rows[%d]", r)
+			for column < l.Columns && occupied[[2]int{r, column}] != "" {
+				column++
+			}
+			id, columnSpan, rowSpan := splitKeypadToken(token)
+			if id == "_" {
+				column += columnSpan
+				continue
+			}
+			if err := place(field, KeypadCell{ID: id, Row: r, Column: column, RowSpan: rowSpan, ColumnSpan: columnSpan}); err != nil {
+				return nil, 0, err
+			}
+			column += columnSpan
+		}
+	}
+
+	for i, cell := range l.Buttons {
+		if err := place(fmt.Sprintf("keypad.buttons[%d]", i), cell); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if len(cells) == 0 {
+		return nil, 0, ValidationError{Field: "keypad", Message: "keypad has no buttons"}
+	}
+
+	sort.SliceStable(cells, func(i, j int) bool {
+		if cells[i].Row != cells[j].Row {
+			return cells[i].Row < cells[j].Row
+		}
+		return cells[i].Column < cells[j].Column
+	})
+	return cells, rows, nil
+}
+
+// compileKeypad resolves and compiles the configured keypad
+func compileKeypad(config CalculatorConfig) (KeypadLayout, []KeypadCell, int, error) {
+	layout, builtin := ResolveKeypad(config)
+	if builtin {
+		layout = layout.withoutUnavailable(config)
+	}
+	cells, rows, err := layout.Compile(config)
+	return layout, cells, rows, err
+}
@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKeypadCompile(t *testing.T) {
+	layout := KeypadLayout{Name: "spans", Columns: 3, Rows: []string{"7 8 =*1x2", "_ 0"}, Buttons: []KeypadCell{
+		{ID: "C", Row: 2, Column: 0, ColumnSpan: 3},
+	}}
+
+	cells, rows, err := layout.Compile(GetDefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []KeypadCell{
+		{ID: "7", Row: 0, Column: 0, RowSpan: 1, ColumnSpan: 1},
+		{ID: "8", Row: 0, Column: 1, RowSpan: 1, ColumnSpan: 1},
+		{ID: "=", Row: 0, Column: 2, RowSpan: 2, ColumnSpan: 1},
+		{ID: "0", Row: 1, Column: 1, RowSpan: 1, ColumnSpan: 1},
+		{ID: "C", Row: 2, Column: 0, RowSpan: 1, ColumnSpan: 3},
+	}
+	if !reflect.DeepEqual(cells, want) || rows != 3 {
+		t.Errorf("Compile() = %+v, %d rows; want %+v, 3 rows", cells, rows, want)
+	}
+}
+
+func TestKeypadCompileErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout KeypadLayout
+		field  string
+		want   string
+	}{
+		{
+			name:   "no columns",
+			layout: KeypadLayout{Rows: []string{"7"}},
+			field:  "keypad.columns",
+			want:   "columns must be between 1 and 12",
+		},
+		{
+			name:   "unknown button",
+			layout: KeypadLayout{Columns: 2, Rows: []string{"7 sinh"}},
+			field:  "keypad.rows[0]",
+			want:   `unknown button "sinh"`,
+		},
+		{
+			name:   "disabled feature",
+			layout: KeypadLayout{Columns: 2, Rows: []string{"7 hex"}},
+			field:  "keypad.rows[0]",
+			want:   `button "hex" needs the programming feature`,
+		},
+		{
+			name:   "placed twice",
+			layout: KeypadLayout{Columns: 3, Rows: []string{"7 8 7"}},
+			field:  "keypad.rows[0]",
+			want:   `button "7" is placed more than once`,
+		},
+		{
+			name:   "row past the last column",
+			layout: KeypadLayout{Columns: 2, Rows: []string{"7 8 9"}},
+			field:  "keypad.rows[0]",
+			want:   `button "9" extends past column 2`,
+		},
+		{
+			name:   "span past the last column",
+			layout: KeypadLayout{Columns: 3, Rows: []string{"7 0*3"}},
+			field:  "keypad.rows[0]",
+			want:   `button "0" extends past column 3`,
+		},
+		{
+			name:   "negative position",
+			layout: KeypadLayout{Columns: 2, Buttons: []KeypadCell{{ID: "7", Row: -1}}},
+			field:  "keypad.buttons[0]",
+			want:   `button "7" has a negative position or an empty span`,
+		},
+		{
+			name:   "empty span",
+			layout: KeypadLayout{Columns: 2, Buttons: []KeypadCell{{ID: "7", ColumnSpan: -1}}},
+			field:  "keypad.buttons[0]",
+			want:   `button "7" has a negative position or an empty span`,
+		},
+		{
+			name:   "overlapping placement",
+			layout: KeypadLayout{Columns: 2, Rows: []string{"7 8"}, Buttons: []KeypadCell{{ID: "9", Row: 0, Column: 1}}},
+			field:  "keypad.buttons[0]",
+			want:   `button "9" overlaps "8" at row 0, column 1`,
+		},
+		{
+			name:   "overlapping row span",
+			layout: KeypadLayout{Columns: 2, Rows: []string{"7*2x2"}, Buttons: []KeypadCell{{ID: "0", Row: 1, Column: 0, ColumnSpan: 2}}},
+			field:  "keypad.buttons[0]",
+			want:   `button "0" overlaps "7" at row 1, column 0`,
+		},
+		{
+			name:   "no buttons",
+			layout: KeypadLayout{Columns: 2, Rows: []string{"_ _"}},
+			field:  "keypad",
+			want:   "keypad has no buttons",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.layout.Compile(GetDefaultConfig())
+			validation, ok := err.(ValidationError)
+			if !ok || validation.Field != tt.field || validation.Message != tt.want {
+				t.Errorf("Compile() error = %v, want %s: %s", err, tt.field, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeypadDroppedButtonsWarning(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "gui"
+	config.UI.Keypad = "programmer"
+	config.Features.Memory = true
+
+	var warnings []string
+	for _, issue := range ValidateConfig(config) {
+		if issue.Field == "ui.keypad" && issue.Severity == SeverityWarning {
+			warnings = append(warnings, issue.Message)
+		}
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "drops 18 buttons") || !strings.Contains(warnings[0], "programming feature is off") {
+		t.Errorf("warnings = %q, want one about the programming buttons", warnings)
+	}
+
+	// Nothing is dropped once the feature is on, and the keypad that follows
+	// the calculator type is never reported
+	config.Features.Programming = true
+	programmer := config
+	automatic := GetDefaultConfig()
+	automatic.UI.Style = "gui"
+	for _, config := range []CalculatorConfig{programmer, automatic} {
+		for _, issue := range ValidateConfig(config) {
+			if issue.Field == "ui.keypad" {
+				t.Errorf("keypad %q: unexpected %s %s", config.UI.Keypad, issue.Severity, issue.Message)
+			}
+		}
+	}
+}
+
+func TestBuiltinKeypadsCompile(t *testing.T) {
+	full := GetDefaultConfig()
+	full.Features.Memory = true
+	full.Features.Trigonometric = true
+	full.Features.Logarithmic = true
+	full.Features.Statistical = true
+	full.Features.Programming = true
+	full.Libraries.UseNumpy = true
+
+	for _, name := range KeypadNames() {
+		t.Run(name, func(t *testing.T) {
+			layout, ok := BuiltinKeypad(name)
+			if !ok || layout.Name != name {
+				t.Fatalf("BuiltinKeypad(%q) = %q, %v", name, layout.Name, ok)
+			}
+
+			// With every feature on, each row fills the grid exactly
+			cells, rows, err := layout.Compile(full)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if rows != len(layout.Rows) {
+				t.Errorf("%d rows, want %d", rows, len(layout.Rows))
+			}
+			area := 0
+			for _, cell := range cells {
+				area += cell.RowSpan * cell.ColumnSpan
+			}
+			if area != rows*layout.Columns {
+				t.Errorf("buttons cover %d of %d cells", area, rows*layout.Columns)
+			}
+
+			// With the defaults, buttons of disabled features are dropped and
+			// the layout still compiles
+			config := GetDefaultConfig()
+			config.UI.Keypad = name
+			if _, cells, _, err := compileKeypad(config); err != nil {
+				t.Errorf("compileKeypad() with the defaults error = %v", err)
+			} else {
+				for _, cell := range cells {
+					if cell.ID == "MS" {
+						t.Error("memory buttons are kept without the memory feature")
+					}
+				}
+			}
+		})
+	}
+
+	if _, ok := BuiltinKeypad("qwerty"); ok {
+		t.Error("BuiltinKeypad(qwerty) found a layout")
+	}
+}
+
+func TestLoadKeypadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pad.yaml")
+	content := "columns: 3\nrows:\n  - \"7 8 9\"\n  - \"0*2 =\"\nbuttons:\n  - id: C\n    row: 2\n    column: 0\n    columnspan: 3\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	layout, err := LoadKeypadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := KeypadLayout{Name: "custom", Columns: 3, Rows: []string{"7 8 9", "0*2 ="}, Buttons: []KeypadCell{
+		{ID: "C", Row: 2, Column: 0, ColumnSpan: 3},
+	}}
+	if !reflect.DeepEqual(layout, want) {
+		t.Errorf("LoadKeypadFile() = %+v, want %+v", layout, want)
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("rows: {\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKeypadFile(bad); err == nil || !strings.Contains(err.Error(), "parsing keypad file") {
+		t.Errorf("LoadKeypadFile() of bad YAML error = %v", err)
+	}
+}
+
+func TestGUIKeypadGrid(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.CustomKeypad = &KeypadLayout{Name: "pad", Columns: 3, Rows: []string{"7 8 =*1x2", "0*2"}, Buttons: []KeypadCell{
+		{ID: "C", Row: 2, Column: 0, ColumnSpan: 3},
+	}}
+	source := generateGUI(t, config)
+
+	want := `        # "pad" keypad (3 columns x 3 rows)
+        self.buttons['7'].grid(row=0, column=0, padx=2, pady=2, sticky='nsew')
+        self.buttons['8'].grid(row=0, column=1, padx=2, pady=2, sticky='nsew')
+        self.buttons['='].grid(row=0, column=2, rowspan=2, padx=2, pady=2, sticky='nsew')
+        self.buttons['0'].grid(row=1, column=0, columnspan=2, padx=2, pady=2, sticky='nsew')
+        self.buttons['C'].grid(row=2, column=0, columnspan=3, padx=2, pady=2, sticky='nsew')
+
+        # Configure grid weights so the keypad grows with the window,
+        # keeping columns and rows equally sized
+        for i in range(3):
+            self.button_frame.columnconfigure(i, weight=1, uniform='column')
+        for i in range(3):
+            self.button_frame.rowconfigure(i, weight=1, uniform='row')`
+	if !strings.Contains(source, want) {
+		t.Errorf("generated GUI does not lay out the keypad as\n%s", want)
+	}
+}
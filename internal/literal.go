@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Configuration strings are free text, so they are never spliced into the
+// generated code as they are: each one goes through the helper for the
+// context it lands in.
+
+// templateFuncs are the functions available to the calculator templates
+var templateFuncs = template.FuncMap{
+	"docstring": pythonDocstring,
+}
+
+// pythonString returns value as a double-quoted Python string literal.
+// Go's escapes are a subset of Python's, so a quoted Go string is also a
+// valid Python literal for the same text.
+func pythonString(value string) string {
+	return strconv.Quote(strings.ToValidUTF8(value, "�"))
+}
+
+// pythonDocstring escapes value for the body of a """ docstring. Line breaks
+// and tabs are kept so the text reads naturally; quotes, backslashes and
+// other control characters are escaped.
+func pythonDocstring(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToValidUTF8(value, "�") {
+		switch {
+		case r == '\\' || r == '"':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\t':
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// tomlString returns value as a TOML basic string
+func tomlString(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range strings.ToValidUTF8(value, "�") {
+		switch {
+		case r == '\\' || r == '"':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// htmlText escapes value for HTML text and attributes. Control characters
+// other than line breaks and tabs are replaced, as browsers do, which also
+// keeps the page valid inside the raw Python string it is served from.
+func htmlText(value string) string {
+	clean := strings.Map(func(r rune) rune {
+		if (r < 0x20 && r != '\n' && r != '\t') || r == 0x7f {
+			return '�'
+		}
+		return r
+	}, strings.ToValidUTF8(value, "�"))
+	return html.EscapeString(clean)
+}
+
+// cssString returns value as a double-quoted CSS string. Everything other
+// than letters, digits, spaces, hyphens and underscores is written as a hex
+// escape, so the value cannot end the string, the rule or the <style>
+// element, and it puts no quotes into the raw Python string either.
+func cssString(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range strings.ToValidUTF8(value, "�") {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' || r == '-' || r == '_' {
+			b.WriteRune(r)
+		} else {
+			fmt.Fprintf(&b, `\%x `, r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
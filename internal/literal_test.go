@@ -0,0 +1,216 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// hostileTexts break generated code that splices configuration strings in
+// without escaping them
+var hostileTexts = []string{
+	`My "Calc"`,
+	`{__import__('os').system('echo pwned')}`,
+	`""" + __import__("os").getcwd() + """`,
+	`C:\Users\me\`,
+	"two\nlines\r\nand a tab\t",
+	"nul\x00 and bell\a",
+	"invalid \xff utf-8",
+	"émoji 🧮 and \u2028 separator",
+	`</title><script>alert(1)</script>`,
+	`'; %d %s %%`,
+}
+
+// python returns the path of python3, skipping the test when there is none
+func python(t testing.TB) string {
+	t.Helper()
+	path, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is not installed")
+	}
+	return path
+}
+
+// runPython runs a Python program with stdin and returns its output
+func runPython(t testing.TB, program string, stdin []byte) (string, error) {
+	t.Helper()
+	cmd := exec.Command(python(t), "-c", program)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", &pythonError{err, stderr.String()}
+	}
+	return string(out), nil
+}
+
+// runPythonModule saves source as module.py in a temporary directory and
+// runs program there with HOME pointing at it, so the program can import
+// the module. Worker processes started with spawn or forkserver need to
+// import what they run, which exec'd source does not allow.
+func runPythonModule(t testing.TB, source, program string) (string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "module.py"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(python(t), "-c", program)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "HOME="+dir, "PYTHONPATH="+dir)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", &pythonError{err, stderr.String()}
+	}
+	return string(out), nil
+}
+
+type pythonError struct {
+	err    error
+	stderr string
+}
+
+func (e *pythonError) Error() string { return e.err.Error() + ": " + e.stderr }
+
+func TestPythonString(t *testing.T) {
+	var literals []string
+	for _, text := range hostileTexts {
+		literals = append(literals, pythonString(text))
+	}
+
+	// Python reads each literal back as the same text, with invalid UTF-8
+	// replaced
+	program := `import ast, json, sys
+print(json.dumps([ast.literal_eval(line) for line in sys.stdin.read().split("\n")]))`
+	out, err := runPython(t, program, []byte(strings.Join(literals, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values []string
+	if err := json.Unmarshal([]byte(out), &values); err != nil || len(values) != len(hostileTexts) {
+		t.Fatalf("Python read back %s", out)
+	}
+	for i, text := range hostileTexts {
+		if want := strings.ToValidUTF8(text, "�"); values[i] != want {
+			t.Errorf("pythonString(%q) reads back as %q", text, values[i])
+		}
+	}
+}
+
+func TestHTMLText(t *testing.T) {
+	got := htmlText(`</title><script>alert("x")</script>` + "\x00")
+	want := "&lt;/title&gt;&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;�"
+	if got != want {
+		t.Errorf("htmlText() = %q, want %q", got, want)
+	}
+}
+
+func TestCSSString(t *testing.T) {
+	got := cssString(`</style><script>"x"\` + "\n")
+	want := `"\3c \2f style\3e \3c script\3e \22 x\22 \5c \a "`
+	if got != want {
+		t.Errorf("cssString() = %q, want %q", got, want)
+	}
+}
+
+// FuzzGeneratedPythonCompiles checks that every style, the package layout
+// and the companion notebook are valid whatever the project name,
+// description and author are. The name is also the theme's font family and
+// the author the GUI's announce file.
+func FuzzGeneratedPythonCompiles(f *testing.F) {
+	for i, text := range hostileTexts {
+		f.Add(text, hostileTexts[(i+1)%len(hostileTexts)], hostileTexts[(i+2)%len(hostileTexts)])
+	}
+
+	f.Fuzz(func(t *testing.T, name, description, author string) {
+		if name == "" {
+			t.Skip("an empty project name is rejected by validation")
+		}
+		hostileConfig := func(style string) CalculatorConfig {
+			config := GetDefaultConfig()
+			config.ProjectName = name
+			config.Description = description
+			config.Author = author
+			config.UI.Style = style
+			config.UI.Announce = author
+			config.Features.Memory = true
+			config.Features.History = true
+			config.Storage.PersistMemory = true
+			config.Storage.MemoryFile = author
+			config.Storage.HistoryFile = description
+			theme := config.UI.ResolveTheme()
+			theme.Fonts.Display.Family = name
+			config.UI.CustomTheme = &theme
+			return config
+		}
+
+		for _, style := range styles {
+			source, err := NewGenerator(hostileConfig(style)).Render()
+			if err != nil {
+				t.Fatalf("%s: %v", style, err)
+			}
+			if _, err := runPython(t, `import sys; compile(sys.stdin.buffer.read(), "calculator.py", "exec")`, []byte(source)); err != nil {
+				t.Errorf("%s: generated code does not compile: %v", style, err)
+			}
+		}
+
+		for _, style := range []string{"cli", "gui"} {
+			config := hostileConfig(style)
+			config.Layout = "package"
+			files, err := NewPackageGenerator(config).Files()
+			if err != nil {
+				t.Fatalf("%s package: %v", style, err)
+			}
+			modules := make(map[string]string)
+			for _, file := range files {
+				if strings.HasSuffix(file.Path, ".py") {
+					modules[file.Path] = file.Content
+				}
+			}
+			source, err := json.Marshal(modules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			program := `import json, sys
+for path, content in json.load(sys.stdin).items():
+    compile(content, path, "exec")`
+			if _, err := runPython(t, program, source); err != nil {
+				t.Errorf("%s package: a module does not compile: %v", style, err)
+			}
+		}
+
+		// The notebook is JSON whose title cell reads back as written; code
+		// cells compile once IPython has rewritten their magics
+		notebook, err := NewNotebookGenerator(hostileConfig("notebook")).generateNotebook()
+		if err != nil {
+			t.Fatal(err)
+		}
+		program := `import json, sys
+cells = json.load(sys.stdin)["cells"]
+try:
+    from IPython.core.inputtransformer2 import TransformerManager
+except ImportError:
+    TransformerManager = None
+for cell in cells:
+    if cell["cell_type"] == "code" and TransformerManager:
+        compile(TransformerManager().transform_cell("".join(cell["source"])), "cell", "exec")
+print(json.dumps("".join(cells[0]["source"])))`
+		out, err := runPython(t, program, notebook)
+		if err != nil {
+			t.Fatalf("notebook: %v", err)
+		}
+		var title string
+		if err := json.Unmarshal([]byte(out), &title); err != nil {
+			t.Fatalf("notebook: Python printed %s", out)
+		}
+		if want := strings.ToValidUTF8("# "+name+"\n\n"+description, "�"); !strings.HasPrefix(title, want) {
+			t.Errorf("notebook title cell = %q, want it to start with %q", title, want)
+		}
+	})
+}
@@ -0,0 +1,465 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the locale calculators are generated for unless another
+// one is configured
+const DefaultLocale = "en-US"
+
+// Locale is the language of a generated calculator's messages and the way it
+// reads and writes numbers
+type Locale struct {
+	Tag               string
+	DecimalSeparator  string
+	GroupingSeparator string // empty leaves results ungrouped
+	ArgumentSeparator string // separates function arguments; ";" where "," is the decimal separator
+	messages          map[string]string
+}
+
+// englishMessages is the base catalogue every locale falls back to. Messages
+// are inserted into double-quoted Python strings; placeholders such as {e}
+// are filled from the f-string's local names, so translations keep them.
+var englishMessages = map[string]string{
+	// Command line
+	"cli.started":           "Calculator started. Type 'help' for commands, 'quit' to exit.",
+	"cli.goodbye":           "Goodbye!",
+	"cli.result":            "Result:",
+	"cli.help.title":        "Available commands:",
+	"cli.help.operations":   "  Basic operations: +, -, *, /, **, %",
+	"cli.help.functions":    "  Functions: sin(), cos(), tan(), log(), ln(), sqrt()",
+	"cli.help.results":      "  Results: ans (last result), _1, _2, ... (numbered results)",
+	"cli.help.continue":     "           start with an operator to continue, e.g. '* 2' doubles the last result",
+	"cli.help.numbers":      "  Numbers: write {example}; separate function arguments with {separator}",
+	"cli.help.memory":       "  Memory: mem store [name] <value>, mem recall [name], mem clear [name]",
+	"cli.help.memory_more":  "          mem add/sub [name] <value> (M+/M-), mem list; registers work in expressions",
+	"cli.help.history":      "  History: hist show [n], hist search <text>, hist rerun <n>, hist save [file], hist clear",
+	"cli.help.history_more": "           hist export --format csv|md|json [file]",
+	"cli.help.editing":      "  Editing: Up/Down recall history, Tab completes names, Ctrl+R searches history",
+	"cli.help.other":        "  Other: help, clear, quit",
+	"cli.memory_usage":      "Memory commands: mem store [name] <value>, mem recall [name], mem clear [name], mem add [name] <value>, mem sub [name] <value>, mem list",
+	"cli.memory_error":      "Memory error: {e}",
+	"cli.no_value":          "No value given and no previous result",
+	"cli.history_usage":     "History commands: hist show [n], hist search <text>, hist rerun <n>, hist export --format csv|md|json [file], hist save [file], hist clear",
+	"cli.history_error":     "History error: {e}",
+	"cli.no_matches":        "No matching history entries",
+
+	// Evaluation
+	"eval.error":          "Error: {e}",
+	"eval.invalid":        "Invalid expression: {e}",
+	"eval.divide_by_zero": "Cannot divide by zero",
+	"eval.modulo_by_zero": "Cannot calculate modulo with zero",
+	"eval.log_domain":     "Logarithm input must be positive",
+	"eval.ln_domain":      "Natural logarithm input must be positive",
+	"eval.arguments":      "Separate function arguments with {separator}: {decimal} is the decimal separator",
+
+	// Memory and history
+	"memory.invalid_name":     "Invalid register name: {name}",
+	"memory.stored":           "Stored {value} in {name}",
+	"memory.empty_register":   "Register {name} is empty",
+	"memory.cleared":          "Memory cleared",
+	"memory.register_cleared": "Register {name} cleared",
+	"memory.empty":            "Memory is empty",
+	"history.no_entry":        "No history entry #{index}",
+	"history.cleared":         "History cleared",
+	"history.saved":           "History saved to {filename}",
+	"history.unknown_format":  "Unknown export format: {fmt} (use json, csv or md)",
+
+	// GUI
+	"gui.error":              "Error",
+	"gui.memory":             "Memory",
+	"gui.history":            "History",
+	"gui.tools":              "Tools",
+	"gui.view":               "View",
+	"gui.help":               "Help",
+	"gui.about":              "About",
+	"gui.close":              "Close",
+	"gui.store_register":     "Store in Register",
+	"gui.recall_register":    "Recall Register",
+	"gui.show_registers":     "Show Registers",
+	"gui.clear_all":          "Clear All",
+	"gui.register_name":      "Register name:",
+	"gui.statistics":         "Statistics",
+	"gui.statistics_menu":    "Statistics Calculator",
+	"gui.statistics_prompt":  "Enter comma-separated numbers:",
+	"gui.statistics_results": "Statistics Results",
+	"gui.invalid_data":       "Invalid data format: {e}",
+	"gui.show_history":       "Show History",
+	"gui.search_history":     "Search History",
+	"gui.export_history":     "Export History",
+	"gui.clear_history":      "Clear History",
+	"gui.history_title":      "Calculation History",
+	"gui.no_history":         "No calculations in history",
+	"gui.find_text":          "Find text:",
+	"gui.no_matches":         "No entries matching '{text}'",
+	"gui.history_matching":   "History matching '{text}'",
+	"gui.larger_text":        "Larger Text",
+	"gui.smaller_text":       "Smaller Text",
+	"gui.normal_text":        "Normal Text Size",
+	"gui.high_contrast":      "High Contrast",
+	"gui.high_contrast_on":   "High contrast on",
+	"gui.high_contrast_off":  "High contrast off",
+	"gui.text_size":          "Text size {percent}%",
+	"gui.shortcuts":          "Keyboard Shortcuts",
+	"gui.shortcut":           "Shortcut",
+	"gui.action":             "Action",
+	"gui.angle_unit":         "Angle unit: {unit}",
+	"gui.version":            "Version",
+	"gui.author":             "Author",
+}
+
+// locales returns every supported locale
+func locales() []Locale {
+	return []Locale{
+		{Tag: "en-US", DecimalSeparator: ".", ArgumentSeparator: ",", messages: englishMessages},
+		{Tag: "de-DE", DecimalSeparator: ",", GroupingSeparator: ".", ArgumentSeparator: ";", messages: germanMessages},
+		{Tag: "fr-FR", DecimalSeparator: ",", GroupingSeparator: " ", ArgumentSeparator: ";", messages: frenchMessages},
+	}
+}
+
+// LocaleNames lists the supported locale tags
+func LocaleNames() []string {
+	var names []string
+	for _, locale := range locales() {
+		names = append(names, locale.Tag)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupLocale finds a locale by tag. Tags are matched case-insensitively,
+// "_" may be used for "-", and a bare language such as "de" matches the
+// first locale for that language.
+func LookupLocale(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	for _, locale := range locales() {
+		if strings.ToLower(locale.Tag) == tag {
+			return locale, true
+		}
+	}
+	for _, locale := range locales() {
+		if language, _, _ := strings.Cut(strings.ToLower(locale.Tag), "-"); language == tag {
+			return locale, true
+		}
+	}
+	return Locale{}, false
+}
+
+// localeFor returns the configured locale, falling back to the default
+func localeFor(config CalculatorConfig) Locale {
+	if locale, ok := LookupLocale(config.UI.Locale); ok {
+		return locale
+	}
+	locale, _ := LookupLocale(DefaultLocale)
+	return locale
+}
+
+// T returns the message for key, falling back to English for messages the
+// locale does not translate
+func (l Locale) T(key string) string {
+	if message, ok := l.messages[key]; ok {
+		return message
+	}
+	if message, ok := englishMessages[key]; ok {
+		return message
+	}
+	// Every key the generators use is in the English catalogue
+	panic("no message for " + key)
+}
+
+// actionDescription returns the translated description of a keymap action
+func (l Locale) actionDescription(action keymapAction) string {
+	if description, ok := l.messages["action."+action.name]; ok {
+		return description
+	}
+	return action.description
+}
+
+// NumberExample writes 1234.56 the way the locale does
+func (l Locale) NumberExample() string {
+	return "1" + l.GroupingSeparator + "234" + l.DecimalSeparator + "56"
+}
+
+// generateLocaleFunctions creates the number format helpers: the locale's
+// separators, parse_localized to turn user input into Python notation,
+// explain_arguments to point out arguments split with the decimal separator
+// and format_localized to write results back in the locale's notation
+func generateLocaleFunctions(locale Locale) string {
+	arguments := strings.NewReplacer(
+		"{separator}", locale.ArgumentSeparator,
+		"{decimal}", locale.DecimalSeparator,
+	).Replace(locale.T("eval.arguments"))
+	return `# Number format for the ` + locale.Tag + ` locale
+DECIMAL_SEPARATOR = "` + locale.DecimalSeparator + `"
+GROUPING_SEPARATOR = "` + locale.GroupingSeparator + `"
+ARGUMENT_SEPARATOR = "` + locale.ArgumentSeparator + `"
+
+
+def parse_localized(expression):
+    """Rewrite numbers written in the locale's notation as Python numbers"""
+    if DECIMAL_SEPARATOR == "." and ARGUMENT_SEPARATOR == ",":
+        return expression
+    grouped = ""
+    if GROUPING_SEPARATOR:
+        grouped = r"\d{1,3}(?:" + re.escape(GROUPING_SEPARATOR) + r"\d{3})+|"
+    number = re.compile(r"(?<![\w.])(" + grouped + r"\d+)(?:" + re.escape(DECIMAL_SEPARATOR) + r"(\d+))?")
+
+    def to_python(match):
+        digits = match.group(1)
+        if GROUPING_SEPARATOR:
+            digits = digits.replace(GROUPING_SEPARATOR, "")
+        return digits + "." + match.group(2) if match.group(2) else digits
+
+    return number.sub(to_python, expression).replace(ARGUMENT_SEPARATOR, ",")
+
+
+def explain_arguments(expression, error):
+    """Explain a failed call such as max(1,2) whose arguments were split with
+    the decimal separator, which parse_localized reads as one number"""
+    if ARGUMENT_SEPARATOR == "," or not isinstance(error, TypeError):
+        return None
+    call = r"\w\([^()" + re.escape(ARGUMENT_SEPARATOR) + r"]*\d" + re.escape(DECIMAL_SEPARATOR) + r"\d"
+    if re.search(call, expression):
+        return ` + pythonString(arguments) + `
+    return None
+
+
+def format_localized(value):
+    """Write a number with the locale's decimal and grouping separators"""
+    if isinstance(value, bool) or not isinstance(value, (int, float)):
+        return str(value)
+    if DECIMAL_SEPARATOR == "." and not GROUPING_SEPARATOR:
+        return str(value)
+    text = f"{value:,}" if GROUPING_SEPARATOR else str(value)
+    return text.replace(",", "\0").replace(".", DECIMAL_SEPARATOR).replace("\0", GROUPING_SEPARATOR)`
+}
+
+// germanMessages translates the catalogue for de-DE
+var germanMessages = map[string]string{
+	"cli.started":           "Rechner gestartet. 'help' zeigt die Befehle, 'quit' beendet.",
+	"cli.goodbye":           "Auf Wiedersehen!",
+	"cli.result":            "Ergebnis:",
+	"cli.help.title":        "Verfügbare Befehle:",
+	"cli.help.operations":   "  Grundrechenarten: +, -, *, /, **, %",
+	"cli.help.functions":    "  Funktionen: sin(), cos(), tan(), log(), ln(), sqrt()",
+	"cli.help.results":      "  Ergebnisse: ans (letztes Ergebnis), _1, _2, ... (nummerierte Ergebnisse)",
+	"cli.help.continue":     "              mit einem Operator weiterrechnen, z. B. verdoppelt '* 2' das letzte Ergebnis",
+	"cli.help.numbers":      "  Zahlen: {example} schreiben; Funktionsargumente mit {separator} trennen",
+	"cli.help.memory":       "  Speicher: mem store [name] <wert>, mem recall [name], mem clear [name]",
+	"cli.help.memory_more":  "            mem add/sub [name] <wert> (M+/M-), mem list; Register sind in Ausdrücken nutzbar",
+	"cli.help.history":      "  Verlauf: hist show [n], hist search <text>, hist rerun <n>, hist save [datei], hist clear",
+	"cli.help.history_more": "           hist export --format csv|md|json [datei]",
+	"cli.help.editing":      "  Bearbeiten: Auf/Ab holt den Verlauf, Tab ergänzt Namen, Strg+R durchsucht den Verlauf",
+	"cli.help.other":        "  Sonstiges: help, clear, quit",
+	"cli.memory_usage":      "Speicherbefehle: mem store [name] <wert>, mem recall [name], mem clear [name], mem add [name] <wert>, mem sub [name] <wert>, mem list",
+	"cli.memory_error":      "Speicherfehler: {e}",
+	"cli.no_value":          "Kein Wert angegeben und kein vorheriges Ergebnis",
+	"cli.history_usage":     "Verlaufsbefehle: hist show [n], hist search <text>, hist rerun <n>, hist export --format csv|md|json [datei], hist save [datei], hist clear",
+	"cli.history_error":     "Verlaufsfehler: {e}",
+	"cli.no_matches":        "Keine passenden Verlaufseinträge",
+
+	"eval.error":          "Fehler: {e}",
+	"eval.invalid":        "Ungültiger Ausdruck: {e}",
+	"eval.divide_by_zero": "Division durch null ist nicht möglich",
+	"eval.modulo_by_zero": "Modulo mit null ist nicht möglich",
+	"eval.log_domain":     "Der Logarithmus braucht eine positive Zahl",
+	"eval.ln_domain":      "Der natürliche Logarithmus braucht eine positive Zahl",
+	"eval.arguments":      "Funktionsargumente mit {separator} trennen: {decimal} ist das Dezimaltrennzeichen",
+
+	"memory.invalid_name":     "Ungültiger Registername: {name}",
+	"memory.stored":           "{value} in {name} gespeichert",
+	"memory.empty_register":   "Register {name} ist leer",
+	"memory.cleared":          "Speicher gelöscht",
+	"memory.register_cleared": "Register {name} gelöscht",
+	"memory.empty":            "Der Speicher ist leer",
+	"history.no_entry":        "Kein Verlaufseintrag #{index}",
+	"history.cleared":         "Verlauf gelöscht",
+	"history.saved":           "Verlauf in {filename} gespeichert",
+	"history.unknown_format":  "Unbekanntes Exportformat: {fmt} (json, csv oder md verwenden)",
+
+	"gui.error":              "Fehler",
+	"gui.memory":             "Speicher",
+	"gui.history":            "Verlauf",
+	"gui.tools":              "Werkzeuge",
+	"gui.view":               "Ansicht",
+	"gui.help":               "Hilfe",
+	"gui.about":              "Über",
+	"gui.close":              "Schließen",
+	"gui.store_register":     "In Register speichern",
+	"gui.recall_register":    "Register abrufen",
+	"gui.show_registers":     "Register anzeigen",
+	"gui.clear_all":          "Alle löschen",
+	"gui.register_name":      "Registername:",
+	"gui.statistics":         "Statistik",
+	"gui.statistics_menu":    "Statistikrechner",
+	"gui.statistics_prompt":  "Zahlen durch Semikolon getrennt eingeben:",
+	"gui.statistics_results": "Statistikergebnisse",
+	"gui.invalid_data":       "Ungültiges Datenformat: {e}",
+	"gui.show_history":       "Verlauf anzeigen",
+	"gui.search_history":     "Verlauf durchsuchen",
+	"gui.export_history":     "Verlauf exportieren",
+	"gui.clear_history":      "Verlauf löschen",
+	"gui.history_title":      "Rechenverlauf",
+	"gui.no_history":         "Noch keine Rechnungen im Verlauf",
+	"gui.find_text":          "Suchtext:",
+	"gui.no_matches":         "Keine Einträge mit '{text}'",
+	"gui.history_matching":   "Verlauf mit '{text}'",
+	"gui.larger_text":        "Größere Schrift",
+	"gui.smaller_text":       "Kleinere Schrift",
+	"gui.normal_text":        "Normale Schriftgröße",
+	"gui.high_contrast":      "Hoher Kontrast",
+	"gui.high_contrast_on":   "Hoher Kontrast an",
+	"gui.high_contrast_off":  "Hoher Kontrast aus",
+	"gui.text_size":          "Schriftgröße {percent} %",
+	"gui.shortcuts":          "Tastenkürzel",
+	"gui.shortcut":           "Kürzel",
+	"gui.action":             "Aktion",
+	"gui.angle_unit":         "Winkeleinheit: {unit}",
+	"gui.version":            "Version",
+	"gui.author":             "Autor",
+
+	"action.calculate":            "Berechnen",
+	"action.clear":                "Alles löschen",
+	"action.clear_entry":          "Eingabe löschen",
+	"action.backspace":            "Zeichen vor dem Cursor löschen",
+	"action.delete_forward":       "Zeichen nach dem Cursor löschen",
+	"action.cursor_left":          "Cursor nach links",
+	"action.cursor_right":         "Cursor nach rechts",
+	"action.cursor_home":          "Cursor an den Anfang",
+	"action.cursor_end":           "Cursor an das Ende",
+	"action.undo":                 "Rückgängig",
+	"action.redo":                 "Wiederholen",
+	"action.insert_answer":        "Letztes Ergebnis einfügen",
+	"action.memory_store":         "Im Speicher ablegen",
+	"action.memory_recall":        "Speicher abrufen",
+	"action.memory_add":           "Zum Speicher addieren",
+	"action.memory_subtract":      "Vom Speicher subtrahieren",
+	"action.memory_clear":         "Speicher löschen",
+	"action.show_memory":          "Register anzeigen",
+	"action.toggle_angle_unit":    "Grad/Bogenmaß umschalten",
+	"action.show_history":         "Verlauf anzeigen",
+	"action.search_history":       "Verlauf durchsuchen",
+	"action.statistics":           "Statistikrechner",
+	"action.zoom_in":              "Größere Schrift",
+	"action.zoom_out":             "Kleinere Schrift",
+	"action.zoom_reset":           "Normale Schriftgröße",
+	"action.toggle_high_contrast": "Hohen Kontrast umschalten",
+	"action.shortcuts":            "Tastenkürzel",
+	"action.quit":                 "Beenden",
+}
+
+// frenchMessages translates the catalogue for fr-FR
+var frenchMessages = map[string]string{
+	"cli.started":           "Calculatrice démarrée. Tapez 'help' pour les commandes, 'quit' pour quitter.",
+	"cli.goodbye":           "Au revoir !",
+	"cli.result":            "Résultat :",
+	"cli.help.title":        "Commandes disponibles :",
+	"cli.help.operations":   "  Opérations de base : +, -, *, /, **, %",
+	"cli.help.functions":    "  Fonctions : sin(), cos(), tan(), log(), ln(), sqrt()",
+	"cli.help.results":      "  Résultats : ans (dernier résultat), _1, _2, ... (résultats numérotés)",
+	"cli.help.continue":     "              commencez par un opérateur pour continuer, p. ex. '* 2' double le dernier résultat",
+	"cli.help.numbers":      "  Nombres : écrivez {example} ; séparez les arguments des fonctions par {separator}",
+	"cli.help.memory":       "  Mémoire : mem store [nom] <valeur>, mem recall [nom], mem clear [nom]",
+	"cli.help.memory_more":  "            mem add/sub [nom] <valeur> (M+/M-), mem list ; les registres servent dans les expressions",
+	"cli.help.history":      "  Historique : hist show [n], hist search <texte>, hist rerun <n>, hist save [fichier], hist clear",
+	"cli.help.history_more": "               hist export --format csv|md|json [fichier]",
+	"cli.help.editing":      "  Édition : Haut/Bas rappelle l'historique, Tab complète les noms, Ctrl+R cherche dans l'historique",
+	"cli.help.other":        "  Autres : help, clear, quit",
+	"cli.memory_usage":      "Commandes mémoire : mem store [nom] <valeur>, mem recall [nom], mem clear [nom], mem add [nom] <valeur>, mem sub [nom] <valeur>, mem list",
+	"cli.memory_error":      "Erreur de mémoire : {e}",
+	"cli.no_value":          "Aucune valeur donnée et aucun résultat précédent",
+	"cli.history_usage":     "Commandes historique : hist show [n], hist search <texte>, hist rerun <n>, hist export --format csv|md|json [fichier], hist save [fichier], hist clear",
+	"cli.history_error":     "Erreur d'historique : {e}",
+	"cli.no_matches":        "Aucune entrée correspondante",
+
+	"eval.error":          "Erreur : {e}",
+	"eval.invalid":        "Expression invalide : {e}",
+	"eval.divide_by_zero": "Division par zéro impossible",
+	"eval.modulo_by_zero": "Modulo par zéro impossible",
+	"eval.log_domain":     "Le logarithme demande un nombre positif",
+	"eval.ln_domain":      "Le logarithme népérien demande un nombre positif",
+	"eval.arguments":      "Séparez les arguments des fonctions par {separator} : {decimal} est le séparateur décimal",
+
+	"memory.invalid_name":     "Nom de registre invalide : {name}",
+	"memory.stored":           "{value} enregistré dans {name}",
+	"memory.empty_register":   "Le registre {name} est vide",
+	"memory.cleared":          "Mémoire effacée",
+	"memory.register_cleared": "Registre {name} effacé",
+	"memory.empty":            "La mémoire est vide",
+	"history.no_entry":        "Aucune entrée #{index} dans l'historique",
+	"history.cleared":         "Historique effacé",
+	"history.saved":           "Historique enregistré dans {filename}",
+	"history.unknown_format":  "Format d'export inconnu : {fmt} (utilisez json, csv ou md)",
+
+	"gui.error":              "Erreur",
+	"gui.memory":             "Mémoire",
+	"gui.history":            "Historique",
+	"gui.tools":              "Outils",
+	"gui.view":               "Affichage",
+	"gui.help":               "Aide",
+	"gui.about":              "À propos",
+	"gui.close":              "Fermer",
+	"gui.store_register":     "Enregistrer dans un registre",
+	"gui.recall_register":    "Rappeler un registre",
+	"gui.show_registers":     "Afficher les registres",
+	"gui.clear_all":          "Tout effacer",
+	"gui.register_name":      "Nom du registre :",
+	"gui.statistics":         "Statistiques",
+	"gui.statistics_menu":    "Calculatrice statistique",
+	"gui.statistics_prompt":  "Saisissez des nombres séparés par des points-virgules :",
+	"gui.statistics_results": "Résultats statistiques",
+	"gui.invalid_data":       "Format de données invalide : {e}",
+	"gui.show_history":       "Afficher l'historique",
+	"gui.search_history":     "Rechercher dans l'historique",
+	"gui.export_history":     "Exporter l'historique",
+	"gui.clear_history":      "Effacer l'historique",
+	"gui.history_title":      "Historique des calculs",
+	"gui.no_history":         "Aucun calcul dans l'historique",
+	"gui.find_text":          "Texte à chercher :",
+	"gui.no_matches":         "Aucune entrée contenant '{text}'",
+	"gui.history_matching":   "Historique contenant '{text}'",
+	"gui.larger_text":        "Texte plus grand",
+	"gui.smaller_text":       "Texte plus petit",
+	"gui.normal_text":        "Taille de texte normale",
+	"gui.high_contrast":      "Contraste élevé",
+	"gui.high_contrast_on":   "Contraste élevé activé",
+	"gui.high_contrast_off":  "Contraste élevé désactivé",
+	"gui.text_size":          "Taille du texte {percent} %",
+	"gui.shortcuts":          "Raccourcis clavier",
+	"gui.shortcut":           "Raccourci",
+	"gui.action":             "Action",
+	"gui.angle_unit":         "Unité d'angle : {unit}",
+	"gui.version":            "Version",
+	"gui.author":             "Auteur",
+
+	"action.calculate":            "Calculer",
+	"action.clear":                "Tout effacer",
+	"action.clear_entry":          "Effacer la saisie",
+	"action.backspace":            "Supprimer avant le curseur",
+	"action.delete_forward":       "Supprimer après le curseur",
+	"action.cursor_left":          "Curseur à gauche",
+	"action.cursor_right":         "Curseur à droite",
+	"action.cursor_home":          "Curseur au début",
+	"action.cursor_end":           "Curseur à la fin",
+	"action.undo":                 "Annuler",
+	"action.redo":                 "Rétablir",
+	"action.insert_answer":        "Insérer le dernier résultat",
+	"action.memory_store":         "Mettre en mémoire",
+	"action.memory_recall":        "Rappeler la mémoire",
+	"action.memory_add":           "Ajouter à la mémoire",
+	"action.memory_subtract":      "Soustraire de la mémoire",
+	"action.memory_clear":         "Effacer la mémoire",
+	"action.show_memory":          "Afficher les registres",
+	"action.toggle_angle_unit":    "Basculer degrés/radians",
+	"action.show_history":         "Afficher l'historique",
+	"action.search_history":       "Rechercher dans l'historique",
+	"action.statistics":           "Calculatrice statistique",
+	"action.zoom_in":              "Texte plus grand",
+	"action.zoom_out":             "Texte plus petit",
+	"action.zoom_reset":           "Taille de texte normale",
+	"action.toggle_high_contrast": "Basculer le contraste élevé",
+	"action.shortcuts":            "Raccourcis clavier",
+	"action.quit":                 "Quitter",
+}
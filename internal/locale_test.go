@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// placeholders returns the sorted f-string placeholders of a message
+func placeholders(message string) []string {
+	names := regexp.MustCompile(`\{[a-z_]+\}`).FindAllString(message, -1)
+	sort.Strings(names)
+	return names
+}
+
+func TestLocaleCatalogues(t *testing.T) {
+	actions := make(map[string]bool)
+	for _, action := range keymapActions() {
+		actions["action."+action.name] = true
+	}
+
+	for _, locale := range locales() {
+		for key, message := range locale.messages {
+			english, ok := englishMessages[key]
+			if !ok && !actions[key] {
+				t.Errorf("%s: %q is not in the English catalogue", locale.Tag, key)
+			}
+			// Messages are inserted into double-quoted Python strings
+			if strings.ContainsAny(message, "\"\\") {
+				t.Errorf("%s: %q contains a double quote or backslash", locale.Tag, key)
+			}
+			if got, want := strings.Join(placeholders(message), " "), strings.Join(placeholders(english), " "); got != want {
+				t.Errorf("%s: %q has placeholders %q, want %q", locale.Tag, key, got, want)
+			}
+		}
+	}
+}
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+		ok   bool
+	}{
+		{"de-DE", "de-DE", true},
+		{"de_de", "de-DE", true},
+		{"fr", "fr-FR", true},
+		{"EN-us", "en-US", true},
+		{"pt-BR", "", false},
+	}
+
+	for _, tt := range tests {
+		locale, ok := LookupLocale(tt.tag)
+		if ok != tt.ok || locale.Tag != tt.want {
+			t.Errorf("LookupLocale(%q) = %q, %v; want %q, %v", tt.tag, locale.Tag, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGUIKeypadFollowsLocale(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Locale = "de-DE"
+	source := generateGUI(t, config)
+
+	for _, want := range []string{
+		`DECIMAL_SEPARATOR = ","`,
+		`ARGUMENT_SEPARATOR = ";"`,
+		"('.', ',', lambda: self.append_number('.'), 'Digit.TButton')",
+		"expression = parse_localized(expression).replace('^', '**').strip()",
+		`("Enter, =", "Berechnen")`,
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("generated GUI is missing %q", want)
+		}
+	}
+}
+
+func TestArgumentSeparatorError(t *testing.T) {
+	program := `import json, sys
+namespace = {"__name__": "calc"}
+exec(sys.stdin.read(), namespace)
+calculator = namespace["CalculatorCore"]()
+outcome = []
+for expression in ("max(1,2)", "max(1,5; 2)", "sqrt(2,25)", "max(1; 2,5)", "1/0"):
+    try:
+        outcome.append(str(calculator.evaluate_expression(expression)))
+    except ValueError as e:
+        outcome.append(str(e))
+print(json.dumps(outcome))`
+
+	config := GetDefaultConfig()
+	config.UI.Locale = "de-DE"
+	source, err := NewGenerator(config).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := runPython(t, program, []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := []string{
+		"Funktionsargumente mit ; trennen: , ist das Dezimaltrennzeichen",
+		"2",
+		"1.5",
+		"2.5",
+		"Division durch null ist nicht möglich",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("evaluations = %q, want %q", got, want)
+	}
+}
+
+func TestLocalizedRecords(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "api"
+	config.UI.Locale = "de-DE"
+	config.Features.History = true
+	source, err := NewAPIGenerator(config).GenerateAPIService()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// formatted follows the locale; value and history stay in Python notation
+	program := `import json, time
+import module
+
+Handler = module.CalculatorAPIHandler
+Handler.calculator = module.CalculatorCore()
+Handler.worker = module.EvaluationWorker()
+handler = Handler.__new__(Handler)
+api, status = handler.evaluate("1234,5 + 1", time.monotonic() + 5)
+Handler.worker.stop()
+core = module.CalculatorCore().evaluate_record("0,5 * 3")
+print(json.dumps({
+    "api": [api["value"], api["formatted"]],
+    "core": [core["value"], core["formatted"]],
+    "history": Handler.calculator.history.get_history()[-1]["result"],
+}))`
+	out, err := runPythonModule(t, source, program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := map[string]any{
+		"api":     []any{1235.5, "1.235,5"},
+		"core":    []any{1.5, "1,5"},
+		"history": "1235.5",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("records = %v, want %v", got, want)
+	}
+}
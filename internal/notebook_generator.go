@@ -0,0 +1,319 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NotebookGenerator handles the generation of IPython extensions that expose
+// the configured calculator inside Jupyter notebooks, together with a
+// companion notebook demonstrating them
+type NotebookGenerator struct {
+	config CalculatorConfig
+	core   *Generator
+}
+
+// NewNotebookGenerator creates a new notebook extension generator instance
+func NewNotebookGenerator(config CalculatorConfig) *NotebookGenerator {
+	return &NotebookGenerator{config: config, core: NewGenerator(config)}
+}
+
+// GenerateExtension creates the IPython extension module
+func (g *NotebookGenerator) GenerateExtension() (string, error) {
+	data := g.prepareNotebookTemplateData()
+	return g.renderNotebookTemplate(data)
+}
+
+// moduleName returns the name the extension is loaded under with %load_ext
+func (g *NotebookGenerator) moduleName() string {
+	return strings.TrimSuffix(filepath.Base(g.config.OutputFile), ".py")
+}
+
+// notebookPath returns the path of the companion notebook
+func (g *NotebookGenerator) notebookPath() string {
+	return strings.TrimSuffix(g.config.OutputFile, ".py") + ".ipynb"
+}
+
+// prepareNotebookTemplateData prepares data for extension template rendering
+func (g *NotebookGenerator) prepareNotebookTemplateData() TemplateData {
+	data := g.core.styleTemplateData([]string{
+		"import html",
+		"from IPython.core.error import UsageError",
+		"from IPython.core.magic import Magics, magics_class, line_magic, line_cell_magic",
+		"from IPython.core.magic_arguments import argument, magic_arguments, parse_argstring",
+		"from IPython.display import display",
+	}, g.generateExtensionContent())
+	data.Summary = "IPython extension: load it in a notebook with %load_ext and use %%calc cells."
+	data.Shebang = false
+	return data
+}
+
+// generateExtensionContent creates the magics class and extension hooks
+func (g *NotebookGenerator) generateExtensionContent() string {
+	var content strings.Builder
+
+	content.WriteString(`def show_rich(html_text, plain_text):
+    """Display HTML in notebooks and plain text in terminal IPython"""
+    display({"text/html": html_text, "text/plain": plain_text}, raw=True)
+
+
+def matrix_html(value, precision):
+    """Render a vector or matrix as an HTML table"""
+    rows = value.tolist()
+    if rows and not isinstance(rows[0], list):
+        rows = [rows]
+
+    def cell(item):
+        if isinstance(item, float):
+            item = round(item, precision)
+        return f"<td style=\"padding: 2px 8px; text-align: right\">{html.escape(str(item))}</td>"
+
+    body = "".join("<tr>" + "".join(cell(item) for item in row) + "</tr>" for row in rows)
+    return f"<table style=\"border-left: 2px solid; border-right: 2px solid\">{body}</table>"
+
+
+@magics_class
+class CalculatorMagics(Magics):
+    """IPython magics backed by CalculatorCore"""
+
+    def __init__(self, shell):
+        super().__init__(shell)
+        self.core = CalculatorCore()
+
+    def evaluate(self, expression):
+        """Evaluate an expression and remember its result"""
+        result = self.core.evaluate_expression(expression)
+        number = self.core.remember_result(result)`)
+
+	if g.config.Features.History {
+		content.WriteString(`
+        self.core.history.add_entry(expression, str(self.core.format_result(result)))`)
+	}
+
+	content.WriteString(`
+        return result, number
+
+    def show(self, expression, result, number):
+        """Display one result, using rich output for matrices, figures and symbolic values"""
+        label = f"<code>{html.escape(expression)}</code> <span style=\"color: #888\">[_{number}]</span>"
+        if result is None:
+            return
+        if getattr(result, "ndim", 0) in (1, 2):
+            show_rich(f"{label} =<br>{matrix_html(result, self.core.precision)}", f"{expression} = {result}")
+        elif any(hasattr(result, attr) for attr in ("_repr_html_", "_repr_latex_", "_ipython_display_")):
+            show_rich(f"{label} =", f"{expression} =")
+            display(result)
+        else:
+            formatted = str(self.core.format_result(result))
+            show_rich(f"{label} = <b>{html.escape(formatted)}</b>", f"{expression} = {formatted}   [_{number}]")
+
+    def apply_settings(self, args):
+        """Apply --precision and --angle options"""
+        if args.precision is not None:
+            if args.precision < 1 or args.precision > 20:
+                raise UsageError("precision must be between 1 and 20")
+            self.core.precision = args.precision
+        if args.angle:
+            self.core.angle_unit = args.angle
+
+    @magic_arguments()
+    @argument("--precision", "-p", type=int, help="Decimal places")
+    @argument("--angle", "-a", choices=["degrees", "radians"], help="Angle unit")
+    @line_cell_magic
+    def calc(self, line, cell=None):
+        """Evaluate calculator expressions.
+
+        %calc EXPRESSION returns the value of one expression.
+        %%calc [options] evaluates one expression per line of the cell;
+        options only apply to that cell.
+        """
+        if cell is None:
+            try:
+                return self.evaluate(line)[0]
+            except ValueError as e:
+                raise UsageError(str(e))
+
+        saved = self.core.precision, self.core.angle_unit
+        self.apply_settings(parse_argstring(self.calc, line))
+        try:
+            for expression in cell.splitlines():
+                expression = expression.split("#", 1)[0].strip()
+                if not expression:
+                    continue
+                try:
+                    result, number = self.evaluate(expression)
+                except ValueError as e:
+                    show_rich(f"<code>{html.escape(expression)}</code>: <span style=\"color: #c0392b\">{html.escape(str(e))}</span>",
+                              f"{expression}: {e}")
+                    continue
+                self.show(expression, result, number)
+        finally:
+            self.core.precision, self.core.angle_unit = saved
+
+    @magic_arguments()
+    @argument("--precision", "-p", type=int, help="Decimal places")
+    @argument("--angle", "-a", choices=["degrees", "radians"], help="Angle unit")
+    @line_magic
+    def calc_config(self, line):
+        """Show or change the precision and angle unit for this session"""
+        self.apply_settings(parse_argstring(self.calc_config, line))
+        print(f"precision={self.core.precision} angle_unit={self.core.angle_unit}")
+`)
+
+	if g.config.Features.Memory {
+		content.WriteString(`
+    @line_magic
+    def calc_memory(self, line):
+        """Memory registers: %calc_memory [list | store [NAME] | recall [NAME] | clear [NAME]]"""
+        args = line.split()
+        command = args[0] if args else "list"
+        name = args[1] if len(args) > 1 else None
+        try:
+            if command == "list":
+                registers = self.core.memory.list()
+                if not registers:
+                    print("Memory is empty")
+                for register, value in registers:
+                    print(f"{register} = {self.core.format_result(value)}")
+            elif command == "store":
+                if not self.core.results:
+                    raise UsageError("No result to store yet")
+                print(self.core.memory.store(self.core.results[-1], name or Memory.DEFAULT_REGISTER))
+            elif command == "recall":
+                return self.core.memory.recall(name or Memory.DEFAULT_REGISTER)
+            elif command == "clear":
+                print(self.core.memory.clear(name))
+            else:
+                raise UsageError(f"Unknown memory command: {command}")
+        except ValueError as e:
+            raise UsageError(str(e))
+`)
+	}
+
+	if g.config.Features.History {
+		content.WriteString(`
+    @line_magic
+    def calc_history(self, line):
+        """Show recent calculations: %calc_history [COUNT]"""
+        count = int(line) if line.strip().isdigit() else None
+        for entry in self.core.history.get_history(count):
+            print(f"{entry['index']:>4}  {entry['expression']} = {entry['result']}")
+`)
+	}
+
+	content.WriteString(`
+
+def load_ipython_extension(ipython):
+    """Register the calculator magics (called by %load_ext)"""
+    ipython.register_magics(CalculatorMagics)
+
+
+if __name__ == "__main__":
+    print("Load this calculator in IPython or Jupyter with: %load_ext ` + g.moduleName() + `")`)
+
+	return content.String()
+}
+
+// notebookCells returns the companion notebook cells as (type, source) pairs
+func (g *NotebookGenerator) notebookCells() [][2]string {
+	features := g.config.Features
+	libraries := g.config.Libraries
+
+	cells := [][2]string{
+		{"markdown", "# " + g.config.ProjectName + "\n\n" + g.config.Description + "\n\n" +
+			"Evaluate one expression per line with `%%calc`, or use `%calc` to get a value back. " +
+			"`ans` is the latest result and `_1`, `_2`, ... refer to numbered results."},
+		{"code", "%load_ext " + g.moduleName()},
+		{"code", "%%calc\n2 + 3 * 4\nans / 2\n_1 ^ 2"},
+		{"code", "value = %calc 2 ** 10\nvalue"},
+		{"code", "%calc_config --precision 4"},
+	}
+
+	if libraries.UseMath && features.Trigonometric {
+		cells = append(cells, [2]string{"code", "%%calc --angle radians\nsin(pi / 2)\ncos(pi)"})
+	}
+
+	if libraries.UseMath && features.Logarithmic {
+		cells = append(cells, [2]string{"code", "%%calc\nlog10(1000)\nln(e)"})
+	}
+
+	if libraries.UseNumpy && features.LinearAlgebra {
+		cells = append(cells, [2]string{"code", "%%calc\narray([[1, 2], [3, 4]])\nmatrix_inverse(ans)\nmatrix_determinant(_1)"})
+	}
+
+	if libraries.UseNumpy && features.Plotting && libraries.UsePlotly {
+		cells = append(cells, [2]string{"code", "%calc plot_function(\"x**2 - 3*x\", (-5, 5))"})
+	}
+
+	if features.Calculus && libraries.UseScipy {
+		cells = append(cells, [2]string{"code", "%%calc\nintegral(lambda x: x**2, 0, 3)\nfind_root(lambda x: x**2 - 2, 0, 2)"})
+	}
+
+	if features.EquationSolver && libraries.UseSympy {
+		cells = append(cells, [2]string{"code", "%%calc\nsolve_equation(\"x**2 - 4\")\ndifferentiate(\"x**3\")"})
+	}
+
+	if features.Memory {
+		cells = append(cells, [2]string{"code", "%calc_memory store\n%calc_memory"})
+	}
+
+	if features.History {
+		cells = append(cells, [2]string{"code", "%calc_history 5"})
+	}
+
+	return cells
+}
+
+// generateNotebook builds the companion notebook document in nbformat 4
+func (g *NotebookGenerator) generateNotebook() ([]byte, error) {
+	var cells []map[string]interface{}
+	for _, c := range g.notebookCells() {
+		lines := strings.SplitAfter(c[1], "\n")
+		cell := map[string]interface{}{
+			"cell_type": c[0],
+			"metadata":  map[string]interface{}{},
+			"source":    lines,
+		}
+		if c[0] == "code" {
+			cell["execution_count"] = nil
+			cell["outputs"] = []interface{}{}
+		}
+		cells = append(cells, cell)
+	}
+
+	notebook := map[string]interface{}{
+		"cells": cells,
+		"metadata": map[string]interface{}{
+			"kernelspec": map[string]string{
+				"display_name": "Python 3",
+				"language":     "python",
+				"name":         "python3",
+			},
+			"language_info": map[string]string{"name": "python"},
+		},
+		"nbformat":       4,
+		"nbformat_minor": 4,
+	}
+
+	data, err := json.MarshalIndent(notebook, "", " ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// WriteNotebook writes the companion notebook next to the extension module
+func (g *NotebookGenerator) WriteNotebook() error {
+	data, err := g.generateNotebook()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(g.notebookPath(), data, 0644)
+}
+
+// renderNotebookTemplate renders the extension module template with the given data
+func (g *NotebookGenerator) renderNotebookTemplate(data TemplateData) (string, error) {
+	return renderScript("notebook_extension", data)
+}
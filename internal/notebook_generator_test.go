@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNotebookCells(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "notebook"
+	config.OutputFile = "lab_calc.py"
+	config.Features.Trigonometric = true
+	config.Features.Memory = true
+
+	data, err := NewNotebookGenerator(config).generateNotebook()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notebook struct {
+		Cells []struct {
+			CellType       string   `json:"cell_type"`
+			Source         []string `json:"source"`
+			ExecutionCount *int     `json:"execution_count"`
+			Outputs        []any    `json:"outputs"`
+		} `json:"cells"`
+		NBFormat      int `json:"nbformat"`
+		NBFormatMinor int `json:"nbformat_minor"`
+	}
+	if err := json.Unmarshal(data, &notebook); err != nil {
+		t.Fatalf("notebook is not JSON: %v", err)
+	}
+	if notebook.NBFormat != 4 {
+		t.Errorf("nbformat = %d, want 4", notebook.NBFormat)
+	}
+
+	var code []string
+	for _, cell := range notebook.Cells {
+		if cell.CellType != "code" {
+			continue
+		}
+		if cell.Outputs == nil || cell.ExecutionCount != nil {
+			t.Errorf("code cell %q is not an unexecuted nbformat cell", cell.Source)
+		}
+		code = append(code, strings.Join(cell.Source, ""))
+	}
+	want := []string{
+		"%load_ext lab_calc",
+		"%%calc\n2 + 3 * 4\nans / 2\n_1 ^ 2",
+		"value = %calc 2 ** 10\nvalue",
+		"%calc_config --precision 4",
+		"%%calc --angle radians\nsin(pi / 2)\ncos(pi)",
+		"%calc_memory store\n%calc_memory",
+	}
+	if !reflect.DeepEqual(code, want) {
+		t.Errorf("code cells = %q\nwant %q", code, want)
+	}
+}
+
+func TestNotebookMagics(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "notebook"
+	config.OutputFile = "module.py"
+	config.Features.Trigonometric = true
+	config.Features.Memory = true
+	config.Features.History = true
+	generator := NewNotebookGenerator(config)
+	source, err := generator.GenerateExtension()
+	if err != nil {
+		t.Fatal(err)
+	}
+	notebook, err := generator.generateNotebook()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The companion notebook runs top to bottom in an IPython shell; further
+	// cells cover per-cell options, errors and matrices. Matrices come from
+	// numpy, so a stand-in with the same shape is used.
+	program := `import contextlib, io, json, sys
+try:
+    from IPython.core.interactiveshell import InteractiveShell
+except ImportError:
+    sys.exit(print("no IPython"))
+cells = ["".join(cell["source"]) for cell in json.loads(` + pythonString(string(notebook)) + `)["cells"] if cell["cell_type"] == "code"]
+cells += [
+    "%%calc --precision 2\n1/3  # comment\n1/0\n\n2/3",
+    "%calc 1/3",
+    "%calc_config --precision 30",
+    "%calc_memory recall nope",
+    """import module
+class Matrix:
+    ndim = 2
+    def tolist(self):
+        return [[1, 0.123456], [3, 4]]
+print(module.matrix_html(Matrix(), 4))""",
+    "%calc_history 2",
+]
+shell = InteractiveShell.instance()
+outputs = []
+for cell in cells:
+    stdout, stderr = io.StringIO(), io.StringIO()
+    with contextlib.redirect_stdout(stdout), contextlib.redirect_stderr(stderr):
+        result = shell.run_cell(cell, store_history=True)
+    outputs.append({"out": stdout.getvalue(), "error": "UsageError" in stderr.getvalue(), "result": repr(result.result)})
+print(json.dumps(outputs))`
+	out, err := runPythonModule(t, source, program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out == "no IPython\n" {
+		t.Skip("python3 has no IPython")
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := []map[string]any{
+		{"out": "", "error": false, "result": "None"},
+		{"out": "2 + 3 * 4 = 14   [_1]\nans / 2 = 7.0   [_2]\n_1 ^ 2 = 196   [_3]\n", "error": false, "result": "None"},
+		{"out": "Out[3]: 1024\n", "error": false, "result": "1024"},
+		{"out": "precision=4 angle_unit=degrees\n", "error": false, "result": "None"},
+		{"out": "sin(pi / 2) = 1.0   [_5]\ncos(pi) = -1.0   [_6]\n", "error": false, "result": "None"},
+		{"out": "Stored -1.0 in M\nM = -1.0\n", "error": false, "result": "None"},
+		{"out": "   2  ans / 2 = 7.0\n   3  _1 ^ 2 = 196\n   4  2 ** 10 = 1024\n   5  sin(pi / 2) = 1.0\n   6  cos(pi) = -1.0\n", "error": false, "result": "None"},
+		// Options apply to their cell only, and a bad line does not stop the rest
+		{"out": "1/3 = 0.33   [_7]\n1/0: Cannot divide by zero\n2/3 = 0.67   [_8]\n", "error": false, "result": "None"},
+		{"out": "Out[9]: 0.3333333333333333\n", "error": false, "result": "0.3333333333333333"},
+		{"out": "", "error": true, "result": "None"},
+		{"out": "", "error": true, "result": "None"},
+		{"out": `<table style="border-left: 2px solid; border-right: 2px solid">` +
+			`<tr><td style="padding: 2px 8px; text-align: right">1</td><td style="padding: 2px 8px; text-align: right">0.1235</td></tr>` +
+			`<tr><td style="padding: 2px 8px; text-align: right">3</td><td style="padding: 2px 8px; text-align: right">4</td></tr></table>` + "\n",
+			"error": false, "result": "None"},
+		{"out": "   8  2/3 = 0.67\n   9  1/3 = 0.3333\n", "error": false, "result": "None"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ran %d cells, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("cell %d gave %v, want %v", i, got[i], want[i])
+		}
+	}
+}
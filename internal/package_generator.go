@@ -0,0 +1,400 @@
+package internal
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PackageGenerator writes a calculator as an installable Python project: a
+// pyproject.toml with console scripts, a package split into core, feature
+// and interface modules, a README and a LICENSE
+type PackageGenerator struct {
+	config CalculatorConfig
+	locale Locale
+}
+
+// NewPackageGenerator creates a new package generator instance
+func NewPackageGenerator(config CalculatorConfig) *PackageGenerator {
+	return &PackageGenerator{config: config, locale: localeFor(config)}
+}
+
+// packageStyles are the styles the package layout can hold as ui modules
+var packageStyles = []string{"cli", "gui"}
+
+// licenses are the licenses a package can be released under
+var licenses = []string{"MIT", "BSD-3-Clause", "Apache-2.0", "none"}
+
+// LicenseNames lists the licenses a package layout can carry
+func LicenseNames() []string {
+	return append([]string(nil), licenses...)
+}
+
+// ProjectFile is a file of a generated project
+type ProjectFile struct {
+	Path    string // relative to the project directory, with forward slashes
+	Content string
+}
+
+// ProjectDir returns the directory a package layout is written to: the
+// output path, or a directory named after the project next to it when the
+// output is a .py file such as the default calculator.py
+func ProjectDir(config CalculatorConfig) string {
+	if strings.HasSuffix(config.OutputFile, ".py") {
+		return filepath.Join(filepath.Dir(config.OutputFile), distributionName(config))
+	}
+	return config.OutputFile
+}
+
+// ProjectCommand returns the command a package layout installs
+func ProjectCommand(config CalculatorConfig) string {
+	return distributionName(config)
+}
+
+// distributionName is the project's name on PyPI and its command
+func distributionName(config CalculatorConfig) string {
+	return strings.ReplaceAll(projectSlug(config.ProjectName), "_", "-")
+}
+
+// packageName is the name the project's package is imported by. A slug
+// that is not an identifier, such as 2nd_calc or class, gets a calc_ prefix.
+func packageName(config CalculatorConfig) string {
+	name := projectSlug(config.ProjectName)
+	if !isPythonIdentifier(name) {
+		return "calc_" + name
+	}
+	return name
+}
+
+// Files renders every file of the project
+func (g *PackageGenerator) Files() ([]ProjectFile, error) {
+	if err := validationError(ValidateConfig(g.config)); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	pkg := packageName(g.config)
+	files := []ProjectFile{
+		{"pyproject.toml", g.pyproject()},
+		{"README.md", g.readme()},
+	}
+	if license := g.license(); license != "" {
+		files = append(files, ProjectFile{"LICENSE", license})
+	}
+
+	// The CLI script's pieces, split into modules: core holds the shared
+	// helpers and the evaluation engine, the functions package the feature
+	// functions, and each ui module an interface built on core
+	cliConfig := g.config
+	cliConfig.UI.Style = "cli"
+	cli := NewGenerator(cliConfig)
+	imports := cli.generateImports()
+
+	var shared []string
+	var features []string
+	modules := make(map[string][]string)
+	for _, group := range cli.functionGroups() {
+		if group.feature == "" {
+			shared = append(shared, group.functions...)
+			continue
+		}
+		if modules[group.feature] == nil {
+			features = append(features, group.feature)
+		}
+		modules[group.feature] = append(modules[group.feature], group.functions...)
+	}
+
+	files = append(files,
+		ProjectFile{pkg + "/__init__.py", g.moduleHeader(g.config.Description) + "__version__ = \"1.0.0\"\n"},
+		ProjectFile{pkg + "/core.py", g.moduleHeader("Shared helpers and the evaluation engine") +
+			strings.Join(imports, "\n") + "\n\nfrom .functions import *\n" + pythonFunctions(shared) +
+			"\n\n" + cli.generateCoreContent()},
+	)
+
+	var functionsInit strings.Builder
+	functionsInit.WriteString(g.moduleHeader("Functions of the enabled features"))
+	for _, feature := range features {
+		functions := modules[feature]
+		module := g.moduleHeader(featureTitle(feature)+" functions") +
+			strings.Join(moduleImports(imports, strings.Join(functions, "\n")), "\n") + "\n" + pythonFunctions(functions)
+		functionsInit.WriteString("from ." + feature + " import *\n")
+		files = append(files, ProjectFile{pkg + "/functions/" + feature + ".py", module})
+	}
+	files = append(files, ProjectFile{pkg + "/functions/__init__.py", functionsInit.String()})
+
+	files = append(files,
+		ProjectFile{pkg + "/ui/__init__.py", g.moduleHeader("User interfaces")},
+		ProjectFile{pkg + "/ui/cli.py", g.moduleHeader("Command line interface") + `from ..core import *
+from ..functions import *
+
+` + cli.generateCLIContent() + `
+
+
+def main():
+    """Run the command line calculator"""
+    calculator = Calculator()
+    calculator.run()
+
+
+if __name__ == "__main__":
+    main()
+`},
+	)
+
+	if g.config.UI.Style == "gui" {
+		gui := NewGUIGenerator(g.config)
+		files = append(files, ProjectFile{pkg + "/ui/gui.py", g.moduleHeader("Desktop interface") + `from ..core import *
+from ..functions import *
+
+` + strings.Join(gui.generateGUIImports(), "\n") + "\n\n\n" + gui.generateGUIMainContent() + "\n"})
+	}
+
+	if constraints := NewGenerator(g.config).Constraints(); len(constraints) > 0 {
+		files = append(files, ProjectFile{"constraints.txt", strings.Join(constraints, "\n") + "\n"})
+	}
+
+	return files, nil
+}
+
+// WritePackage writes the project to its directory
+func (g *PackageGenerator) WritePackage() error {
+	files, err := g.Files()
+	if err != nil {
+		return err
+	}
+
+	dir := ProjectDir(g.config)
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(file.Content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moduleHeader is the docstring that opens each module of the package
+func (g *PackageGenerator) moduleHeader(summary string) string {
+	return `"""` + pythonDocstring(g.config.ProjectName) + `: ` + pythonDocstring(summary) + `
+
+Generated by Calculator Generator
+"""
+
+`
+}
+
+// pythonFunctions lays out top-level Python definitions, two blank lines
+// before each
+func pythonFunctions(functions []string) string {
+	var b strings.Builder
+	for _, function := range functions {
+		b.WriteString("\n\n" + function + "\n")
+	}
+	return b.String()
+}
+
+// moduleImports returns the imports whose names code uses, so a feature
+// module imports only what its functions need
+func moduleImports(imports []string, code string) []string {
+	var used []string
+	for _, statement := range imports {
+		for _, name := range importedNames(statement) {
+			if regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `[.(]`).MatchString(code) {
+				used = append(used, statement)
+				break
+			}
+		}
+	}
+	return used
+}
+
+// importedNames returns the names an import statement binds, such as np for
+// "import numpy as np" or solve and diff for "from sympy import solve, diff"
+func importedNames(statement string) []string {
+	var names []string
+	for _, line := range strings.Split(statement, "\n") {
+		line = strings.TrimSpace(line)
+		var bound string
+		switch {
+		case strings.HasPrefix(line, "from "):
+			_, bound, _ = strings.Cut(line, " import ")
+		case strings.HasPrefix(line, "import "):
+			bound = strings.TrimPrefix(line, "import ")
+		default:
+			continue
+		}
+		for _, part := range strings.Split(bound, ",") {
+			fields := strings.Fields(part)
+			if len(fields) == 0 {
+				continue
+			}
+			name := fields[len(fields)-1] // the alias after "as", if any
+			name, _, _ = strings.Cut(name, ".")
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// featureTitle turns a feature's configuration name into words
+func featureTitle(feature string) string {
+	title := strings.ReplaceAll(feature, "_", " ")
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// requiresPython is the oldest Python the project installs on
+func (g *PackageGenerator) requiresPython() pythonVersion {
+	if python, set := targetPython(g.config); set {
+		return python
+	}
+	if minimum, ok := stylePython[g.config.UI.Style]; ok && packagePython.less(minimum) {
+		return minimum
+	}
+	return packagePython
+}
+
+// pyproject renders pyproject.toml
+func (g *PackageGenerator) pyproject() string {
+	name := distributionName(g.config)
+	pkg := packageName(g.config)
+	summary := strings.Join(strings.Fields(g.config.Description), " ")
+
+	var b strings.Builder
+	b.WriteString(`[build-system]
+requires = ["setuptools>=61"]
+build-backend = "setuptools.build_meta"
+
+[project]
+`)
+	fmt.Fprintf(&b, "name = %s\n", tomlString(name))
+	b.WriteString("version = \"1.0.0\"\n")
+	fmt.Fprintf(&b, "description = %s\n", tomlString(summary))
+	b.WriteString("readme = \"README.md\"\n")
+	fmt.Fprintf(&b, "authors = [{ name = %s }]\n", tomlString(g.config.Author))
+	if g.config.License != "" && g.config.License != "none" {
+		fmt.Fprintf(&b, "license = { text = %s }\n", tomlString(g.config.License))
+	}
+	fmt.Fprintf(&b, "requires-python = \">=%s\"\n", g.requiresPython())
+
+	b.WriteString("dependencies = [")
+	var dependencies []string
+	for _, requirement := range NewGenerator(g.config).Requirements() {
+		if strings.HasPrefix(requirement, "#") {
+			continue
+		}
+		dependencies = append(dependencies, "\n    "+tomlString(requirement)+",")
+	}
+	if len(dependencies) > 0 {
+		b.WriteString(strings.Join(dependencies, "") + "\n")
+	}
+	b.WriteString("]\n")
+
+	fmt.Fprintf(&b, "\n[project.scripts]\n%s = \"%s.ui.cli:main\"\n", name, pkg)
+	if g.config.UI.Style == "gui" {
+		fmt.Fprintf(&b, "\n[project.gui-scripts]\n%s-gui = \"%s.ui.gui:main\"\n", name, pkg)
+	}
+
+	fmt.Fprintf(&b, "\n[tool.setuptools]\npackages = [\"%[1]s\", \"%[1]s.functions\", \"%[1]s.ui\"]\n", pkg)
+	return b.String()
+}
+
+// readme renders README.md
+func (g *PackageGenerator) readme() string {
+	name := distributionName(g.config)
+
+	var enabled []string
+	for _, feature := range featureFields(&g.config.Features) {
+		if *feature.field {
+			enabled = append(enabled, "- "+featureTitle(feature.name))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n%s\n\n", g.config.ProjectName, g.config.Description)
+	fmt.Fprintf(&b, "## Installation\n\n```bash\npip install .\n```\n\n")
+	fmt.Fprintf(&b, "## Usage\n\n```bash\n%s\n```\n\n", name)
+	if g.config.UI.Style == "gui" {
+		fmt.Fprintf(&b, "Start the desktop calculator with `%s-gui`.\n\n", name)
+	}
+	fmt.Fprintf(&b, "## Features\n\n%s\n", strings.Join(enabled, "\n"))
+	if g.config.License != "" && g.config.License != "none" {
+		fmt.Fprintf(&b, "\n## License\n\n%s, see [LICENSE](LICENSE).\n", g.config.License)
+	}
+	fmt.Fprintf(&b, "\nGenerated by Calculator Generator for %s.\n", g.config.Author)
+	return b.String()
+}
+
+//go:embed apache-2.0.txt
+var apacheLicense string
+
+// license renders the LICENSE file, or "" when the project has none
+func (g *PackageGenerator) license() string {
+	year := time.Now().Year()
+	holder := strings.Join(strings.Fields(g.config.Author), " ")
+
+	switch g.config.License {
+	case "MIT":
+		return fmt.Sprintf(`MIT License
+
+Copyright (c) %d %s
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+`, year, holder)
+	case "BSD-3-Clause":
+		return fmt.Sprintf(`BSD 3-Clause License
+
+Copyright (c) %d, %s
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+1. Redistributions of source code must retain the above copyright notice, this
+   list of conditions and the following disclaimer.
+
+2. Redistributions in binary form must reproduce the above copyright notice,
+   this list of conditions and the following disclaimer in the documentation
+   and/or other materials provided with the distribution.
+
+3. Neither the name of the copyright holder nor the names of its
+   contributors may be used to endorse or promote products derived from
+   this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+`, year, holder)
+	case "Apache-2.0":
+		// The full text, with the appendix's notice filled in
+		return strings.Replace(apacheLicense, "[yyyy] [name of copyright owner]", fmt.Sprintf("%d %s", year, holder), 1)
+	}
+	return ""
+}
@@ -0,0 +1,263 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPackageFiles(t *testing.T) {
+	config := GetScientificConfig()
+	config.ProjectName = "2nd Calc"
+	config.Description = "A \"quoted\"\ncalculator"
+	config.Author = `C:\Users\me`
+	config.UI.Style = "gui"
+	config.Layout = "package"
+	config.License = "BSD-3-Clause"
+
+	files, err := NewPackageGenerator(config).Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	contents := make(map[string]string)
+	for _, file := range files {
+		paths = append(paths, file.Path)
+		contents[file.Path] = file.Content
+	}
+	want := []string{
+		"pyproject.toml",
+		"README.md",
+		"LICENSE",
+		"calc_2nd_calc/__init__.py",
+		"calc_2nd_calc/core.py",
+		"calc_2nd_calc/functions/trigonometric.py",
+		"calc_2nd_calc/functions/logarithmic.py",
+		"calc_2nd_calc/functions/exponential.py",
+		"calc_2nd_calc/functions/statistical.py",
+		"calc_2nd_calc/functions/linear_algebra.py",
+		"calc_2nd_calc/functions/__init__.py",
+		"calc_2nd_calc/ui/__init__.py",
+		"calc_2nd_calc/ui/cli.py",
+		"calc_2nd_calc/ui/gui.py",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("files = %q, want %q", paths, want)
+	}
+
+	for _, script := range []string{
+		"2nd-calc = \"calc_2nd_calc.ui.cli:main\"",
+		"2nd-calc-gui = \"calc_2nd_calc.ui.gui:main\"",
+	} {
+		if !strings.Contains(contents["pyproject.toml"], script) {
+			t.Errorf("pyproject.toml has no %s", script)
+		}
+	}
+
+	// Every module compiles, and pyproject.toml parses when tomllib is there
+	source, err := json.Marshal(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	program := `import json, sys
+files = json.load(sys.stdin)
+for path, content in files.items():
+    if path.endswith(".py"):
+        compile(content, path, "exec")
+try:
+    import tomllib
+except ImportError:
+    sys.exit()
+project = tomllib.loads(files["pyproject.toml"])["project"]
+print(json.dumps([project["description"], project["authors"][0]["name"]]))`
+	out, err := runPython(t, program, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "" {
+		var values []string
+		if err := json.Unmarshal([]byte(out), &values); err != nil {
+			t.Fatalf("Python read back %s", out)
+		}
+		if want := []string{`A "quoted" calculator`, config.Author}; !reflect.DeepEqual(values, want) {
+			t.Errorf("pyproject.toml reads back as %q, want %q", values, want)
+		}
+	}
+}
+
+func TestProjectDir(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"calculator.py", "my-calc"},
+		{"out/calculator.py", "out/my-calc"},
+		{"projects/calc", "projects/calc"},
+	}
+
+	for _, tt := range tests {
+		config := GetDefaultConfig()
+		config.ProjectName = "My Calc"
+		config.OutputFile = tt.output
+		if got := ProjectDir(config); got != tt.want {
+			t.Errorf("ProjectDir(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestValidateLayout(t *testing.T) {
+	tests := []struct {
+		layout  string
+		style   string
+		license string
+		valid   bool
+	}{
+		{"script", "web", "MIT", true},
+		{"package", "cli", "none", true},
+		{"package", "gui", "Apache-2.0", true},
+		{"package", "web", "MIT", false},
+		{"package", "cli", "GPL", false},
+		{"zip", "cli", "MIT", false},
+	}
+
+	for _, tt := range tests {
+		config := GetDefaultConfig()
+		config.Layout = tt.layout
+		config.UI.Style = tt.style
+		config.License = tt.license
+		if err := NewGenerator(config).Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s layout with the %s style and %s license: Validate() = %v", tt.layout, tt.style, tt.license, err)
+		}
+	}
+}
+
+func TestPackageModulesShareCore(t *testing.T) {
+	config := GetDefaultConfig()
+	config.ProjectName = "Shared"
+	config.UI.Style = "gui"
+	config.UI.AngleUnit = "degrees"
+	config.Layout = "package"
+	config.Features.Trigonometric = true
+	config.Features.Memory = true
+
+	files, err := NewPackageGenerator(config).Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(file.Content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// The engine lives in core; the interfaces only build on it
+		if strings.HasPrefix(file.Path, "shared/ui/") {
+			for _, definition := range []string{"class CalculatorCore", "def parse_localized", "def safe_eval", "class Memory"} {
+				if strings.Contains(file.Content, definition) {
+					t.Errorf("%s defines %s", file.Path, definition)
+				}
+			}
+		}
+	}
+
+	// Both interfaces are CalculatorCore subclasses using the trigonometric
+	// module's functions
+	program := `import json, sys
+sys.path.insert(0, sys.argv[1])
+try:
+    import tkinter
+except ImportError:
+    sys.exit(print("no tkinter"))
+from shared.core import CalculatorCore
+from shared.ui.cli import Calculator
+from shared.ui.gui import CalculatorGUI
+outcome = {}
+for cls in (Calculator, CalculatorGUI):
+    calculator = cls.__new__(cls)
+    CalculatorCore.__init__(calculator)
+    outcome[cls.__name__] = [issubclass(cls, CalculatorCore), calculator.format_result(calculator.evaluate_expression("sin(30) * 2"))]
+print(json.dumps(outcome))`
+	cmd := exec.Command(python(t), "-c", program, dir)
+	cmd.Env = append(os.Environ(), "HOME="+t.TempDir())
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("importing the package failed: %v: %s", err, out)
+	}
+	if string(out) == "no tkinter\n" {
+		t.Skip("python3 has no tkinter")
+	}
+
+	var got map[string][]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := map[string][]any{"Calculator": {true, 1.0}, "CalculatorGUI": {true, 1.0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("outcome = %v, want %v", got, want)
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		project string
+		want    string
+	}{
+		{"My Calc", "my_calc"},
+		{"2nd Calc", "calc_2nd_calc"},
+		{"class", "calc_class"},
+		{"Lambda", "calc_lambda"},
+		{"match", "match"},
+		{"!!!", "calculator"},
+	}
+
+	for _, tt := range tests {
+		config := GetDefaultConfig()
+		config.ProjectName = tt.project
+		if got := packageName(config); got != tt.want {
+			t.Errorf("packageName(%q) = %q, want %q", tt.project, got, tt.want)
+		}
+	}
+}
+
+func TestPackageMetadata(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Author = "Ada"
+	config.Layout = "package"
+	config.License = "Apache-2.0"
+
+	files, err := NewPackageGenerator(config).Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := make(map[string]string)
+	for _, file := range files {
+		contents[file.Path] = file.Content
+	}
+
+	// setuptools>=61 does not install on Python 3.6
+	if !strings.Contains(contents["pyproject.toml"], `requires-python = ">=3.7"`) {
+		t.Errorf("pyproject.toml does not require Python 3.7:\n%s", contents["pyproject.toml"])
+	}
+
+	license := contents["LICENSE"]
+	copyright := fmt.Sprintf("Copyright %d Ada", time.Now().Year())
+	for _, want := range []string{"TERMS AND CONDITIONS FOR USE, REPRODUCTION, AND DISTRIBUTION", "END OF TERMS AND CONDITIONS", copyright} {
+		if !strings.Contains(license, want) {
+			t.Errorf("LICENSE is missing %q", want)
+		}
+	}
+	if strings.Contains(license, "[yyyy]") {
+		t.Error("LICENSE still has the appendix placeholder")
+	}
+}
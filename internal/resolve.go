@@ -0,0 +1,133 @@
+package internal
+
+// featureLibraries lists the libraries each feature's generated code needs,
+// by configuration name. It is the one place feature→library implications
+// are kept: the resolver, validation and the wizard all read it. Features
+// without generated code need nothing, see featureGeneratesCode.
+var featureLibraries = map[string][]string{
+	"trigonometric":   {"math"},
+	"logarithmic":     {"math"},
+	"exponential":     {"math"},
+	"statistical":     {"numpy"},
+	"linear_algebra":  {"numpy"},
+	"calculus":        {"scipy"},
+	"plotting":        {"plotly"},
+	"equation_solver": {"sympy"},
+	"data_analysis":   {"pandas"},
+}
+
+// placeholderFeatures can be selected but have no generated code yet
+var placeholderFeatures = map[string]bool{
+	"unit_conversion":   true,
+	"matrix_operations": true,
+	"graphing":          true,
+}
+
+// featureGeneratesCode reports whether an enabled feature changes the
+// generated calculator. Programming only adds the GUI's programmer keys.
+func featureGeneratesCode(config CalculatorConfig, feature string) bool {
+	if feature == "programming" {
+		return config.UI.Style == "gui"
+	}
+	return !placeholderFeatures[feature]
+}
+
+// standardLibrary is the library that is always available and that also
+// supplies sqrt, pi and e to expressions, so it is never unused
+const standardLibrary = "math"
+
+// namedFlag is a feature or library switch with its configuration name
+type namedFlag struct {
+	name  string
+	field *bool
+}
+
+// featureFields lists the features by configuration name, in field order
+func featureFields(f *Features) []namedFlag {
+	return []namedFlag{
+		{"basic_arithmetic", &f.BasicArithmetic},
+		{"history", &f.History},
+		{"memory", &f.Memory},
+		{"trigonometric", &f.Trigonometric},
+		{"logarithmic", &f.Logarithmic},
+		{"exponential", &f.Exponential},
+		{"statistical", &f.Statistical},
+		{"linear_algebra", &f.LinearAlgebra},
+		{"calculus", &f.Calculus},
+		{"plotting", &f.Plotting},
+		{"unit_conversion", &f.UnitConversion},
+		{"complex_numbers", &f.ComplexNumbers},
+		{"equation_solver", &f.EquationSolver},
+		{"matrix_operations", &f.MatrixOperations},
+		{"data_analysis", &f.DataAnalysis},
+		{"graphing", &f.Graphing},
+		{"programming", &f.Programming},
+	}
+}
+
+// libraryFields lists the libraries by the names --libraries takes
+func libraryFields(l *Libraries) []namedFlag {
+	return []namedFlag{
+		{"math", &l.UseMath},
+		{"numpy", &l.UseNumpy},
+		{"pandas", &l.UsePandas},
+		{"scipy", &l.UseScipy},
+		{"sympy", &l.UseSympy},
+		{"plotly", &l.UsePlotly},
+	}
+}
+
+// FeatureLibraries returns the libraries a feature needs, by the feature's
+// configuration name such as "data_analysis"
+func FeatureLibraries(feature string) []string {
+	return featureLibraries[feature]
+}
+
+// LibraryChoices records the libraries the user turned on (true) or off
+// (false) explicitly. Libraries missing from it are at their defaults.
+type LibraryChoices map[string]bool
+
+// LibraryDecision explains whether a library is part of a resolved
+// configuration and why
+type LibraryDecision struct {
+	Library    string   // name as given to --libraries
+	Enabled    bool     // part of the resolved configuration
+	Chosen     bool     // turned on or off explicitly
+	RequiredBy []string // enabled features that need it
+}
+
+// ResolveLibraries computes the smallest library set the configuration's
+// features need. Libraries a feature needs are turned on unless they were
+// explicitly turned off, which validation then reports as an error. Default
+// libraries no feature uses are turned off; explicitly chosen ones are kept
+// and validation warns about them. The standard library is left as it is.
+func ResolveLibraries(config CalculatorConfig, choices LibraryChoices) (CalculatorConfig, []LibraryDecision) {
+	requiredBy := make(map[string][]string)
+	for _, feature := range featureFields(&config.Features) {
+		if *feature.field {
+			for _, library := range featureLibraries[feature.name] {
+				requiredBy[library] = append(requiredBy[library], feature.name)
+			}
+		}
+	}
+
+	var decisions []LibraryDecision
+	for _, library := range libraryFields(&config.Libraries) {
+		choice, chosen := choices[library.name]
+		switch {
+		case chosen:
+			*library.field = choice
+		case len(requiredBy[library.name]) > 0:
+			*library.field = true
+		case library.name != standardLibrary:
+			*library.field = false
+		}
+		decisions = append(decisions, LibraryDecision{
+			Library:    library.name,
+			Enabled:    *library.field,
+			Chosen:     chosen,
+			RequiredBy: requiredBy[library.name],
+		})
+	}
+	return config, decisions
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveLibraries(t *testing.T) {
+	config := GetScientificConfig()
+	config.Features.Calculus = true
+	config.Features.Graphing = true
+	config.Libraries.UsePlotly = true
+
+	resolved, decisions := ResolveLibraries(config, LibraryChoices{"scipy": false, "plotly": true})
+
+	want := Libraries{UseMath: true, UseNumpy: true, UsePlotly: true}
+	if resolved.Libraries != want {
+		t.Errorf("libraries = %+v, want %+v", resolved.Libraries, want)
+	}
+
+	byName := make(map[string]LibraryDecision)
+	for _, decision := range decisions {
+		byName[decision.Library] = decision
+	}
+	if got := byName["numpy"].RequiredBy; !reflect.DeepEqual(got, []string{"statistical", "linear_algebra"}) {
+		t.Errorf("numpy required by %v", got)
+	}
+	if scipy := byName["scipy"]; scipy.Enabled || !scipy.Chosen || !reflect.DeepEqual(scipy.RequiredBy, []string{"calculus"}) {
+		t.Errorf("scipy decision = %+v, want turned off while calculus needs it", scipy)
+	}
+	// Graphing has no generated code, so nothing requires plotly for it
+	if plotly := byName["plotly"]; !plotly.Enabled || len(plotly.RequiredBy) != 0 {
+		t.Errorf("plotly decision = %+v, want kept only because it was chosen", plotly)
+	}
+	if sympy := byName["sympy"]; sympy.Enabled || sympy.Chosen {
+		t.Errorf("sympy decision = %+v, want dropped as unused", sympy)
+	}
+
+	// Validation reports the conflict, the feature without code and the
+	// unused choice
+	var fields []string
+	for _, issue := range ValidateConfig(resolved) {
+		fields = append(fields, string(issue.Severity)+" "+issue.Field)
+	}
+	if want := []string{"error features.calculus", "warning features.graphing", "warning libraries.use_plotly"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("issues = %v, want %v", fields, want)
+	}
+}
+
+func TestFeatureLibrariesHaveCode(t *testing.T) {
+	// A feature implies libraries only when it has code that uses them
+	for _, feature := range featureFields(&Features{}) {
+		config := GetDefaultConfig()
+		for _, flag := range featureFields(&config.Features) {
+			*flag.field = flag.name == feature.name
+		}
+		for _, library := range libraryFields(&config.Libraries) {
+			*library.field = true
+		}
+
+		var generated bool
+		for _, group := range NewGenerator(config).functionGroups() {
+			generated = generated || group.feature == feature.name
+		}
+		_, mapped := featureLibraries[feature.name]
+		if mapped && !generated {
+			t.Errorf("%s needs %v but generates no functions", feature.name, featureLibraries[feature.name])
+		}
+		if placeholderFeatures[feature.name] && generated {
+			t.Errorf("%s generates functions but is listed as a placeholder", feature.name)
+		}
+	}
+}
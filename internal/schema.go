@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ResultType identifies the kind of value produced by an evaluation
+type ResultType string
+
+const (
+	ResultInt      ResultType = "int"
+	ResultFloat    ResultType = "float"
+	ResultComplex  ResultType = "complex"
+	ResultMatrix   ResultType = "matrix"
+	ResultSymbolic ResultType = "symbolic"
+)
+
+// EvaluationRecord describes a single evaluation emitted by a generated
+// calculator running with the "json" output format. The Python emitters
+// derive their field names from this struct, so it is the one place the
+// schema is defined.
+type EvaluationRecord struct {
+	Input      string      `json:"input" doc:"Raw text entered by the user"`
+	Expression string      `json:"expression" doc:"Expression after normalization (for example ^ rewritten to **)"`
+	Value      interface{} `json:"value" doc:"Result value: a number, {real, imag} for complex results, nested arrays for matrices or a string for symbolic results"`
+	Type       ResultType  `json:"type" doc:"Kind of result, null when evaluation failed" enum:"int,float,complex,matrix,symbolic"`
+	Formatted  string      `json:"formatted" doc:"Result formatted with the configured precision, null when evaluation failed"`
+	Unit       string      `json:"unit" doc:"Unit attached to the result, null when the result is dimensionless"`
+	Error      string      `json:"error" doc:"Error message, null when evaluation succeeded"`
+	DurationMs float64     `json:"duration_ms" doc:"Evaluation time in milliseconds"`
+}
+
+// EvaluationRecordFields returns the JSON field names of EvaluationRecord in
+// declaration order
+func EvaluationRecordFields() []string {
+	var fields []string
+	t := reflect.TypeOf(EvaluationRecord{})
+	for i := 0; i < t.NumField(); i++ {
+		fields = append(fields, jsonFieldName(t.Field(i)))
+	}
+	return fields
+}
+
+// EvaluationRecordSchema returns a JSON Schema document for EvaluationRecord
+func EvaluationRecordSchema() map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	t := reflect.TypeOf(EvaluationRecord{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonFieldName(field)
+		required = append(required, name)
+
+		property := map[string]interface{}{
+			"description": field.Tag.Get("doc"),
+		}
+		switch field.Name {
+		case "Value":
+			// Any JSON value is allowed
+		case "Input", "Expression":
+			property["type"] = "string"
+		case "DurationMs":
+			property["type"] = "number"
+		default:
+			property["type"] = []string{"string", "null"}
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			var values []interface{}
+			for _, v := range strings.Split(enum, ",") {
+				values = append(values, v)
+			}
+			property["enum"] = append(values, nil)
+		}
+		properties[name] = property
+	}
+
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                "EvaluationRecord",
+		"description":          "A single calculator evaluation emitted by calculators generated with --output-format json",
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// jsonFieldName returns the name a struct field is encoded under in JSON
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
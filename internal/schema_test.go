@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvaluationRecordSchema(t *testing.T) {
+	schema := EvaluationRecordSchema()
+
+	fields := EvaluationRecordFields()
+	want := []string{"input", "expression", "value", "type", "formatted", "unit", "error", "duration_ms"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("EvaluationRecordFields() = %v, want %v", fields, want)
+	}
+	if required := schema["required"]; !reflect.DeepEqual(required, want) {
+		t.Errorf("required = %v, want %v", required, want)
+	}
+	if schema["additionalProperties"] != false {
+		t.Error("records must not allow additional properties")
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	if len(properties) != len(want) {
+		t.Errorf("schema has %d properties, want %d", len(properties), len(want))
+	}
+	for _, name := range want {
+		property, ok := properties[name].(map[string]interface{})
+		if !ok || property["description"] == "" {
+			t.Errorf("property %s is missing or undocumented", name)
+		}
+	}
+	typeEnum := properties["type"].(map[string]interface{})["enum"]
+	if wantEnum := []interface{}{"int", "float", "complex", "matrix", "symbolic", nil}; !reflect.DeepEqual(typeEnum, wantEnum) {
+		t.Errorf("type enum = %v, want %v", typeEnum, wantEnum)
+	}
+	if _, typed := properties["value"].(map[string]interface{})["type"]; typed {
+		t.Error("value must accept any JSON value")
+	}
+}
+
+func TestJSONRecordsFollowSchema(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Features.ComplexNumbers = true
+	config.UI.OutputFormat = "json"
+	source, err := NewGenerator(config).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(source, `RECORD_FIELDS = ("input", "expression", "value", "type", "formatted", "unit", "error", "duration_ms")`) {
+		t.Error("generated calculator does not take its record fields from the schema")
+	}
+
+	out := runScript(t, source, "7*6\n1/4\n2^3\n2+3j\n1/0\n")
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var record map[string]interface{}
+		decoder := json.NewDecoder(strings.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("stdout line is not a JSON record: %q", line)
+		}
+		var keys []string
+		for key := range record {
+			keys = append(keys, key)
+		}
+		if len(keys) != len(EvaluationRecordFields()) {
+			t.Errorf("record %q has fields %v, want %v", line, keys, EvaluationRecordFields())
+		}
+		if _, err := record["duration_ms"].(json.Number).Float64(); err != nil {
+			t.Errorf("duration_ms of %q is not a number", line)
+		}
+		delete(record, "duration_ms")
+		records = append(records, record)
+	}
+
+	want := []map[string]interface{}{
+		{"input": "7*6", "expression": "7*6", "value": json.Number("42"), "type": "int", "formatted": "42", "unit": nil, "error": nil},
+		{"input": "1/4", "expression": "1/4", "value": json.Number("0.25"), "type": "float", "formatted": "0.25", "unit": nil, "error": nil},
+		{"input": "2^3", "expression": "2**3", "value": json.Number("8"), "type": "int", "formatted": "8", "unit": nil, "error": nil},
+		{"input": "2+3j", "expression": "2+3j", "value": map[string]interface{}{"real": json.Number("2.0"), "imag": json.Number("3.0")},
+			"type": "complex", "formatted": "(2+3j)", "unit": nil, "error": nil},
+		{"input": "1/0", "expression": "1/0", "value": nil, "type": nil, "formatted": nil, "unit": nil, "error": "Cannot divide by zero"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %v\nwant %v", records, want)
+	}
+}
+
+func TestResultTypes(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.OutputFormat = "json"
+	source, err := NewGenerator(config).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Matrices and symbolic results come from numpy and sympy; stand-ins
+	// with the same shape classify the same way
+	program := `import json, sys
+namespace = {"__name__": "calc"}
+exec(sys.stdin.read(), namespace)
+class Array:
+    def __init__(self, items):
+        self.items = items
+    def tolist(self):
+        return self.items
+class Symbol:
+    def __str__(self):
+        return "x**2"
+values = [True, 3, 0.5, 1j, [[1, 2], [3, 4]], Array([1.5, 2]), Array(7), Symbol(), float("nan")]
+print(json.dumps([[namespace["result_type"](v), namespace["to_json_value"](v)] for v in values]))`
+	out, err := runPython(t, program, []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[["int", 1], ["int", 3], ["float", 0.5], ["complex", {"real": 0.0, "imag": 1.0}], ` +
+		`["matrix", [[1, 2], [3, 4]]], ["matrix", [1.5, 2]], ["int", 7], ["symbolic", "x**2"], ["float", null]]`
+	if strings.TrimSpace(out) != want {
+		t.Errorf("result types and values = %s\nwant %s", out, want)
+	}
+}
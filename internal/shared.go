@@ -0,0 +1,396 @@
+package internal
+
+import "fmt"
+
+// Python emitters shared by every calculator style, so the CLI and GUI
+// builds behave identically.
+
+// generateMemoryClass creates the named-register memory used by all styles,
+// with its messages in the locale's language
+func generateMemoryClass(locale Locale) string {
+	return `class Memory:
+    """Named memory registers, optionally persisted to a JSON file"""
+    DEFAULT_REGISTER = "M"
+
+    def __init__(self, filename=None):
+        self.registers = {}
+        self.filename = filename
+        self.load()
+
+    @staticmethod
+    def is_valid_name(name):
+        """Register names must be identifiers that do not start with _"""
+        return name.isidentifier() and not name.startswith("_")
+
+    def load(self):
+        """Restore registers from the memory file"""
+        if not self.filename or not os.path.exists(self.filename):
+            return
+        try:
+            with open(self.filename) as f:
+                data = json.load(f)
+            # Whole numbers come back as they were stored, anything else as a float
+            self.registers = {
+                name: value if type(value) is int else float(value)
+                for name, value in data.items() if self.is_valid_name(name)
+            }
+        except (OSError, ValueError, TypeError, AttributeError):
+            self.registers = {}
+
+    def save(self):
+        """Persist registers to the memory file"""
+        if not self.filename:
+            return
+        try:
+            with open(self.filename, 'w') as f:
+                json.dump(self.registers, f, indent=2)
+        except OSError:
+            pass
+
+    def check_name(self, name):
+        """Validate a register name"""
+        if not self.is_valid_name(name):
+            raise ValueError(f"` + locale.T("memory.invalid_name") + `")
+        return name
+
+    def store(self, value, name=DEFAULT_REGISTER):
+        """Store value in a register (MS)"""
+        self.registers[self.check_name(name)] = value
+        self.save()
+        value = format_localized(value)
+        return f"` + locale.T("memory.stored") + `"
+
+    def recall(self, name=DEFAULT_REGISTER):
+        """Recall value from a register (MR)"""
+        if name not in self.registers and name != self.DEFAULT_REGISTER:
+            raise ValueError(f"` + locale.T("memory.empty_register") + `")
+        return self.registers.get(name, 0)
+
+    def clear(self, name=None):
+        """Clear one register, or all registers when no name is given (MC)"""
+        if name is None:
+            self.registers = {}
+            self.save()
+            return "` + locale.T("memory.cleared") + `"
+        self.registers.pop(name, None)
+        self.save()
+        return f"` + locale.T("memory.register_cleared") + `"
+
+    def add(self, value, name=DEFAULT_REGISTER):
+        """Add value to a register (M+)"""
+        self.registers[self.check_name(name)] = self.registers.get(name, 0) + value
+        self.save()
+        return f"{name} = {format_localized(self.registers[name])}"
+
+    def subtract(self, value, name=DEFAULT_REGISTER):
+        """Subtract value from a register (M-)"""
+        self.registers[self.check_name(name)] = self.registers.get(name, 0) - value
+        self.save()
+        return f"{name} = {format_localized(self.registers[name])}"
+
+    def list(self):
+        """List all registers sorted by name"""
+        return sorted(self.registers.items())`
+}
+
+// memoryConstructor returns the Python expression that creates the memory
+// object, wiring in the persistence file when enabled
+func memoryConstructor(config CalculatorConfig) string {
+	if !config.Storage.PersistMemory {
+		return "Memory()"
+	}
+	return `Memory(os.path.expanduser(` + pythonString(memoryFile(config)) + `))`
+}
+
+// memoryFile returns the configured memory file or a per-project default
+func memoryFile(config CalculatorConfig) string {
+	if config.Storage.MemoryFile != "" {
+		return config.Storage.MemoryFile
+	}
+	return "~/." + projectSlug(config.ProjectName) + "_memory.json"
+}
+
+// generateHistoryClass creates the history store used by all styles. Every
+// interface writes the same entry schema: index, ISO timestamp, expression
+// and formatted result. Messages are in the locale's language.
+func generateHistoryClass(locale Locale) string {
+	return `class History:
+    """Calculation history shared by every calculator interface"""
+    EXPORT_FORMATS = ("json", "csv", "md")
+
+    def __init__(self, filename=None, max_entries=100):
+        self.entries = []
+        self.filename = filename
+        self.max_entries = max_entries
+        self.load()
+
+    def load(self):
+        """Restore history from the history file"""
+        if not self.filename or not os.path.exists(self.filename):
+            return
+        try:
+            with open(self.filename) as f:
+                data = json.load(f)
+            self.entries = [
+                {
+                    "index": int(entry.get("index", number)),
+                    "timestamp": str(entry.get("timestamp", "")),
+                    "expression": str(entry.get("expression", entry.get("operation", ""))),
+                    "result": str(entry.get("result", "")),
+                }
+                for number, entry in enumerate(data, start=1)
+            ]
+            self.trim()
+        except (OSError, ValueError, TypeError, AttributeError):
+            self.entries = []
+
+    def save(self):
+        """Persist history to the history file"""
+        if not self.filename:
+            return
+        try:
+            with open(self.filename, 'w') as f:
+                json.dump(self.entries, f, indent=2)
+        except OSError:
+            pass
+
+    def trim(self):
+        """Keep only the most recent max_entries entries"""
+        if len(self.entries) > self.max_entries:
+            self.entries = self.entries[-self.max_entries:]
+
+    def add_entry(self, expression, result):
+        """Add calculation to history"""
+        index = self.entries[-1]["index"] + 1 if self.entries else 1
+        self.entries.append({
+            "index": index,
+            "timestamp": datetime.now().isoformat(timespec="seconds"),
+            "expression": expression,
+            "result": str(result),
+        })
+        self.trim()
+        self.save()
+
+    def get_history(self, count=None):
+        """Get recent history entries"""
+        if count is None:
+            return list(self.entries)
+        return self.entries[-count:] if count > 0 else []
+
+    def get_entry(self, index):
+        """Find an entry by its index"""
+        for entry in self.entries:
+            if entry["index"] == index:
+                return entry
+        raise ValueError(f"` + locale.T("history.no_entry") + `")
+
+    def search(self, text):
+        """Find entries whose expression or result contains text"""
+        text = text.lower()
+        return [
+            entry for entry in self.entries
+            if text in entry["expression"].lower() or text in entry["result"].lower()
+        ]
+
+    def clear_history(self):
+        """Clear calculation history"""
+        self.entries = []
+        self.save()
+        return "` + locale.T("history.cleared") + `"
+
+    def export(self, fmt="json"):
+        """Render history as json, csv or md"""
+        if fmt == "json":
+            return json.dumps(self.entries, indent=2)
+        if fmt == "csv":
+            output = io.StringIO()
+            writer = csv.DictWriter(output, fieldnames=["index", "timestamp", "expression", "result"])
+            writer.writeheader()
+            writer.writerows(self.entries)
+            return output.getvalue()
+        if fmt == "md":
+            lines = ["| # | Timestamp | Expression | Result |", "|---|---|---|---|"]
+            for entry in self.entries:
+                cells = [str(entry["index"]), entry["timestamp"], entry["expression"], entry["result"]]
+                lines.append("| " + " | ".join(cell.replace("|", "\\|") for cell in cells) + " |")
+            return "\n".join(lines) + "\n"
+        raise ValueError(f"` + locale.T("history.unknown_format") + `")
+
+    def save_to_file(self, filename="calculator_history.json", fmt=None):
+        """Export history to a file, choosing the format from the extension"""
+        if fmt is None:
+            extension = os.path.splitext(filename)[1].lower().lstrip(".")
+            fmt = {"markdown": "md"}.get(extension, extension)
+            if fmt not in self.EXPORT_FORMATS:
+                fmt = "json"
+        content = self.export(fmt)
+        with open(filename, 'w', newline='') as f:
+            f.write(content)
+        return f"` + locale.T("history.saved") + `"`
+}
+
+// historyConstructor returns the Python expression that creates the history
+// object with the configured size and persistence file
+func historyConstructor(config CalculatorConfig) string {
+	maxEntries := fmt.Sprintf("max_entries=%d", historyMaxEntries(config))
+	if !config.Storage.PersistHistory {
+		return "History(" + maxEntries + ")"
+	}
+	return `History(os.path.expanduser(` + pythonString(historyFile(config)) + `), ` + maxEntries + `)`
+}
+
+// historyFile returns the configured history file or a per-project default
+func historyFile(config CalculatorConfig) string {
+	if config.Storage.HistoryFile != "" {
+		return config.Storage.HistoryFile
+	}
+	return "~/." + projectSlug(config.ProjectName) + "_history.json"
+}
+
+// historyMaxEntries returns the configured history size, defaulting to 100
+func historyMaxEntries(config CalculatorConfig) int {
+	if config.Storage.HistoryMaxEntries > 0 {
+		return config.Storage.HistoryMaxEntries
+	}
+	return 100
+}
+
+// generateRequestErrorClass creates the exception HTTP handlers raise to
+// answer a request with an error status
+func generateRequestErrorClass() string {
+	return `class RequestError(Exception):
+    """A request that must be answered with an HTTP error status"""
+
+    def __init__(self, status, message):
+        super().__init__(message)
+        self.status = status`
+}
+
+// generateJSONHandlerMethods creates the JSON request and response helpers
+// shared by the HTTP request handlers. Request bodies larger than the
+// handler's max_request_bytes are refused with 413.
+func generateJSONHandlerMethods() string {
+	return `
+    def send_json(self, payload, status=200):
+        """Send a JSON response"""
+        body = json.dumps(payload).encode("utf-8")
+        self.send_response(status)
+        self.send_header("Content-Type", "application/json")
+        self.send_header("Content-Length", str(len(body)))
+        self.end_headers()
+        self.wfile.write(body)
+
+    def read_json(self):
+        """Read a JSON object request body"""
+        try:
+            length = int(self.headers.get("Content-Length") or 0)
+        except ValueError:
+            raise RequestError(400, "Invalid Content-Length")
+        if length > self.max_request_bytes:
+            raise RequestError(413, f"Request body larger than {self.max_request_bytes} bytes")
+        if length <= 0:
+            return {}
+        try:
+            data = json.loads(self.rfile.read(length).decode("utf-8"))
+        except (UnicodeDecodeError, json.JSONDecodeError) as e:
+            raise RequestError(400, f"Invalid JSON: {e}")
+        if not isinstance(data, dict):
+            raise RequestError(400, "Request body must be a JSON object")
+        return data
+`
+}
+
+// generateWorkerHandlerMethods creates the helpers HTTP request handlers use
+// to evaluate in their EvaluationWorker. The handler's lock guards the
+// session and is held only while it is copied or updated, never while an
+// evaluation runs; evaluations lets one evaluation run at a time.
+func generateWorkerHandlerMethods() string {
+	return `
+    def wait_for_evaluations(self, deadline):
+        """Take the evaluations lock, waiting for earlier evaluations until the deadline"""
+        if not self.evaluations.acquire(timeout=max(deadline - time.monotonic(), 0)):
+            raise RequestError(504, f"Evaluation timed out after {self.eval_timeout} seconds")
+
+    def run_evaluation(self, user_input, deadline):
+        """Evaluate in the worker before the deadline; returns ("value", result),
+        ("error", message) or ("timeout", None). The caller holds evaluations."""
+        with self.lock:
+            try:
+                session = pickle.dumps(self.calculator)
+            except Exception as e:
+                return ("error", f"The session cannot be sent to the worker: {e}")
+        return self.worker.evaluate(session, user_input, deadline - time.monotonic())
+`
+}
+
+// generateEvaluationWorker creates the worker process HTTP styles evaluate
+// in, so an evaluation can be stopped at its deadline. Workers are started
+// with forkserver or spawn rather than fork: a fork of the threaded server
+// could copy a lock another thread holds. The session travels to the worker
+// pickled, and results come back to be remembered in the server.
+func generateEvaluationWorker() string {
+	return `# Evaluations run in a worker process that is killed when it misses the
+# deadline. Workers start from a clean process (forkserver, or spawn where
+# there is none), never as a fork of the threaded server.
+WORKER_CONTEXT = multiprocessing.get_context(
+    "forkserver" if "forkserver" in multiprocessing.get_all_start_methods() else "spawn"
+)
+
+
+def evaluation_worker(connection):
+    """Evaluate (pickled calculator, expression) requests until the connection closes"""
+    while True:
+        try:
+            session, user_input = connection.recv()
+        except (EOFError, OSError):
+            return
+        try:
+            outcome = ("value", pickle.loads(session).evaluate_expression(user_input))
+        except Exception as e:
+            outcome = ("error", str(e))
+        try:
+            connection.send(outcome)
+        except Exception as e:
+            connection.send(("error", f"The result cannot be returned: {e}"))
+
+
+class EvaluationWorker:
+    """A worker process evaluating one expression at a time; callers serialize
+    their evaluations. A worker that misses a deadline is replaced."""
+
+    def __init__(self):
+        self.process = None
+        self.connection = None
+
+    def start(self):
+        """Start a worker so the next evaluation does not wait for one"""
+        self.connection, child = WORKER_CONTEXT.Pipe()
+        self.process = WORKER_CONTEXT.Process(target=evaluation_worker, args=(child,), daemon=True)
+        self.process.start()
+        child.close()
+
+    def stop(self):
+        """Kill the worker"""
+        if self.process is not None:
+            self.process.kill()
+            self.process.join()
+            self.connection.close()
+            self.process = None
+
+    def evaluate(self, session, user_input, timeout):
+        """Evaluate with a pickled calculator; returns ("value", result),
+        ("error", message) or ("timeout", None)"""
+        if self.process is None or not self.process.is_alive():
+            self.stop()
+            self.start()
+        try:
+            self.connection.send((session, user_input))
+            if self.connection.poll(max(timeout, 0)):
+                return self.connection.recv()
+        except (EOFError, OSError) as e:
+            self.stop()
+            return ("error", f"Evaluation failed: {e}")
+        self.stop()
+        self.start()
+        return ("timeout", None)`
+}
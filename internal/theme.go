@@ -0,0 +1,301 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ThemeColor is the foreground/background pair of one interface element
+type ThemeColor struct {
+	Foreground string `json:"foreground" yaml:"foreground"`
+	Background string `json:"background" yaml:"background"`
+}
+
+// ThemeColors names the colours of every themed interface element
+type ThemeColors struct {
+	Window    ThemeColor `json:"window" yaml:"window"`
+	Display   ThemeColor `json:"display" yaml:"display"`
+	Digits    ThemeColor `json:"digits" yaml:"digits"`
+	Operators ThemeColor `json:"operators" yaml:"operators"`
+	Functions ThemeColor `json:"functions" yaml:"functions"`
+	Memory    ThemeColor `json:"memory" yaml:"memory"`
+	Error     ThemeColor `json:"error" yaml:"error"`
+}
+
+// ThemeFont describes a font by family, point size and weight
+type ThemeFont struct {
+	Family string `json:"family" yaml:"family"`
+	Size   int    `json:"size" yaml:"size"`
+	Bold   bool   `json:"bold" yaml:"bold"`
+}
+
+// ThemeFonts holds the display and button fonts
+type ThemeFonts struct {
+	Display ThemeFont `json:"display" yaml:"display"`
+	Buttons ThemeFont `json:"buttons" yaml:"buttons"`
+}
+
+// ThemePadding holds spacing in pixels around the window and inside buttons
+type ThemePadding struct {
+	Window int `json:"window" yaml:"window"`
+	Button int `json:"button" yaml:"button"`
+}
+
+// Theme describes the look of a generated calculator. Every style applies the
+// same theme: ANSI colours in the CLI, ttk styles in the GUI, CSS on the web
+// page and colour pairs in the terminal UI.
+type Theme struct {
+	Name    string       `json:"name" yaml:"name"`
+	Base    string       `json:"base,omitempty" yaml:"base"` // built-in theme a theme file starts from
+	Colors  ThemeColors  `json:"colors" yaml:"colors"`
+	Fonts   ThemeFonts   `json:"fonts" yaml:"fonts"`
+	Padding ThemePadding `json:"padding" yaml:"padding"`
+}
+
+// themeRole pairs an element name with its colours
+type themeRole struct {
+	name  string
+	color ThemeColor
+}
+
+// roles returns the themed elements in a fixed order
+func (t Theme) roles() []themeRole {
+	return []themeRole{
+		{"window", t.Colors.Window},
+		{"display", t.Colors.Display},
+		{"digits", t.Colors.Digits},
+		{"operators", t.Colors.Operators},
+		{"functions", t.Colors.Functions},
+		{"memory", t.Colors.Memory},
+		{"error", t.Colors.Error},
+	}
+}
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Validate checks that every colour is a hex colour and that fonts and
+// padding are usable
+func (t Theme) Validate() error {
+	for _, role := range t.roles() {
+		parts := [][2]string{{"foreground", role.color.Foreground}, {"background", role.color.Background}}
+		for _, part := range parts {
+			if !hexColorPattern.MatchString(part[1]) {
+				return ValidationError{
+					Field:   fmt.Sprintf("theme.colors.%s.%s", role.name, part[0]),
+					Message: fmt.Sprintf("%q is not a hex colour such as #1f6feb", part[1]),
+				}
+			}
+		}
+	}
+
+	fonts := []struct {
+		name string
+		font ThemeFont
+	}{{"display", t.Fonts.Display}, {"buttons", t.Fonts.Buttons}}
+	for _, f := range fonts {
+		if f.font.Family == "" {
+			return ValidationError{Field: "theme.fonts." + f.name + ".family", Message: "font family cannot be empty"}
+		}
+		if f.font.Size < 6 || f.font.Size > 96 {
+			return ValidationError{Field: "theme.fonts." + f.name + ".size", Message: "font size must be between 6 and 96"}
+		}
+	}
+
+	if t.Padding.Window < 0 || t.Padding.Button < 0 {
+		return ValidationError{Field: "theme.padding", Message: "padding cannot be negative"}
+	}
+
+	return nil
+}
+
+// ThemeNames lists the built-in themes
+func ThemeNames() []string {
+	return []string{"light", "dark", "colorful", "high-contrast"}
+}
+
+// BuiltinTheme returns the built-in theme with the given name
+func BuiltinTheme(name string) (Theme, bool) {
+	regular := ThemeFonts{
+		Display: ThemeFont{Family: "Helvetica", Size: 22, Bold: true},
+		Buttons: ThemeFont{Family: "Helvetica", Size: 12},
+	}
+
+	switch name {
+	case "light":
+		return Theme{
+			Name: "light",
+			Colors: ThemeColors{
+				Window:    ThemeColor{"#222222", "#f4f4f4"},
+				Display:   ThemeColor{"#111111", "#ffffff"},
+				Digits:    ThemeColor{"#222222", "#ffffff"},
+				Operators: ThemeColor{"#ffffff", "#1f6feb"},
+				Functions: ThemeColor{"#ffffff", "#6f42c1"},
+				Memory:    ThemeColor{"#ffffff", "#2e7d32"},
+				Error:     ThemeColor{"#d32f2f", "#fdecea"},
+			},
+			Fonts:   regular,
+			Padding: ThemePadding{Window: 10, Button: 5},
+		}, true
+	case "dark":
+		return Theme{
+			Name: "dark",
+			Colors: ThemeColors{
+				Window:    ThemeColor{"#f0f0f0", "#2b2b2b"},
+				Display:   ThemeColor{"#ffffff", "#1e1e1e"},
+				Digits:    ThemeColor{"#ffffff", "#404040"},
+				Operators: ThemeColor{"#ffffff", "#ff9500"},
+				Functions: ThemeColor{"#ffffff", "#5e5ce6"},
+				Memory:    ThemeColor{"#ffffff", "#30a14e"},
+				Error:     ThemeColor{"#ff6b6b", "#3a1e1e"},
+			},
+			Fonts:   regular,
+			Padding: ThemePadding{Window: 10, Button: 5},
+		}, true
+	case "colorful":
+		return Theme{
+			Name: "colorful",
+			Colors: ThemeColors{
+				Window:    ThemeColor{"#2d2d2d", "#fdf6e3"},
+				Display:   ThemeColor{"#073642", "#eee8d5"},
+				Digits:    ThemeColor{"#2d2d2d", "#ffd166"},
+				Operators: ThemeColor{"#ffffff", "#e4572e"},
+				Functions: ThemeColor{"#ffffff", "#17becf"},
+				Memory:    ThemeColor{"#ffffff", "#8e44ad"},
+				Error:     ThemeColor{"#c0392b", "#fde2e0"},
+			},
+			Fonts: ThemeFonts{
+				Display: ThemeFont{Family: "Helvetica", Size: 24, Bold: true},
+				Buttons: ThemeFont{Family: "Helvetica", Size: 12, Bold: true},
+			},
+			Padding: ThemePadding{Window: 12, Button: 6},
+		}, true
+	case "high-contrast":
+		return Theme{
+			Name: "high-contrast",
+			Colors: ThemeColors{
+				Window:    ThemeColor{"#ffffff", "#000000"},
+				Display:   ThemeColor{"#ffff00", "#000000"},
+				Digits:    ThemeColor{"#ffffff", "#000000"},
+				Operators: ThemeColor{"#000000", "#ffff00"},
+				Functions: ThemeColor{"#000000", "#00ffff"},
+				Memory:    ThemeColor{"#000000", "#00ff00"},
+				Error:     ThemeColor{"#ffffff", "#c00000"},
+			},
+			Fonts: ThemeFonts{
+				Display: ThemeFont{Family: "Helvetica", Size: 28, Bold: true},
+				Buttons: ThemeFont{Family: "Helvetica", Size: 14, Bold: true},
+			},
+			Padding: ThemePadding{Window: 12, Button: 8},
+		}, true
+	}
+	return Theme{}, false
+}
+
+// LoadThemeFile reads a YAML theme. Values not set in the file are taken
+// from the built-in theme named by its "base" key (light by default).
+func LoadThemeFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("reading theme file: %w", err)
+	}
+
+	var header struct {
+		Base string `yaml:"base"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return Theme{}, fmt.Errorf("parsing theme file %s: %w", path, err)
+	}
+	if header.Base == "" {
+		header.Base = "light"
+	}
+
+	theme, ok := BuiltinTheme(header.Base)
+	if !ok {
+		return Theme{}, fmt.Errorf("theme file %s: unknown base theme %q (choose from %s)", path, header.Base, strings.Join(ThemeNames(), ", "))
+	}
+	theme.Name = ""
+	if err := yaml.Unmarshal(data, &theme); err != nil {
+		return Theme{}, fmt.Errorf("parsing theme file %s: %w", path, err)
+	}
+	if theme.Name == "" {
+		theme.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	if err := theme.Validate(); err != nil {
+		return Theme{}, fmt.Errorf("theme file %s: %w", path, err)
+	}
+	return theme, nil
+}
+
+// ResolveTheme returns the custom theme if one was loaded, otherwise the
+// built-in theme named by Theme (light when unset)
+func (ui UIConfig) ResolveTheme() Theme {
+	if ui.CustomTheme != nil {
+		return *ui.CustomTheme
+	}
+	if theme, ok := BuiltinTheme(ui.Theme); ok {
+		return theme
+	}
+	theme, _ := BuiltinTheme("light")
+	return theme
+}
+
+// rgb splits a hex colour into its red, green and blue components
+func rgb(hex string) (r, g, b int) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	value, _ := strconv.ParseUint(hex, 16, 32)
+	return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff)
+}
+
+// ansiColor returns the SGR parameters selecting a 24-bit colour
+func ansiColor(hex string, background bool) string {
+	r, g, b := rgb(hex)
+	layer := 38
+	if background {
+		layer = 48
+	}
+	return fmt.Sprintf("%d;2;%d;%d;%d", layer, r, g, b)
+}
+
+// cursesColor returns the nearest of the eight standard curses colours
+func cursesColor(hex string) string {
+	names := []string{"COLOR_BLACK", "COLOR_RED", "COLOR_GREEN", "COLOR_YELLOW", "COLOR_BLUE", "COLOR_MAGENTA", "COLOR_CYAN", "COLOR_WHITE"}
+	r, g, b := rgb(hex)
+
+	best, bestDistance := 0, -1
+	for i := range names {
+		// Curses colour i has red in bit 0, green in bit 1 and blue in bit 2
+		cr, cg, cb := (i&1)*255, (i>>1&1)*255, (i>>2&1)*255
+		distance := (r-cr)*(r-cr) + (g-cg)*(g-cg) + (b-cb)*(b-cb)
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	return names[best]
+}
+
+// tkFont returns a Python Tk font tuple
+func tkFont(font ThemeFont) string {
+	if font.Bold {
+		return fmt.Sprintf("(%s, %d, \"bold\")", pythonString(font.Family), font.Size)
+	}
+	return fmt.Sprintf("(%s, %d)", pythonString(font.Family), font.Size)
+}
+
+// cssFont returns a CSS font shorthand
+func cssFont(font ThemeFont) string {
+	weight := "normal"
+	if font.Bold {
+		weight = "bold"
+	}
+	return fmt.Sprintf("%s %dpt %s, system-ui, sans-serif", weight, font.Size, cssString(font.Family))
+}
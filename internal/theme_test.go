@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinThemes(t *testing.T) {
+	for _, name := range ThemeNames() {
+		theme, ok := BuiltinTheme(name)
+		if !ok || theme.Name != name {
+			t.Errorf("BuiltinTheme(%q) = %q, %v", name, theme.Name, ok)
+			continue
+		}
+		if err := theme.Validate(); err != nil {
+			t.Errorf("built-in theme %s is invalid: %v", name, err)
+		}
+	}
+	if _, ok := BuiltinTheme("neon"); ok {
+		t.Error("BuiltinTheme(neon) found a theme")
+	}
+
+	// An unknown or unset theme name falls back to light
+	if got := (UIConfig{Theme: "neon"}).ResolveTheme().Name; got != "light" {
+		t.Errorf("ResolveTheme() for an unknown theme = %s, want light", got)
+	}
+	custom := Theme{Name: "mine"}
+	if got := (UIConfig{Theme: "dark", CustomTheme: &custom}).ResolveTheme().Name; got != "mine" {
+		t.Errorf("ResolveTheme() with a custom theme = %s, want mine", got)
+	}
+}
+
+func TestLoadThemeFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		check   func(t *testing.T, theme Theme)
+		wantErr string
+	}{
+		{
+			name:    "starts from light",
+			file:    "ocean.yaml",
+			content: "colors:\n  operators:\n    background: \"#006994\"\n",
+			check: func(t *testing.T, theme Theme) {
+				light, _ := BuiltinTheme("light")
+				if theme.Name != "ocean" {
+					t.Errorf("name = %q, want the file name", theme.Name)
+				}
+				if theme.Colors.Operators != (ThemeColor{light.Colors.Operators.Foreground, "#006994"}) {
+					t.Errorf("operators = %+v", theme.Colors.Operators)
+				}
+				if theme.Colors.Digits != light.Colors.Digits || theme.Fonts != light.Fonts {
+					t.Errorf("unset values do not come from light: %+v", theme)
+				}
+			},
+		},
+		{
+			name:    "named base",
+			file:    "night.yml",
+			content: "base: dark\nname: Night\nfonts:\n  display:\n    family: Menlo\n    size: 30\npadding:\n  button: 9\n",
+			check: func(t *testing.T, theme Theme) {
+				dark, _ := BuiltinTheme("dark")
+				if theme.Name != "Night" || theme.Colors != dark.Colors {
+					t.Errorf("theme = %+v, want dark colours named Night", theme)
+				}
+				if theme.Fonts.Display != (ThemeFont{Family: "Menlo", Size: 30, Bold: true}) || theme.Padding != (ThemePadding{Window: 10, Button: 9}) {
+					t.Errorf("fonts = %+v, padding = %+v", theme.Fonts, theme.Padding)
+				}
+			},
+		},
+		{name: "unknown base", content: "base: neon\n", wantErr: `unknown base theme "neon" (choose from light, dark, colorful, high-contrast)`},
+		{name: "bad colour", content: "colors:\n  memory:\n    foreground: green\n", wantErr: `theme.colors.memory.foreground: "green" is not a hex colour`},
+		{name: "tiny font", content: "fonts:\n  buttons:\n    size: 2\n", wantErr: "theme.fonts.buttons.size: font size must be between 6 and 96"},
+		{name: "negative padding", content: "padding:\n  window: -1\n", wantErr: "padding cannot be negative"},
+		{name: "not YAML", content: "colors: [\n", wantErr: "parsing theme file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := tt.file
+			if file == "" {
+				file = "theme.yaml"
+			}
+			path := filepath.Join(t.TempDir(), file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			theme, err := LoadThemeFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("LoadThemeFile() error = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, theme)
+		})
+	}
+
+	if _, err := LoadThemeFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "reading theme file") {
+		t.Errorf("LoadThemeFile() of a missing file error = %v", err)
+	}
+}
+
+func TestThemeConversions(t *testing.T) {
+	if got := ansiColor("#1f6feb", false); got != "38;2;31;111;235" {
+		t.Errorf("ansiColor() = %s", got)
+	}
+	if got := ansiColor("#fa0", true); got != "48;2;255;170;0" {
+		t.Errorf("ansiColor() of a short colour = %s", got)
+	}
+	if got := tkFont(ThemeFont{Family: "Helvetica", Size: 22, Bold: true}); got != `("Helvetica", 22, "bold")` {
+		t.Errorf("tkFont() = %s", got)
+	}
+	if got := cssFont(ThemeFont{Family: "Menlo", Size: 12}); got != `normal 12pt "Menlo", system-ui, sans-serif` {
+		t.Errorf("cssFont() = %s", got)
+	}
+}
+
+func TestThemeRendering(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Theme = "colorful"
+	cli, err := NewGenerator(config).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# ANSI colours from the \"colorful\" theme\nTHEME_ANSI = {\n",
+		"    \"display\": \"38;2;7;54;66;48;2;238;232;213\",\n",
+		"    \"operators\": \"38;2;228;87;46\",\n",
+		"    \"error\": \"38;2;192;57;43\",\n",
+	} {
+		if !strings.Contains(cli, want) {
+			t.Errorf("CLI is missing %q", want)
+		}
+	}
+
+	custom := config.UI.ResolveTheme()
+	custom.Name = "Mine"
+	custom.Colors.Digits = ThemeColor{"#010203", "#040506"}
+	custom.Padding = ThemePadding{Window: 3, Button: 4}
+	config.UI.CustomTheme = &custom
+	gui := generateGUI(t, config)
+	for _, want := range []string{
+		"from the \"Mine\" theme\nTHEME_COLORS = {\n",
+		"    \"digits\": (\"#010203\", \"#040506\"),\n",
+		"    \"display\": (\"Helvetica\", 24, \"bold\"),\n",
+		"THEME_PADDING = {\"window\": 3, \"button\": 4}\n",
+	} {
+		if !strings.Contains(gui, want) {
+			t.Errorf("GUI is missing %q", want)
+		}
+	}
+}
+
+func TestCLIPaintsOnlyTerminals(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Theme = "dark"
+	source, err := NewGenerator(config).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Piped output stays plain
+	if out := runScript(t, source, "1/0\n2+2\n"); strings.Contains(out, "\033[") {
+		t.Errorf("piped output has escape codes:\n%q", out)
+	}
+
+	program := `import json, sys
+namespace = {"__name__": "calc"}
+exec(sys.stdin.read(), namespace)
+namespace["USE_COLOR"] = True
+paint = namespace["paint"]
+print(json.dumps([paint("oops", "error"), paint("calc> ", "operators", prompt=True)]))`
+	out, err := runPython(t, program, []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `["\u001b[38;2;255;107;107moops\u001b[0m", "\u0001\u001b[38;2;255;149;0m\u0002calc> \u0001\u001b[0m\u0002"]`
+	if strings.TrimSpace(out) != want {
+		t.Errorf("paint() = %s\nwant %s", out, want)
+	}
+}
@@ -0,0 +1,421 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TUIGenerator handles the generation of full-screen curses calculators for
+// terminals without a graphical display (for example over SSH)
+type TUIGenerator struct {
+	config CalculatorConfig
+	core   *Generator
+}
+
+// NewTUIGenerator creates a new terminal UI calculator generator instance
+func NewTUIGenerator(config CalculatorConfig) *TUIGenerator {
+	return &TUIGenerator{config: config, core: NewGenerator(config)}
+}
+
+// GenerateTUICalculator creates a curses-based terminal calculator
+func (g *TUIGenerator) GenerateTUICalculator() (string, error) {
+	data := g.prepareTUITemplateData()
+	return g.renderTUITemplate(data)
+}
+
+// prepareTUITemplateData prepares data for terminal UI template rendering
+func (g *TUIGenerator) prepareTUITemplateData() TemplateData {
+	return g.core.styleTemplateData([]string{
+		`try:
+    import curses
+except ImportError:
+    curses = None`,
+	}, g.generateTUIMainContent())
+}
+
+// tuiFunctionKey maps a function key to a calculator action
+type tuiFunctionKey struct {
+	number int
+	label  string
+	method string
+}
+
+// functionKeys returns the function-key shortcuts, mirroring the GUI buttons
+// for the enabled features
+func (g *TUIGenerator) functionKeys() []tuiFunctionKey {
+	keys := []tuiFunctionKey{{1, "Help", "show_help"}}
+
+	if g.config.Features.Trigonometric {
+		keys = append(keys, tuiFunctionKey{2, "Deg/Rad", "toggle_angle_unit"})
+	}
+
+	if g.config.Features.Memory {
+		keys = append(keys,
+			tuiFunctionKey{3, "MS", "memory_store"},
+			tuiFunctionKey{4, "MR", "memory_recall"},
+			tuiFunctionKey{5, "M+", "memory_add"},
+			tuiFunctionKey{6, "M-", "memory_subtract"},
+			tuiFunctionKey{7, "MC", "memory_clear"},
+		)
+	}
+
+	keys = append(keys,
+		tuiFunctionKey{8, "Ans", "insert_answer"},
+		tuiFunctionKey{9, "Clear", "clear"},
+		tuiFunctionKey{10, "Quit", "quit"},
+	)
+
+	return keys
+}
+
+// tuiColors returns curses foreground/background colour names for each
+// screen element, mapping the theme onto the eight standard curses colours.
+// Results, errors and the sidebar sit on the window background, so they use
+// their role colour as text.
+func (g *TUIGenerator) tuiColors() [][3]string {
+	colors := g.config.UI.ResolveTheme().Colors
+	window := cursesColor(colors.Window.Background)
+	text := cursesColor(colors.Window.Foreground)
+
+	// Errors must stand out from normal text even when the theme draws them
+	// as plain text on a coloured background
+	errorColor := cursesColor(colors.Error.Foreground)
+	if errorColor == text || errorColor == window {
+		errorColor = cursesColor(colors.Error.Background)
+	}
+
+	return [][3]string{
+		{"normal", text, window},
+		{"title", cursesColor(colors.Functions.Foreground), cursesColor(colors.Functions.Background)},
+		{"result", cursesColor(colors.Operators.Background), window},
+		{"error", errorColor, window},
+		{"sidebar", cursesColor(colors.Memory.Background), window},
+		{"keys", cursesColor(colors.Operators.Foreground), cursesColor(colors.Operators.Background)},
+	}
+}
+
+// generateTUIMainContent creates the curses application class
+func (g *TUIGenerator) generateTUIMainContent() string {
+	var content strings.Builder
+
+	content.WriteString("# Theme colours: element -> (foreground, background) curses colour names\nTHEME_COLORS = {\n")
+	for _, color := range g.tuiColors() {
+		content.WriteString(fmt.Sprintf("    %q: (%q, %q),\n", color[0], color[1], color[2]))
+	}
+	content.WriteString("}\n\n# Function keys: (number, label, method)\nFUNCTION_KEYS = [\n")
+	for _, key := range g.functionKeys() {
+		content.WriteString(fmt.Sprintf("    (%d, %q, %q),\n", key.number, key.label, key.method))
+	}
+	content.WriteString("]\n")
+
+	content.WriteString(`
+
+class CalculatorTUI:
+    """Full-screen terminal calculator"""
+    SIDEBAR_WIDTH = 28
+
+    def __init__(self, screen):
+        self.screen = screen
+        self.core = CalculatorCore()
+        self.expression = ""
+        self.cursor = 0
+        self.lines = []
+        self.inputs = []
+        self.recall_index = None
+        self.status = "F1 for help, F10 to quit"
+        self.running = True
+        self.setup_colors()
+
+    def setup_colors(self):
+        """Map theme colours to curses attributes, falling back to monochrome"""
+        self.colors = {}
+        if curses.has_colors():
+            curses.start_color()
+            for index, (name, (foreground, background)) in enumerate(THEME_COLORS.items(), start=1):
+                curses.init_pair(index, getattr(curses, foreground), getattr(curses, background))
+                self.colors[name] = curses.color_pair(index)
+            self.screen.bkgd(" ", self.colors["normal"])
+        else:
+            self.colors = {
+                "title": curses.A_REVERSE,
+                "result": curses.A_BOLD,
+                "error": curses.A_BOLD | curses.A_UNDERLINE,
+                "keys": curses.A_REVERSE,
+            }
+
+    def color(self, name):
+        """Attribute for a screen element"""
+        return self.colors.get(name, curses.A_NORMAL)
+
+    def put(self, y, x, text, attr=0, width=None):
+        """Write text, clipped to the window"""
+        height, screen_width = self.screen.getmaxyx()
+        if y < 0 or y >= height or x >= screen_width:
+            return
+        limit = screen_width - x if width is None else min(width, screen_width - x)
+        try:
+            self.screen.addstr(y, x, text[:max(limit, 0)], attr)
+        except curses.error:
+            pass  # Writing the bottom-right cell raises after drawing
+
+    def draw(self):
+        """Redraw the whole screen"""
+        self.screen.erase()
+        height, width = self.screen.getmaxyx()
+        if height < 8 or width < 40:
+            self.put(0, 0, "Terminal too small")
+            self.screen.refresh()
+            return
+
+        show_sidebar = width >= 80
+        main_width = width - self.SIDEBAR_WIDTH - 1 if show_sidebar else width
+
+        # Title bar
+        title = " " + ` + pythonString(g.config.ProjectName) + ` + f" | {self.core.angle_unit} "
+        self.put(0, 0, title.ljust(width), self.color("title"))
+
+        # Scrolling history pane
+        pane_height = height - 4
+        for row, (text, kind) in enumerate(self.lines[-pane_height:], start=1):
+            self.put(row, 1, text, self.color(kind), main_width - 2)
+
+        if show_sidebar:
+            self.draw_sidebar(main_width, pane_height)
+
+        # Expression line, status line and function key bar
+        prompt = "calc> "
+        self.put(height - 3, 0, prompt + self.expression, self.color("normal"), width)
+        self.put(height - 2, 0, self.status.ljust(width), self.color("normal"))
+        keys = "  ".join(f"F{number} {label}" for number, label, _ in FUNCTION_KEYS)
+        self.put(height - 1, 0, keys.ljust(width - 1), self.color("keys"))
+
+        self.screen.move(height - 3, min(len(prompt) + self.cursor, width - 1))
+        self.screen.refresh()
+
+    def draw_sidebar(self, x, pane_height):
+        """Draw the memory and variables sidebar"""
+        for row in range(1, pane_height + 1):
+            self.put(row, x, "│", self.color("sidebar"))
+        for row, (text, attr) in enumerate(self.sidebar_lines(pane_height), start=1):
+            self.put(row, x + 2, text, self.color("sidebar") | attr, self.SIDEBAR_WIDTH - 2)
+
+    def show(self, value):
+        """Format a value the way the history pane shows results"""
+        return format_localized(self.core.format_result(value))
+
+    def sidebar_lines(self, pane_height):
+        """Lines of the memory and variables sidebar, with their attributes"""
+        lines = []`)
+
+	if g.config.Features.Memory {
+		content.WriteString(`
+        lines.append(("Memory", curses.A_BOLD))
+        registers = self.core.memory.list()
+        if not registers:
+            lines.append(("  (empty)", 0))
+        for name, value in registers:
+            lines.append((f"  {name} = {self.show(value)}", 0))
+        lines.append(("", 0))`)
+	}
+
+	content.WriteString(`
+        lines.append(("Variables", curses.A_BOLD))
+        if not self.core.results:
+            lines.append(("  (none yet)", 0))
+        else:
+            lines.append((f"  ans = {self.show(self.core.results[-1])}", 0))
+        first = max(1, len(self.core.results) - pane_height + len(lines) + 1)
+        for number in range(len(self.core.results), first - 1, -1):
+            lines.append((f"  _{number} = {self.show(self.core.results[number - 1])}", 0))
+        return lines[:pane_height]
+
+    def run(self):
+        """Main input loop"""
+        self.screen.keypad(True)
+        try:
+            curses.curs_set(1)
+        except curses.error:
+            pass
+
+        while self.running:
+            self.draw()
+            try:
+                key = self.screen.get_wch()
+            except KeyboardInterrupt:
+                break
+            except curses.error:
+                continue
+            self.handle_key(key)
+
+    def handle_key(self, key):
+        """Dispatch a key press"""
+        for number, label, method in FUNCTION_KEYS:
+            if key == curses.KEY_F0 + number:
+                getattr(self, method)()
+                return
+
+        if key in ("\n", "\r", curses.KEY_ENTER):
+            self.evaluate()
+        elif key in ("\x7f", "\b", curses.KEY_BACKSPACE):
+            if self.cursor > 0:
+                self.expression = self.expression[:self.cursor - 1] + self.expression[self.cursor:]
+                self.cursor -= 1
+        elif key == curses.KEY_DC:
+            self.expression = self.expression[:self.cursor] + self.expression[self.cursor + 1:]
+        elif key == curses.KEY_LEFT:
+            self.cursor = max(0, self.cursor - 1)
+        elif key == curses.KEY_RIGHT:
+            self.cursor = min(len(self.expression), self.cursor + 1)
+        elif key == curses.KEY_HOME:
+            self.cursor = 0
+        elif key == curses.KEY_END:
+            self.cursor = len(self.expression)
+        elif key == curses.KEY_UP:
+            self.recall(-1)
+        elif key == curses.KEY_DOWN:
+            self.recall(1)
+        elif key == "\x1b":
+            self.clear()
+        elif key == "\x04":
+            self.quit()
+        elif isinstance(key, str) and key.isprintable():
+            self.insert(key)
+
+    def insert(self, text):
+        """Insert text at the cursor"""
+        self.expression = self.expression[:self.cursor] + text + self.expression[self.cursor:]
+        self.cursor += len(text)
+
+    def recall(self, direction):
+        """Step through previously entered expressions"""
+        if not self.inputs:
+            return
+        if self.recall_index is None:
+            self.recall_index = len(self.inputs)
+        self.recall_index = max(0, min(len(self.inputs), self.recall_index + direction))
+        self.expression = self.inputs[self.recall_index] if self.recall_index < len(self.inputs) else ""
+        self.cursor = len(self.expression)
+
+    def evaluate(self):
+        """Evaluate the expression line"""
+        user_input = self.expression.strip()
+        if not user_input:
+            return
+        if user_input.lower() in ("quit", "exit", "q"):
+            self.quit()
+            return
+
+        self.inputs.append(user_input)
+        self.recall_index = None
+        self.lines.append((user_input, "normal"))
+        try:
+            result = self.core.evaluate_expression(user_input)
+            number = self.core.remember_result(result)
+            self.lines.append((f"  = {self.show(result)}   [_{number}]", "result"))`)
+
+	if g.config.Features.History {
+		content.WriteString(`
+            self.core.history.add_entry(user_input, self.core.format_result(result))`)
+	}
+
+	content.WriteString(`
+            self.status = ""
+        except Exception as e:
+            self.lines.append((f"  {e}", "error"))
+            self.status = "Error"
+        self.expression = ""
+        self.cursor = 0
+
+    def current_value(self):
+        """Value of the expression line, or the last result when it is empty"""
+        if self.expression.strip():
+            return self.core.evaluate_expression(self.expression)
+        if self.core.results:
+            return self.core.results[-1]
+        raise ValueError("Nothing to use: enter an expression first")
+
+    def show_help(self):
+        """List the keyboard shortcuts in the history pane"""
+        self.lines.append(("Keys:", "result"))
+        for number, label, _ in FUNCTION_KEYS:
+            self.lines.append((f"  F{number:<3} {label}", "normal"))
+        self.lines.append(("  Enter evaluate, Esc clear, Up/Down recall, Left/Right edit", "normal"))
+        self.lines.append(("  ans, _1, _2 ... refer to earlier results", "normal"))
+
+    def insert_answer(self):
+        """Insert a reference to the last result"""
+        if self.core.results:
+            self.insert("ans")
+
+    def clear(self):
+        """Clear the expression line"""
+        self.expression = ""
+        self.cursor = 0
+        self.status = ""
+
+    def quit(self):
+        """Leave the calculator"""
+        self.running = False
+`)
+
+	if g.config.Features.Trigonometric {
+		content.WriteString(`
+    def toggle_angle_unit(self):
+        """Switch between degrees and radians"""
+        self.core.angle_unit = "radians" if self.core.angle_unit == "degrees" else "degrees"
+        self.status = f"Angles in {self.core.angle_unit}"
+`)
+	}
+
+	if g.config.Features.Memory {
+		content.WriteString(`
+    def memory_operation(self, operation):
+        """Apply a memory operation to the current value"""
+        try:
+            self.status = operation(self.current_value())
+            self.expression = ""
+            self.cursor = 0
+        except ValueError as e:
+            self.status = str(e)
+
+    def memory_store(self):
+        """Store the current value in memory (MS)"""
+        self.memory_operation(self.core.memory.store)
+
+    def memory_add(self):
+        """Add the current value to memory (M+)"""
+        self.memory_operation(self.core.memory.add)
+
+    def memory_subtract(self):
+        """Subtract the current value from memory (M-)"""
+        self.memory_operation(self.core.memory.subtract)
+
+    def memory_recall(self):
+        """Insert the memory register into the expression (MR)"""
+        self.insert(Memory.DEFAULT_REGISTER)
+
+    def memory_clear(self):
+        """Clear all memory registers (MC)"""
+        self.status = self.core.memory.clear()
+`)
+	}
+
+	content.WriteString(`
+
+def main():
+    """Main application entry point"""
+    if curses is None:
+        print("The curses module is not available. On Windows install it with: pip install windows-curses")
+        sys.exit(1)
+    curses.wrapper(lambda screen: CalculatorTUI(screen).run())
+
+if __name__ == "__main__":
+    main()`)
+
+	return content.String()
+}
+
+// renderTUITemplate renders the terminal UI calculator template with the given data
+func (g *TUIGenerator) renderTUITemplate(data TemplateData) (string, error) {
+	return renderScript("tui_calculator", data)
+}
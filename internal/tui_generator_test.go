@@ -0,0 +1,178 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTUISidebarFormatsValues(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "tui"
+	config.UI.Locale = "de-DE"
+	config.UI.Precision = 2
+	config.Features.Memory = true
+	source, err := NewTUIGenerator(config).GenerateTUICalculator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The sidebar shows memory and variables like the history pane shows
+	// results: rounded to the precision, in the locale's notation
+	program := `import json, sys
+namespace = {"__name__": "calc_tui"}
+exec(sys.stdin.read(), namespace)
+TUI = namespace["CalculatorTUI"]
+TUI.setup_colors = lambda self: None
+tui = TUI(None)
+tui.expression = "1234,5678"
+tui.evaluate()
+tui.core.memory.store(2 / 3)
+print(json.dumps({"pane": tui.lines[-1][0], "sidebar": [text for text, _ in tui.sidebar_lines(20)]}))`
+	out, err := runPython(t, program, []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := map[string]any{
+		"pane":    "  = 1.234,57   [_1]",
+		"sidebar": []any{"Memory", "  M = 0,67", "", "Variables", "  ans = 1.234,57", "  _1 = 1.234,57"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TUI shows %v, want %v", got, want)
+	}
+}
+
+func TestCursesColor(t *testing.T) {
+	tests := map[string]string{
+		"#000000": "COLOR_BLACK",
+		"#ffffff": "COLOR_WHITE",
+		"#d32f2f": "COLOR_RED",
+		"#2e7d32": "COLOR_BLACK",
+		"#4caf50": "COLOR_GREEN",
+		"#0d47a1": "COLOR_BLUE",
+		"#ffeb3b": "COLOR_YELLOW",
+		"#00bcd4": "COLOR_CYAN",
+		"#e040fb": "COLOR_MAGENTA",
+	}
+	for hex, want := range tests {
+		if got := cursesColor(hex); got != want {
+			t.Errorf("cursesColor(%s) = %s, want %s", hex, got, want)
+		}
+	}
+}
+
+func TestTUIThemeColors(t *testing.T) {
+	for _, theme := range ThemeNames() {
+		t.Run(theme, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.UI.Style = "tui"
+			config.UI.Theme = theme
+			generator := NewTUIGenerator(config)
+
+			colors := make(map[string][2]string)
+			for _, color := range generator.tuiColors() {
+				colors[color[0]] = [2]string{color[1], color[2]}
+			}
+			for _, name := range []string{"normal", "title", "result", "error", "sidebar", "keys"} {
+				if _, ok := colors[name]; !ok {
+					t.Errorf("no colour pair for %s", name)
+				}
+			}
+			// Errors stay readable against the window and distinct from normal text
+			normal, errors := colors["normal"], colors["error"]
+			if errors[0] == normal[0] || errors[0] == normal[1] {
+				t.Errorf("error colour %s blends with normal text %v", errors[0], normal)
+			}
+
+			source, err := generator.GenerateTUICalculator()
+			if err != nil {
+				t.Fatal(err)
+			}
+			line := fmt.Sprintf("    %q: (%q, %q),\n", "normal", normal[0], normal[1])
+			if !strings.Contains(source, "THEME_COLORS = {\n"+line) {
+				t.Errorf("generated THEME_COLORS does not start with %q", line)
+			}
+		})
+	}
+}
+
+func TestTUIKeys(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "tui"
+	config.Features.Memory = true
+	config.Features.Trigonometric = true
+	source, err := NewTUIGenerator(config).GenerateTUICalculator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys are fed to handle_key as curses delivers them; the monochrome
+	// fallback is checked with has_colors reporting no colour support
+	program := `import curses, json, sys
+namespace = {"__name__": "calc_tui"}
+exec(sys.stdin.read(), namespace)
+TUI = namespace["CalculatorTUI"]
+setup_colors = TUI.setup_colors
+TUI.setup_colors = lambda self: None
+tui = TUI(None)
+steps = []
+def press(name, *keys):
+    for key in keys:
+        tui.handle_key(key)
+    steps.append([name, tui.expression, tui.cursor, tui.status])
+press("type", "1", "+", "2")
+press("home", curses.KEY_HOME, "3")
+press("delete", curses.KEY_DC)
+press("end", curses.KEY_END, "0")
+press("enter", "\n")
+steps.append(["result", tui.lines[-1][0]])
+press("F3 memory store", "4", curses.KEY_F3)
+press("F4 memory recall", curses.KEY_F4, "*", "2", "\n")
+steps.append(["result", tui.lines[-1][0]])
+press("F8 ans", curses.KEY_F8)
+press("up", curses.KEY_UP)
+press("F2 angles", curses.KEY_F2)
+press("F9 clear", curses.KEY_F9)
+curses.has_colors = lambda: False
+setup_colors(tui)
+fallback = {name: tui.color(name) for name in ("title", "error", "sidebar")}
+print(json.dumps({"steps": steps, "fallback": fallback == {
+    "title": curses.A_REVERSE, "error": curses.A_BOLD | curses.A_UNDERLINE, "sidebar": curses.A_NORMAL}}))`
+	out, err := runPython(t, program, []byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := map[string]any{
+		"steps": []any{
+			[]any{"type", "1+2", 3.0, "F1 for help, F10 to quit"},
+			[]any{"home", "31+2", 1.0, "F1 for help, F10 to quit"},
+			[]any{"delete", "3+2", 1.0, "F1 for help, F10 to quit"},
+			[]any{"end", "3+20", 4.0, "F1 for help, F10 to quit"},
+			[]any{"enter", "", 0.0, ""},
+			[]any{"result", "  = 23   [_1]"},
+			[]any{"F3 memory store", "", 0.0, "Stored 4 in M"},
+			[]any{"F4 memory recall", "", 0.0, ""},
+			[]any{"result", "  = 8   [_2]"},
+			[]any{"F8 ans", "ans", 3.0, ""},
+			[]any{"up", "M*2", 3.0, ""},
+			[]any{"F2 angles", "M*2", 3.0, "Angles in radians"},
+			[]any{"F9 clear", "", 0.0, ""},
+		},
+		"fallback": true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TUI key handling = %v\nwant %v", got, want)
+	}
+}
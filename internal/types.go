@@ -19,6 +19,12 @@ type CalculatorConfig struct {
 	Libraries   Libraries      `json:"libraries"`
 	Features    Features       `json:"features"`
 	UI          UIConfig       `json:"ui"`
+	Storage     StorageConfig  `json:"storage"`
+
+	Dependencies DependencyConfig `json:"dependencies"`
+
+	Layout  string `json:"layout"`  // "script" (one file) or "package" (an installable project)
+	License string `json:"license"` // package license: "MIT", "BSD-3-Clause", "Apache-2.0" or "none"
 }
 
 // Libraries configuration for Python dependencies
@@ -59,12 +65,50 @@ type Features struct {
 
 // UIConfig configuration for user interface options
 type UIConfig struct {
-	Style      string `json:"style"` // "cli", "gui", "web"
-	Theme      string `json:"theme"` // "light", "dark", "colorful"
+	Style      string `json:"style"`  // "cli", "gui", "web", "tui", "notebook", "api"
+	Theme      string `json:"theme"`  // "light", "dark", "colorful", "high-contrast"
+	Keypad     string `json:"keypad"` // GUI keypad: "basic", "scientific", "programmer", "statistics"; defaults to the calculator type
 	ShowHelp   bool   `json:"show_help"`
 	ShowBanner bool   `json:"show_banner"`
 	Precision  int    `json:"precision"`  // decimal places
 	AngleUnit  string `json:"angle_unit"` // "degrees", "radians"
+	Locale     string `json:"locale"`     // messages and number format: "en-US", "de-DE", "fr-FR"
+
+	OutputFormat string `json:"output_format"` // "text", "json"
+
+	// GUI window sizes are pixels at 96 DPI and grow with the display's DPI;
+	// zero sizes are derived from the keypad
+	WindowWidth     int     `json:"window_width"`
+	WindowHeight    int     `json:"window_height"`
+	MinWindowWidth  int     `json:"min_window_width"`
+	MinWindowHeight int     `json:"min_window_height"`
+	Resizable       bool    `json:"resizable"`
+	FontScale       float64 `json:"font_scale"`   // multiplies the theme's font sizes
+	DisplayFont     string  `json:"display_font"` // overrides the theme's display font family
+
+	Keymap   []KeyBinding `json:"keymap,omitempty"` // GUI shortcuts added to or replacing the defaults
+	Announce string       `json:"announce"`         // GUI announce mode for screen readers: "" (off), "stdout" or a log file path
+
+	CustomTheme  *Theme        `json:"custom_theme,omitempty"`  // loaded from --theme-file, overrides Theme
+	CustomKeypad *KeypadLayout `json:"custom_keypad,omitempty"` // loaded from --keypad-file, overrides Keypad
+}
+
+// StorageConfig controls what generated calculators persist between sessions
+type StorageConfig struct {
+	PersistMemory bool   `json:"persist_memory"`
+	MemoryFile    string `json:"memory_file"` // defaults to ~/.<project>_memory.json
+
+	PersistHistory    bool   `json:"persist_history"`
+	HistoryFile       string `json:"history_file"` // defaults to ~/.<project>_history.json
+	HistoryMaxEntries int    `json:"history_max_entries"`
+}
+
+// DependencyConfig controls the requirements files written next to the
+// calculator
+type DependencyConfig struct {
+	PythonVersion string `json:"python_version"` // target Python such as "3.12"; empty keeps broad lower bounds
+	Pin           bool   `json:"pin"`            // exact versions in requirements.txt
+	Constraints   bool   `json:"constraints"`    // also write constraints.txt with exact versions
 }
 
 // TemplateData holds data for template rendering
@@ -75,6 +119,8 @@ type TemplateData struct {
 	MainContent string
 	Version     string
 	Timestamp   string
+	Summary     string // paragraph after the description saying what kind of module it is
+	Shebang     bool   // whether the module is a script run directly
 }
 
 // ValidationError represents a configuration validation error
@@ -130,7 +176,23 @@ func GetDefaultConfig() CalculatorConfig {
 			ShowBanner: true,
 			Precision:  10,
 			AngleUnit:  "degrees",
+			Locale:     DefaultLocale,
+
+			OutputFormat: "text",
+
+			Resizable: true,
+			FontScale: 1.0,
+		},
+		Storage: StorageConfig{
+			PersistMemory: false,
+			MemoryFile:    "",
+
+			PersistHistory:    true,
+			HistoryFile:       "",
+			HistoryMaxEntries: 100,
 		},
+		Layout:  "script",
+		License: "MIT",
 	}
 }
 
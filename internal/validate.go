@@ -0,0 +1,243 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Severity tells whether a validation issue stops generation
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // the configuration cannot be generated
+	SeverityWarning Severity = "warning" // the configuration works but is probably not what was meant
+)
+
+// Issue is one problem found in a configuration
+type Issue struct {
+	Field    string // path of the setting, such as "ui.style"
+	Severity Severity
+	Message  string
+	Fix      string // suggested fix, empty when there is no obvious one
+}
+
+func (i Issue) Error() string {
+	return i.Field + ": " + i.Message
+}
+
+// ValidationErrors is the error of a configuration with several errors
+type ValidationErrors []Issue
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, issue := range e {
+		messages[i] = issue.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// styles lists the supported UI styles
+var styles = []string{"cli", "gui", "web", "tui", "notebook", "api"}
+
+// ValidateConfig checks a configuration and returns every issue found, in
+// the order of the configuration's fields
+func ValidateConfig(config CalculatorConfig) []Issue {
+	var issues []Issue
+	add := func(severity Severity, field, message, fix string) {
+		issues = append(issues, Issue{Field: field, Severity: severity, Message: message, Fix: fix})
+	}
+	nested := func(err error, from, to string) {
+		var validation ValidationError
+		if errors.As(err, &validation) {
+			add(SeverityError, to+strings.TrimPrefix(validation.Field, from), validation.Message, "")
+		} else {
+			add(SeverityError, strings.TrimSuffix(to, "."), err.Error(), "")
+		}
+	}
+	ui := config.UI
+
+	// Project information
+	if config.OutputFile == "" {
+		add(SeverityError, "output_file", "output file cannot be empty", "set --output, for example calculator.py")
+	}
+	if config.ProjectName == "" {
+		add(SeverityError, "project_name", "project name cannot be empty", "set --name")
+	}
+
+	// Libraries the features need, and libraries nothing needs
+	enabled := make(map[string]bool)
+	for _, library := range libraryFields(&config.Libraries) {
+		enabled[library.name] = *library.field
+	}
+	used := map[string]bool{standardLibrary: true}
+	for _, feature := range featureFields(&config.Features) {
+		if !*feature.field {
+			continue
+		}
+		for _, library := range featureLibraries[feature.name] {
+			used[library] = true
+			if !enabled[library] {
+				add(SeverityError, "features."+feature.name,
+					fmt.Sprintf("needs the %s library, which is disabled", library),
+					fmt.Sprintf("enable libraries.use_%s (--libraries %s) or turn the feature off", library, library))
+			}
+		}
+	}
+	for _, feature := range featureFields(&config.Features) {
+		if *feature.field && !featureGeneratesCode(config, feature.name) {
+			add(SeverityWarning, "features."+feature.name, "generates no code for this configuration, it has no effect",
+				"turn the feature off")
+		}
+	}
+	for _, library := range libraryFields(&config.Libraries) {
+		if *library.field && !used[library.name] {
+			add(SeverityWarning, "libraries.use_"+library.name, "no enabled feature uses it, it only adds an import and an install",
+				fmt.Sprintf("remove %s from --libraries", library.name))
+		}
+	}
+
+	// Interface
+	switch ui.Style {
+	case "", "cli", "gui", "web", "tui", "api":
+	case "notebook":
+		module := strings.TrimSuffix(filepath.Base(config.OutputFile), ".py")
+		if !strings.HasSuffix(config.OutputFile, ".py") || !isPythonIdentifier(module) {
+			add(SeverityError, "output_file", "notebook extensions must be written to <module>.py where <module> is a valid Python module name",
+				"use a name such as calc_magic.py")
+		}
+	default:
+		add(SeverityError, "ui.style", fmt.Sprintf("unknown style %q", ui.Style), "use one of "+strings.Join(styles, ", "))
+	}
+
+	if ui.CustomTheme != nil {
+		if err := ui.CustomTheme.Validate(); err != nil {
+			nested(err, "theme.", "ui.custom_theme.")
+		}
+	} else if _, ok := BuiltinTheme(ui.Theme); !ok && ui.Theme != "" {
+		add(SeverityError, "ui.theme", fmt.Sprintf("unknown theme %q", ui.Theme), "use one of "+strings.Join(ThemeNames(), ", ")+", or --theme-file")
+	}
+
+	if ui.CustomKeypad == nil && ui.Keypad != "" {
+		if _, ok := BuiltinKeypad(ui.Keypad); !ok {
+			add(SeverityError, "ui.keypad", fmt.Sprintf("unknown keypad %q", ui.Keypad), "use one of "+strings.Join(KeypadNames(), ", ")+", or --keypad-file")
+		}
+	}
+	if ui.Style == "gui" {
+		if _, _, _, err := compileKeypad(config); err != nil {
+			nested(err, "keypad", "ui.custom_keypad")
+		}
+		// A keypad picked by name loses the buttons of disabled features
+		if layout, ok := BuiltinKeypad(ui.Keypad); ok && ui.CustomKeypad == nil {
+			features, dropped := layout.droppedButtons(config)
+			for _, feature := range features {
+				add(SeverityWarning, "ui.keypad",
+					fmt.Sprintf("the %s keypad drops %d buttons (%s) because the %s feature is off",
+						layout.Name, len(dropped[feature]), strings.Join(dropped[feature], " "), feature),
+					fmt.Sprintf("add %s to --features or choose another --keypad", feature))
+			}
+		}
+		if _, err := resolveKeymap(config); err != nil {
+			nested(err, "keymap", "ui.keymap")
+		}
+	} else {
+		gui := []struct {
+			field string
+			set   bool
+		}{
+			{"ui.keypad", ui.Keypad != "" || ui.CustomKeypad != nil},
+			{"ui.keymap", len(ui.Keymap) > 0},
+			{"ui.window_width", ui.WindowWidth > 0 || ui.WindowHeight > 0},
+			{"ui.min_window_width", ui.MinWindowWidth > 0 || ui.MinWindowHeight > 0},
+			{"ui.font_scale", ui.FontScale != 0 && ui.FontScale != 1},
+			{"ui.display_font", ui.DisplayFont != ""},
+			{"ui.announce", ui.Announce != ""},
+		}
+		for _, setting := range gui {
+			if setting.set {
+				add(SeverityWarning, setting.field, "only applies to the gui style", "remove it or use --style gui")
+			}
+		}
+	}
+
+	// Display
+	if ui.Precision < 1 || ui.Precision > 20 {
+		add(SeverityError, "ui.precision", "precision must be between 1 and 20", "use --precision 10")
+	} else if ui.Precision > 15 {
+		add(SeverityWarning, "ui.precision", "floating point numbers only carry about 15 significant digits", "use a precision of 15 or less")
+	}
+	switch ui.AngleUnit {
+	case "", "degrees", "radians":
+	default:
+		add(SeverityError, "ui.angle_unit", fmt.Sprintf("unknown angle unit %q", ui.AngleUnit), "use degrees or radians")
+	}
+	if _, ok := LookupLocale(ui.Locale); !ok && ui.Locale != "" {
+		add(SeverityError, "ui.locale", fmt.Sprintf("unknown locale %q", ui.Locale), "use one of "+strings.Join(LocaleNames(), ", "))
+	}
+	if ui.OutputFormat != "" && ui.OutputFormat != "text" && ui.OutputFormat != "json" {
+		add(SeverityError, "ui.output_format", fmt.Sprintf("unknown output format %q", ui.OutputFormat), "use text or json")
+	}
+
+	// GUI window
+	if ui.WindowWidth < 0 || ui.WindowHeight < 0 || ui.MinWindowWidth < 0 || ui.MinWindowHeight < 0 {
+		add(SeverityError, "ui.window_width", "window sizes cannot be negative", "use WIDTHxHEIGHT with positive numbers")
+	}
+	if (ui.WindowWidth > 0 && ui.MinWindowWidth > ui.WindowWidth) || (ui.WindowHeight > 0 && ui.MinWindowHeight > ui.WindowHeight) {
+		add(SeverityError, "ui.min_window_width", "minimum window size cannot exceed the window size", "lower --min-window-size or raise --window-size")
+	}
+	if ui.FontScale != 0 && (ui.FontScale < 0.5 || ui.FontScale > 4) {
+		add(SeverityError, "ui.font_scale", "font scale must be between 0.5 and 4", "use --font-scale 1")
+	}
+
+	// Storage
+	storage := config.Storage
+	if storage.HistoryMaxEntries < 0 {
+		add(SeverityError, "storage.history_max_entries", "history size cannot be negative", "use --history-size 100")
+	}
+	if !config.Features.Memory && (storage.PersistMemory || storage.MemoryFile != "") {
+		add(SeverityWarning, "storage.persist_memory", "memory storage is set but the memory feature is off", "enable --memory or remove the memory storage settings")
+	}
+	if !config.Features.History && storage.HistoryFile != "" {
+		add(SeverityWarning, "storage.history_file", "a history file is set but the history feature is off", "enable --history or remove --history-file")
+	}
+
+	// Dependencies
+	validateDependencies(config, add)
+
+	// Layout
+	switch config.Layout {
+	case "", "script":
+	case "package":
+		if ui.Style != "" && ui.Style != "cli" && ui.Style != "gui" {
+			add(SeverityError, "layout", fmt.Sprintf("the package layout holds the %s styles, not %s", strings.Join(packageStyles, " and "), ui.Style),
+				"use --style cli or gui, or --layout script")
+		}
+	default:
+		add(SeverityError, "layout", fmt.Sprintf("unknown layout %q", config.Layout), "use script or package")
+	}
+	if config.License != "" && !slices.Contains(licenses, config.License) {
+		add(SeverityError, "license", fmt.Sprintf("unknown license %q", config.License), "use one of "+strings.Join(licenses, ", "))
+	}
+
+	return issues
+}
+
+// validationError returns the errors among issues as an error, or nil when
+// there are only warnings
+func validationError(issues []Issue) error {
+	var errs ValidationErrors
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue)
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return ValidationError{Field: errs[0].Field, Message: errs[0].Message}
+	}
+	return errs
+}
@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(config *CalculatorConfig)
+		want   []Issue // field and severity of each expected issue, in order
+	}{
+		{"defaults", func(config *CalculatorConfig) {}, nil},
+		{
+			name: "unknown names",
+			change: func(config *CalculatorConfig) {
+				config.UI.Style = "holo"
+				config.UI.Theme = "purple"
+				config.UI.AngleUnit = "gradians"
+			},
+			want: []Issue{
+				{Field: "ui.style", Severity: SeverityError},
+				{Field: "ui.theme", Severity: SeverityError},
+				{Field: "ui.angle_unit", Severity: SeverityError},
+			},
+		},
+		{
+			name: "feature without its library",
+			change: func(config *CalculatorConfig) {
+				config.Features.Statistical = true
+				config.Features.Calculus = true
+			},
+			want: []Issue{
+				{Field: "features.statistical", Severity: SeverityError},
+				{Field: "features.calculus", Severity: SeverityError},
+			},
+		},
+		{
+			name: "features without generated code",
+			change: func(config *CalculatorConfig) {
+				config.Features.UnitConversion = true
+				config.Features.Graphing = true
+				config.Features.Programming = true
+			},
+			want: []Issue{
+				{Field: "features.unit_conversion", Severity: SeverityWarning},
+				{Field: "features.graphing", Severity: SeverityWarning},
+				{Field: "features.programming", Severity: SeverityWarning},
+			},
+		},
+		{
+			name:   "quotes in the project name",
+			change: func(config *CalculatorConfig) { config.ProjectName = `My "Calc" {x}` },
+		},
+		{
+			name: "settings without effect",
+			change: func(config *CalculatorConfig) {
+				config.UI.Keypad = "programmer"
+				config.UI.Precision = 18
+				config.Storage.PersistMemory = true
+			},
+			want: []Issue{
+				{Field: "ui.keypad", Severity: SeverityWarning},
+				{Field: "ui.precision", Severity: SeverityWarning},
+				{Field: "storage.persist_memory", Severity: SeverityWarning},
+			},
+		},
+		{
+			name: "gui keymap",
+			change: func(config *CalculatorConfig) {
+				config.UI.Style = "gui"
+				config.UI.Keymap = []KeyBinding{{Keys: "F9", Action: "memory_store"}}
+			},
+			want: []Issue{{Field: "ui.keymap[0]", Severity: SeverityError}},
+		},
+		{
+			name: "shortcut on a hex digit",
+			change: func(config *CalculatorConfig) {
+				config.UI.Style = "gui"
+				config.Features.Programming = true
+				config.UI.Keymap = []KeyBinding{{Keys: "c", Action: "clear"}}
+			},
+			want: []Issue{{Field: "ui.keymap[0]", Severity: SeverityError}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.change(&config)
+			issues := ValidateConfig(config)
+			if len(issues) != len(tt.want) {
+				t.Fatalf("ValidateConfig() = %v, want %d issues", issues, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if issues[i].Field != want.Field || issues[i].Severity != want.Severity {
+					t.Errorf("issue %d = %s %s, want %s %s", i, issues[i].Severity, issues[i].Field, want.Severity, want.Field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryError(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "holo"
+	config.UI.Precision = 0
+
+	var errs ValidationErrors
+	if err := NewGenerator(config).Validate(); !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("Validate() = %v, want both errors", err)
+	}
+}
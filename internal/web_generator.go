@@ -0,0 +1,463 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WebGenerator handles the generation of browser-based Python calculators.
+// The generated script serves a single HTML page and a JSON API using only
+// the Python standard library http.server module.
+type WebGenerator struct {
+	config CalculatorConfig
+	core   *Generator
+}
+
+// NewWebGenerator creates a new web calculator generator instance
+func NewWebGenerator(config CalculatorConfig) *WebGenerator {
+	return &WebGenerator{config: config, core: NewGenerator(config)}
+}
+
+// GenerateWebCalculator creates a self-hosted browser calculator
+func (g *WebGenerator) GenerateWebCalculator() (string, error) {
+	data := g.prepareWebTemplateData()
+	return g.renderWebTemplate(data)
+}
+
+// prepareWebTemplateData prepares data for web template rendering
+func (g *WebGenerator) prepareWebTemplateData() TemplateData {
+	return g.core.styleTemplateData([]string{
+		"import argparse",
+		"import multiprocessing",
+		"import pickle",
+		"import threading",
+		"from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer",
+	}, g.generateWebMainContent())
+}
+
+// webButton describes a keypad button on the generated page
+type webButton struct {
+	label  string
+	insert string // text inserted into the expression
+	action string // JavaScript action name when insert is empty
+	class  string
+}
+
+// generateKeypadRows returns the keypad rows for the enabled features
+func (g *WebGenerator) generateKeypadRows() [][]webButton {
+	digit := func(d string) webButton { return webButton{label: d, insert: d} }
+	op := func(label, insert string) webButton { return webButton{label: label, insert: insert, class: "op"} }
+	fn := func(name string) webButton { return webButton{label: name, insert: name + "(", class: "fn"} }
+
+	var rows [][]webButton
+
+	if g.config.Features.Memory {
+		rows = append(rows, []webButton{
+			{label: "MS", action: "memory('store')", class: "mem"},
+			{label: "MR", action: "memory('recall')", class: "mem"},
+			{label: "M+", action: "memory('add')", class: "mem"},
+			{label: "M-", action: "memory('subtract')", class: "mem"},
+			{label: "MC", action: "memory('clear')", class: "mem"},
+		})
+	}
+
+	var functions []webButton
+	if g.config.Libraries.UseMath {
+		functions = append(functions, fn("sqrt"), op("π", "pi"))
+	}
+	if g.config.Features.Trigonometric {
+		functions = append(functions, fn("sin"), fn("cos"), fn("tan"))
+	}
+	if g.config.Features.Logarithmic {
+		functions = append(functions, fn("log"), fn("ln"))
+	}
+	if g.config.Features.Statistical && g.config.Libraries.UseNumpy {
+		functions = append(functions, fn("mean"), fn("median"), fn("std"), op("[", "["), op("]", "]"), op(",", ","))
+	}
+	for len(functions) > 0 {
+		n := 5
+		if len(functions) < n {
+			n = len(functions)
+		}
+		rows = append(rows, functions[:n])
+		functions = functions[n:]
+	}
+
+	rows = append(rows,
+		[]webButton{{label: "C", action: "clearAll()", class: "op"}, {label: "⌫", action: "backspace()", class: "op"}, op("(", "("), op(")", ")"), op("÷", "/")},
+		[]webButton{digit("7"), digit("8"), digit("9"), op("^", "^"), op("×", "*")},
+		[]webButton{digit("4"), digit("5"), digit("6"), op("%", "%"), op("−", "-")},
+		[]webButton{digit("1"), digit("2"), digit("3"), {label: "Ans", insert: "ans", class: "fn"}, op("+", "+")},
+		[]webButton{digit("0"), digit("00"), digit("."), {label: "=", action: "evaluate()", class: "eq"}},
+	)
+
+	return rows
+}
+
+// generateIndexHTML creates the single page served at /
+func (g *WebGenerator) generateIndexHTML() string {
+	theme := g.config.UI.ResolveTheme()
+	colors := theme.Colors
+
+	var page strings.Builder
+	page.WriteString(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>` + htmlText(g.config.ProjectName) + `</title>
+<style>
+  body { font: ` + cssFont(theme.Fonts.Buttons) + `; background: ` + colors.Window.Background + `; color: ` + colors.Window.Foreground + `; margin: 0; display: flex; justify-content: center; }
+  main { display: flex; gap: 16px; padding: ` + fmt.Sprintf("%dpx", theme.Padding.Window*2) + `; flex-wrap: wrap; }
+  .calculator, .side { background: ` + colors.Window.Background + `; border: 1px solid rgba(128,128,128,0.3); border-radius: 8px; padding: ` + fmt.Sprintf("%dpx", theme.Padding.Window+6) + `; box-shadow: 0 1px 4px rgba(0,0,0,0.2); }
+  .calculator { width: 340px; }
+  .side { width: 260px; }
+  #status { min-height: 1.2em; font-size: 0.9em; opacity: 0.8; }
+  #status.error { color: ` + colors.Error.Foreground + `; background: ` + colors.Error.Background + `; opacity: 1; }
+  #display { width: 100%; box-sizing: border-box; font: ` + cssFont(theme.Fonts.Display) + `; text-align: right; padding: 8px; margin: 6px 0 12px; color: ` + colors.Display.Foreground + `; background: ` + colors.Display.Background + `; border: 1px solid rgba(128,128,128,0.4); }
+  .row { display: flex; gap: 6px; margin-bottom: 6px; }
+  button { flex: 1; padding: ` + fmt.Sprintf("%dpx", theme.Padding.Button*2) + ` 0; font: inherit; border: 1px solid rgba(0,0,0,0.15); border-radius: 6px; color: ` + colors.Digits.Foreground + `; background: ` + colors.Digits.Background + `; cursor: pointer; }
+  button.op, button.eq { color: ` + colors.Operators.Foreground + `; background: ` + colors.Operators.Background + `; }
+  button.fn { color: ` + colors.Functions.Foreground + `; background: ` + colors.Functions.Background + `; }
+  button.mem { color: ` + colors.Memory.Foreground + `; background: ` + colors.Memory.Background + `; }
+  button.eq { flex: 2; font-weight: bold; }
+  button:hover { filter: brightness(1.1); }
+  ul { list-style: none; padding: 0; margin: 0; max-height: 320px; overflow-y: auto; }
+  li { padding: 4px 0; cursor: pointer; border-bottom: 1px solid rgba(128,128,128,0.2); }
+  h1 { font-size: 1.1em; margin: 0; }
+  h2 { font-size: 1em; margin: 12px 0 6px; }
+</style>
+</head>
+<body>
+<main>
+<section class="calculator">
+  <h1>` + htmlText(g.config.ProjectName) + `</h1>
+  <div id="status"></div>
+  <input id="display" autocomplete="off" autofocus>
+`)
+
+	for _, row := range g.generateKeypadRows() {
+		page.WriteString(`  <div class="row">`)
+		for _, button := range row {
+			onclick := button.action
+			if button.insert != "" {
+				onclick = "insert('" + button.insert + "')"
+			}
+			page.WriteString(`<button class="` + button.class + `" onclick="` + onclick + `">` + button.label + `</button>`)
+		}
+		page.WriteString("</div>\n")
+	}
+
+	page.WriteString(fmt.Sprintf("  <p>Precision: %d &middot; Angles: %s</p>\n</section>\n", g.config.UI.Precision, htmlText(g.config.UI.AngleUnit)))
+
+	if g.config.Features.Memory || g.config.Features.History {
+		page.WriteString(`<section class="side">
+`)
+		if g.config.Features.Memory {
+			page.WriteString(`  <h2>Memory</h2>
+  <ul id="memory"></ul>
+`)
+		}
+		if g.config.Features.History {
+			page.WriteString(`  <h2>History</h2>
+  <ul id="history"></ul>
+`)
+		}
+		page.WriteString(`</section>
+`)
+	}
+
+	page.WriteString(`</main>
+<script>
+const display = document.getElementById('display');
+const status = document.getElementById('status');
+
+function insert(text) {
+  const start = display.selectionStart ?? display.value.length;
+  const end = display.selectionEnd ?? display.value.length;
+  display.value = display.value.slice(0, start) + text + display.value.slice(end);
+  display.focus();
+  display.setSelectionRange(start + text.length, start + text.length);
+}
+
+function clearAll() { display.value = ''; showStatus(''); display.focus(); }
+function backspace() { display.value = display.value.slice(0, -1); display.focus(); }
+
+function showStatus(text, isError) {
+  status.textContent = text;
+  status.className = isError ? 'error' : '';
+}
+
+async function request(path, body) {
+  const options = body === undefined ? {} : {
+    method: 'POST', headers: {'Content-Type': 'application/json'}, body: JSON.stringify(body)
+  };
+  const response = await fetch(path, options);
+  return response.json();
+}
+
+async function evaluate() {
+  if (!display.value.trim()) return;
+  const record = await request('/evaluate', {expression: display.value});
+  if (record.error) {
+    showStatus(record.error, true);
+    return;
+  }
+  showStatus(record.expression + ' =');
+  display.value = record.formatted;
+  refresh();
+}
+`)
+
+	if g.config.Features.Memory {
+		page.WriteString(`
+async function memory(action) {
+  const body = {action: action};
+  if (display.value.trim() && action !== 'recall' && action !== 'clear') body.value = display.value;
+  const reply = await request('/memory', body);
+  if (reply.error) { showStatus(reply.error, true); return; }
+  if (action === 'recall') display.value = String(reply.value);
+  showStatus(reply.message || '');
+  renderMemory(reply.registers);
+}
+
+function renderMemory(registers) {
+  const list = document.getElementById('memory');
+  list.innerHTML = '';
+  for (const [name, value] of Object.entries(registers)) {
+    const item = document.createElement('li');
+    item.textContent = name + ' = ' + value;
+    item.onclick = () => insert(name);
+    list.appendChild(item);
+  }
+}
+`)
+	}
+
+	if g.config.Features.History {
+		page.WriteString(`
+function renderHistory(entries) {
+  const list = document.getElementById('history');
+  list.innerHTML = '';
+  for (const entry of entries.slice().reverse()) {
+    const item = document.createElement('li');
+    item.textContent = '#' + entry.index + ' ' + entry.expression + ' = ' + entry.result;
+    item.onclick = () => { display.value = entry.expression; display.focus(); };
+    list.appendChild(item);
+  }
+}
+`)
+	}
+
+	page.WriteString(`
+async function refresh() {
+`)
+	if g.config.Features.Memory {
+		page.WriteString(`  renderMemory(await request('/memory'));
+`)
+	}
+	if g.config.Features.History {
+		page.WriteString(`  renderHistory(await request('/history'));
+`)
+	}
+	page.WriteString(`}
+
+display.addEventListener('keydown', (event) => {
+  if (event.key === 'Enter') { event.preventDefault(); evaluate(); }
+  if (event.key === 'Escape') { clearAll(); }
+});
+refresh();
+</script>
+</body>
+</html>
+`)
+
+	return page.String()
+}
+
+// generateWebMainContent creates the HTTP request handler and entry point
+func (g *WebGenerator) generateWebMainContent() string {
+	var content strings.Builder
+
+	content.WriteString(`INDEX_HTML = r"""` + g.generateIndexHTML() + `"""
+
+
+# Seconds an evaluation may run before it is stopped
+DEFAULT_TIMEOUT = 5.0
+
+# Largest accepted request body in bytes
+MAX_REQUEST_BYTES = 64 * 1024
+
+
+` + generateEvaluationWorker() + `
+
+
+` + generateRequestErrorClass() + `
+
+
+class CalculatorRequestHandler(BaseHTTPRequestHandler):
+    """Serves the calculator page and its JSON API"""
+    calculator = None
+    worker = None
+    lock = threading.Lock()
+    evaluations = threading.Lock()
+    eval_timeout = DEFAULT_TIMEOUT
+    max_request_bytes = MAX_REQUEST_BYTES
+` + generateJSONHandlerMethods() + generateWorkerHandlerMethods() + `
+    def do_GET(self):
+        """Serve the page and read-only endpoints"""
+        if self.path == "/":
+            body = INDEX_HTML.encode("utf-8")
+            self.send_response(200)
+            self.send_header("Content-Type", "text/html; charset=utf-8")
+            self.send_header("Content-Length", str(len(body)))
+            self.end_headers()
+            self.wfile.write(body)`)
+
+	if g.config.Features.History {
+		content.WriteString(`
+        elif self.path == "/history":
+            with self.lock:
+                self.send_json(self.calculator.history.get_history())`)
+	}
+
+	if g.config.Features.Memory {
+		content.WriteString(`
+        elif self.path == "/memory":
+            with self.lock:
+                self.send_json(self.calculator.memory.registers)`)
+	}
+
+	content.WriteString(`
+        else:
+            self.send_json({"error": "Not found"}, 404)
+
+    def do_POST(self):
+        """Handle evaluation and memory requests"""
+        try:
+            body = self.read_json()
+            if self.path == "/evaluate":
+                self.send_json(self.evaluate_record(str(body.get("expression", ""))))`)
+
+	if g.config.Features.Memory {
+		content.WriteString(`
+            elif self.path == "/memory":
+                self.send_json(*self.memory_command(body))`)
+	}
+
+	content.WriteString(`
+            else:
+                self.send_json({"error": "Not found"}, 404)
+        except RequestError as e:
+            self.send_json({"error": str(e)}, e.status)
+
+    def evaluate_record(self, user_input):
+        """Evaluate input in the worker and describe the outcome as an evaluation record"""
+        deadline = time.monotonic() + self.eval_timeout
+        with self.lock:
+            expression = self.calculator.normalize_expression(user_input)
+        start = time.perf_counter()
+        self.wait_for_evaluations(deadline)
+        try:
+            kind, value = self.run_evaluation(user_input, deadline)
+            duration_ms = (time.perf_counter() - start) * 1000
+            if kind == "timeout":
+                error = f"Evaluation timed out after {self.eval_timeout} seconds"
+                return make_record(user_input, expression, None, None, None, error, duration_ms)
+            if kind == "error":
+                return make_record(user_input, expression, None, None, None, value, duration_ms)
+            with self.lock:
+                self.calculator.remember_result(value)
+                result = self.calculator.format_result(value)`)
+
+	if g.config.Features.History {
+		content.WriteString(`
+                self.calculator.history.add_entry(user_input, result)`)
+	}
+
+	content.WriteString(`
+            return make_record(user_input, expression, value, format_localized(result), None, None, duration_ms)
+        finally:
+            self.evaluations.release()
+`)
+
+	if g.config.Features.Memory {
+		content.WriteString(`
+    def memory_command(self, body):
+        """Apply a memory action; returns the response payload and status"""
+        memory = self.calculator.memory
+        action = str(body.get("action", ""))
+        name = str(body.get("name", Memory.DEFAULT_REGISTER))
+        try:
+            if action == "recall":
+                with self.lock:
+                    return {"value": memory.recall(name), "registers": memory.registers}, 200
+            if action == "clear":
+                with self.lock:
+                    message = memory.clear(body.get("name"))
+                    return {"message": message, "registers": memory.registers}, 200
+            if action not in ("store", "add", "subtract"):
+                return {"error": f"Unknown memory action: {action}"}, 400
+
+            if "value" in body:
+                value = self.memory_value(str(body["value"]))
+            elif self.calculator.results:
+                value = self.calculator.results[-1]
+            else:
+                return {"error": "No value given and no previous result"}, 400
+            with self.lock:
+                message = getattr(memory, action)(value, name)
+                return {"message": message, "registers": memory.registers}, 200
+        except ValueError as e:
+            return {"error": str(e)}, 400
+
+    def memory_value(self, user_input):
+        """Evaluate a value for a memory action in the worker"""
+        deadline = time.monotonic() + self.eval_timeout
+        self.wait_for_evaluations(deadline)
+        try:
+            kind, value = self.run_evaluation(user_input, deadline)
+        finally:
+            self.evaluations.release()
+        if kind == "timeout":
+            raise RequestError(504, f"Evaluation timed out after {self.eval_timeout} seconds")
+        if kind == "error":
+            raise ValueError(value)
+        return value
+`)
+	}
+
+	content.WriteString(`
+
+def main():
+    """Start the calculator web server"""
+    parser = argparse.ArgumentParser(description=` + pythonString(g.config.ProjectName) + `)
+    parser.add_argument("--host", default="127.0.0.1", help="address to listen on")
+    parser.add_argument("--port", type=int, default=8000, help="port to listen on")
+    parser.add_argument("--timeout", type=float, default=DEFAULT_TIMEOUT, help="evaluation timeout per request in seconds")
+    args = parser.parse_args()
+
+    CalculatorRequestHandler.calculator = CalculatorCore()
+    CalculatorRequestHandler.eval_timeout = args.timeout
+    CalculatorRequestHandler.worker = EvaluationWorker()
+    CalculatorRequestHandler.worker.start()
+    server = ThreadingHTTPServer((args.host, args.port), CalculatorRequestHandler)
+    print(f"Serving on http://{args.host}:{args.port} (Ctrl+C to stop)")
+    try:
+        server.serve_forever()
+    except KeyboardInterrupt:
+        print("\nGoodbye!")
+    finally:
+        server.server_close()
+        CalculatorRequestHandler.worker.stop()
+
+if __name__ == "__main__":
+    main()`)
+
+	return content.String()
+}
+
+// renderWebTemplate renders the web calculator template with the given data
+func (g *WebGenerator) renderWebTemplate(data TemplateData) (string, error) {
+	return renderScript("web_calculator", data)
+}
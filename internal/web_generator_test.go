@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWebEvaluationTimeout(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "web"
+	config.Features.Memory = true
+	config.Features.History = true
+	source, err := NewWebGenerator(config).GenerateWebCalculator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A huge power is stopped at the timeout without blocking reads, and
+	// the page keeps working after
+	program := `import json, threading, time, urllib.request
+from http.server import ThreadingHTTPServer
+import module
+
+Handler = module.CalculatorRequestHandler
+Handler.calculator = module.CalculatorCore()
+Handler.eval_timeout = 1
+Handler.worker = module.EvaluationWorker()
+Handler.worker.start()
+server = ThreadingHTTPServer(("127.0.0.1", 0), Handler)
+threading.Thread(target=server.serve_forever, daemon=True).start()
+url = "http://127.0.0.1:%d" % server.server_address[1]
+
+def call(path, body=None):
+    data = None if body is None else json.dumps(body).encode()
+    request = urllib.request.Request(url + path, data=data, headers={"Content-Type": "application/json"})
+    try:
+        with urllib.request.urlopen(request, timeout=10) as response:
+            return response.status, json.load(response)
+    except urllib.error.HTTPError as e:
+        return e.code, json.load(e)
+
+outcome = {}
+call("/evaluate", {"expression": "40+2"})
+slow = {}
+start = time.monotonic()
+worker = threading.Thread(target=lambda: slow.update(result=call("/evaluate", {"expression": "9**9**9"})))
+worker.start()
+time.sleep(0.2)
+read_start = time.monotonic()
+status, _ = call("/memory")
+outcome["read during evaluation"] = [status, time.monotonic() - read_start < 0.5]
+worker.join()
+outcome["huge power"] = [slow["result"][1]["error"] is not None, time.monotonic() - start < 3]
+status, record = call("/evaluate", {"expression": "ans + 2"})
+outcome["after timeout"] = [status, record["value"]]
+outcome["store"] = call("/memory", {"action": "store", "value": "ans * 2"})[1]["registers"]
+outcome["store timeout"] = call("/memory", {"action": "store", "value": "9**9**9"})[0]
+outcome["history"] = [entry["expression"] for entry in call("/history")[1]]
+server.shutdown()
+Handler.worker.stop()
+print(json.dumps(outcome))`
+	out, err := runPythonModule(t, source, program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := map[string]any{
+		"read during evaluation": []any{200.0, true},
+		"huge power":             []any{true, true},
+		"after timeout":          []any{200.0, 44.0},
+		"store":                  map[string]any{"M": 88.0},
+		"store timeout":          504.0,
+		"history":                []any{"40+2", "ans + 2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("outcome = %v, want %v", got, want)
+	}
+}
+
+func TestWebPageFollowsConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  func(config *CalculatorConfig)
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "basic",
+			want: []string{
+				`<button class="fn" onclick="insert('sqrt(')">sqrt</button>`,
+				`<button class="eq" onclick="evaluate()">=</button>`,
+				"<p>Precision: 10 &middot; Angles: degrees</p>",
+			},
+			notWant: []string{`insert('sin(')`, `<section class="side">`, "function memory(", "renderHistory"},
+		},
+		{
+			name: "scientific dark",
+			config: func(config *CalculatorConfig) {
+				config.Features.Trigonometric = true
+				config.Features.Logarithmic = true
+				config.Features.Memory = true
+				config.Features.History = true
+				config.UI.Theme = "dark"
+				config.UI.Precision = 4
+				config.UI.AngleUnit = "radians"
+			},
+			want: []string{
+				`insert('sin(')`, `insert('ln(')`,
+				`<button class="mem" onclick="memory('subtract')">M-</button>`,
+				`<ul id="memory"></ul>`, `<ul id="history"></ul>`,
+				"renderMemory(await request('/memory'));", "renderHistory(await request('/history'));",
+				"<p>Precision: 4 &middot; Angles: radians</p>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.UI.Style = "web"
+			if tt.config != nil {
+				tt.config(&config)
+			}
+			page := NewWebGenerator(config).generateIndexHTML()
+
+			// The page takes its colours from the configured theme
+			colors := config.UI.ResolveTheme().Colors
+			want := append(tt.want,
+				"background: "+colors.Window.Background,
+				"color: "+colors.Display.Foreground+"; background: "+colors.Display.Background,
+			)
+			for _, text := range want {
+				if !strings.Contains(page, text) {
+					t.Errorf("page is missing %q", text)
+				}
+			}
+			for _, text := range tt.notWant {
+				if strings.Contains(page, text) {
+					t.Errorf("page has %q", text)
+				}
+			}
+		})
+	}
+}
+
+func TestWebServesPageAndEvaluates(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.Style = "web"
+	config.Features.Trigonometric = true
+	config.UI.Precision = 4
+	source, err := NewWebGenerator(config).GenerateWebCalculator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program := `import json, threading, urllib.request
+from http.server import ThreadingHTTPServer
+import module
+
+Handler = module.CalculatorRequestHandler
+Handler.calculator = module.CalculatorCore()
+Handler.worker = module.EvaluationWorker()
+Handler.worker.start()
+server = ThreadingHTTPServer(("127.0.0.1", 0), Handler)
+threading.Thread(target=server.serve_forever, daemon=True).start()
+url = "http://127.0.0.1:%d" % server.server_address[1]
+
+def call(path, body=None):
+    data = None if body is None else json.dumps(body).encode()
+    request = urllib.request.Request(url + path, data=data, headers={"Content-Type": "application/json"})
+    try:
+        with urllib.request.urlopen(request, timeout=10) as response:
+            return response.status, response.headers["Content-Type"], response.read().decode()
+    except urllib.error.HTTPError as e:
+        return e.code, e.headers["Content-Type"], e.read().decode()
+
+outcome = {}
+status, content_type, page = call("/")
+outcome["page"] = [status, content_type, page.startswith("<!DOCTYPE html>")]
+for expression in ("1/3", "sin(90)", "1/0"):
+    record = json.loads(call("/evaluate", {"expression": expression})[2])
+    outcome[expression] = [record["formatted"], record["error"]]
+outcome["missing"] = call("/nope")[0]
+server.shutdown()
+Handler.worker.stop()
+print(json.dumps(outcome))`
+	out, err := runPythonModule(t, source, program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("Python printed %s", out)
+	}
+	want := map[string]any{
+		"page":    []any{200.0, "text/html; charset=utf-8", true},
+		"1/3":     []any{"0.3333", nil},
+		"sin(90)": []any{"1.0", nil},
+		"1/0":     []any{nil, "Cannot divide by zero"},
+		"missing": 404.0,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("server answered %v, want %v", got, want)
+	}
+}